@@ -0,0 +1,434 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: api/protos/entities/admin.proto
+
+package entities
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Программа кредитования в админском API
+type Program struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Code              string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`                                                        // код для выбора программы в запросе расчета
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                                        // отображаемое название
+	RateBps           int64                  `protobuf:"varint,3,opt,name=rate_bps,json=rateBps,proto3" json:"rate_bps,omitempty"`                                  // годовая ставка (для плавающей не задается)
+	RatePeriods       []*RatePeriod          `protobuf:"bytes,4,rep,name=rate_periods,json=ratePeriods,proto3" json:"rate_periods,omitempty"`                       // ступенчатая ставка в начале срока
+	RateType          string                 `protobuf:"bytes,5,opt,name=rate_type,json=rateType,proto3" json:"rate_type,omitempty"`                                // fixed или key_rate
+	SpreadBps         int64                  `protobuf:"varint,6,opt,name=spread_bps,json=spreadBps,proto3" json:"spread_bps,omitempty"`                            // надбавка к ключевой ставке
+	MinInitialPayment float64                `protobuf:"fixed64,7,opt,name=min_initial_payment,json=minInitialPayment,proto3" json:"min_initial_payment,omitempty"` // минимальная доля первоначального взноса
+	MinMonths         int64                  `protobuf:"varint,8,opt,name=min_months,json=minMonths,proto3" json:"min_months,omitempty"`                            // ограничения срока и суммы кредита, 0 — без ограничения
+	MaxMonths         int64                  `protobuf:"varint,9,opt,name=max_months,json=maxMonths,proto3" json:"max_months,omitempty"`
+	MinLoanSum        int64                  `protobuf:"varint,10,opt,name=min_loan_sum,json=minLoanSum,proto3" json:"min_loan_sum,omitempty"`
+	MaxLoanSum        int64                  `protobuf:"varint,11,opt,name=max_loan_sum,json=maxLoanSum,proto3" json:"max_loan_sum,omitempty"`
+	Inactive          bool                   `protobuf:"varint,12,opt,name=inactive,proto3" json:"inactive,omitempty"` // выключена: не выбирается в расчетах
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Program) Reset() {
+	*x = Program{}
+	mi := &file_api_protos_entities_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Program) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Program) ProtoMessage() {}
+
+func (x *Program) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Program.ProtoReflect.Descriptor instead.
+func (*Program) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Program) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Program) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Program) GetRateBps() int64 {
+	if x != nil {
+		return x.RateBps
+	}
+	return 0
+}
+
+func (x *Program) GetRatePeriods() []*RatePeriod {
+	if x != nil {
+		return x.RatePeriods
+	}
+	return nil
+}
+
+func (x *Program) GetRateType() string {
+	if x != nil {
+		return x.RateType
+	}
+	return ""
+}
+
+func (x *Program) GetSpreadBps() int64 {
+	if x != nil {
+		return x.SpreadBps
+	}
+	return 0
+}
+
+func (x *Program) GetMinInitialPayment() float64 {
+	if x != nil {
+		return x.MinInitialPayment
+	}
+	return 0
+}
+
+func (x *Program) GetMinMonths() int64 {
+	if x != nil {
+		return x.MinMonths
+	}
+	return 0
+}
+
+func (x *Program) GetMaxMonths() int64 {
+	if x != nil {
+		return x.MaxMonths
+	}
+	return 0
+}
+
+func (x *Program) GetMinLoanSum() int64 {
+	if x != nil {
+		return x.MinLoanSum
+	}
+	return 0
+}
+
+func (x *Program) GetMaxLoanSum() int64 {
+	if x != nil {
+		return x.MaxLoanSum
+	}
+	return 0
+}
+
+func (x *Program) GetInactive() bool {
+	if x != nil {
+		return x.Inactive
+	}
+	return false
+}
+
+// Фильтр списка программ (GET /admin/programs?include_inactive=true)
+type ListProgramsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	IncludeInactive bool                   `protobuf:"varint,1,opt,name=include_inactive,json=includeInactive,proto3" json:"include_inactive,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListProgramsRequest) Reset() {
+	*x = ListProgramsRequest{}
+	mi := &file_api_protos_entities_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProgramsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProgramsRequest) ProtoMessage() {}
+
+func (x *ListProgramsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProgramsRequest.ProtoReflect.Descriptor instead.
+func (*ListProgramsRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListProgramsRequest) GetIncludeInactive() bool {
+	if x != nil {
+		return x.IncludeInactive
+	}
+	return false
+}
+
+// Программы в порядке реестра
+type ProgramList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Programs      []*Program             `protobuf:"bytes,1,rep,name=programs,proto3" json:"programs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProgramList) Reset() {
+	*x = ProgramList{}
+	mi := &file_api_protos_entities_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProgramList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgramList) ProtoMessage() {}
+
+func (x *ProgramList) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgramList.ProtoReflect.Descriptor instead.
+func (*ProgramList) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProgramList) GetPrograms() []*Program {
+	if x != nil {
+		return x.Programs
+	}
+	return nil
+}
+
+// Замена программы целиком (PUT /admin/programs/{code}), код в теле не меняется
+type UpdateProgramRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Program       *Program               `protobuf:"bytes,2,opt,name=program,proto3" json:"program,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProgramRequest) Reset() {
+	*x = UpdateProgramRequest{}
+	mi := &file_api_protos_entities_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProgramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProgramRequest) ProtoMessage() {}
+
+func (x *UpdateProgramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProgramRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProgramRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdateProgramRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *UpdateProgramRequest) GetProgram() *Program {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+// Код программы из пути запроса
+type ProgramCode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProgramCode) Reset() {
+	*x = ProgramCode{}
+	mi := &file_api_protos_entities_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProgramCode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgramCode) ProtoMessage() {}
+
+func (x *ProgramCode) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgramCode.ProtoReflect.Descriptor instead.
+func (*ProgramCode) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ProgramCode) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+var File_api_protos_entities_admin_proto protoreflect.FileDescriptor
+
+const file_api_protos_entities_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x1fapi/protos/entities/admin.proto\x12\bentities\x1a\x1eapi/protos/entities/loan.proto\"\x8f\x03\n" +
+	"\aProgram\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x19\n" +
+	"\brate_bps\x18\x03 \x01(\x03R\arateBps\x127\n" +
+	"\frate_periods\x18\x04 \x03(\v2\x14.entities.RatePeriodR\vratePeriods\x12\x1b\n" +
+	"\trate_type\x18\x05 \x01(\tR\brateType\x12\x1d\n" +
+	"\n" +
+	"spread_bps\x18\x06 \x01(\x03R\tspreadBps\x12.\n" +
+	"\x13min_initial_payment\x18\a \x01(\x01R\x11minInitialPayment\x12\x1d\n" +
+	"\n" +
+	"min_months\x18\b \x01(\x03R\tminMonths\x12\x1d\n" +
+	"\n" +
+	"max_months\x18\t \x01(\x03R\tmaxMonths\x12 \n" +
+	"\fmin_loan_sum\x18\n" +
+	" \x01(\x03R\n" +
+	"minLoanSum\x12 \n" +
+	"\fmax_loan_sum\x18\v \x01(\x03R\n" +
+	"maxLoanSum\x12\x1a\n" +
+	"\binactive\x18\f \x01(\bR\binactive\"@\n" +
+	"\x13ListProgramsRequest\x12)\n" +
+	"\x10include_inactive\x18\x01 \x01(\bR\x0fincludeInactive\"<\n" +
+	"\vProgramList\x12-\n" +
+	"\bprograms\x18\x01 \x03(\v2\x11.entities.ProgramR\bprograms\"W\n" +
+	"\x14UpdateProgramRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12+\n" +
+	"\aprogram\x18\x02 \x01(\v2\x11.entities.ProgramR\aprogram\"!\n" +
+	"\vProgramCode\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04codeB4Z2github.com/Dorji/sberInterview/api/protos/entitiesb\x06proto3"
+
+var (
+	file_api_protos_entities_admin_proto_rawDescOnce sync.Once
+	file_api_protos_entities_admin_proto_rawDescData []byte
+)
+
+func file_api_protos_entities_admin_proto_rawDescGZIP() []byte {
+	file_api_protos_entities_admin_proto_rawDescOnce.Do(func() {
+		file_api_protos_entities_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_protos_entities_admin_proto_rawDesc), len(file_api_protos_entities_admin_proto_rawDesc)))
+	})
+	return file_api_protos_entities_admin_proto_rawDescData
+}
+
+var file_api_protos_entities_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_api_protos_entities_admin_proto_goTypes = []any{
+	(*Program)(nil),              // 0: entities.Program
+	(*ListProgramsRequest)(nil),  // 1: entities.ListProgramsRequest
+	(*ProgramList)(nil),          // 2: entities.ProgramList
+	(*UpdateProgramRequest)(nil), // 3: entities.UpdateProgramRequest
+	(*ProgramCode)(nil),          // 4: entities.ProgramCode
+	(*RatePeriod)(nil),           // 5: entities.RatePeriod
+}
+var file_api_protos_entities_admin_proto_depIdxs = []int32{
+	5, // 0: entities.Program.rate_periods:type_name -> entities.RatePeriod
+	0, // 1: entities.ProgramList.programs:type_name -> entities.Program
+	0, // 2: entities.UpdateProgramRequest.program:type_name -> entities.Program
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_api_protos_entities_admin_proto_init() }
+func file_api_protos_entities_admin_proto_init() {
+	if File_api_protos_entities_admin_proto != nil {
+		return
+	}
+	file_api_protos_entities_loan_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_protos_entities_admin_proto_rawDesc), len(file_api_protos_entities_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_protos_entities_admin_proto_goTypes,
+		DependencyIndexes: file_api_protos_entities_admin_proto_depIdxs,
+		MessageInfos:      file_api_protos_entities_admin_proto_msgTypes,
+	}.Build()
+	File_api_protos_entities_admin_proto = out.File
+	file_api_protos_entities_admin_proto_goTypes = nil
+	file_api_protos_entities_admin_proto_depIdxs = nil
+}
@@ -0,0 +1,339 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: api/protos/entities/affordability.proto
+
+package entities
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Что ограничило сумму кредита
+type AffordabilityLimit int32
+
+const (
+	AffordabilityLimit_BUDGET          AffordabilityLimit = 0 // ежемесячный бюджет
+	AffordabilityLimit_INITIAL_PAYMENT AffordabilityLimit = 1 // доступный взнос и минимальная доля взноса программы
+	AffordabilityLimit_MAX_LOAN_SUM    AffordabilityLimit = 2 // максимальная сумма кредита программы
+)
+
+// Enum value maps for AffordabilityLimit.
+var (
+	AffordabilityLimit_name = map[int32]string{
+		0: "BUDGET",
+		1: "INITIAL_PAYMENT",
+		2: "MAX_LOAN_SUM",
+	}
+	AffordabilityLimit_value = map[string]int32{
+		"BUDGET":          0,
+		"INITIAL_PAYMENT": 1,
+		"MAX_LOAN_SUM":    2,
+	}
+)
+
+func (x AffordabilityLimit) Enum() *AffordabilityLimit {
+	p := new(AffordabilityLimit)
+	*p = x
+	return p
+}
+
+func (x AffordabilityLimit) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AffordabilityLimit) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_protos_entities_affordability_proto_enumTypes[0].Descriptor()
+}
+
+func (AffordabilityLimit) Type() protoreflect.EnumType {
+	return &file_api_protos_entities_affordability_proto_enumTypes[0]
+}
+
+func (x AffordabilityLimit) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AffordabilityLimit.Descriptor instead.
+func (AffordabilityLimit) EnumDescriptor() ([]byte, []int) {
+	return file_api_protos_entities_affordability_proto_rawDescGZIP(), []int{0}
+}
+
+// Подбор максимального кредита по ежемесячному бюджету (POST /affordability)
+type AffordabilityRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	MonthlyPayment int64                  `protobuf:"varint,1,opt,name=monthly_payment,json=monthlyPayment,proto3" json:"monthly_payment,omitempty"` // бюджет на платеж в месяц (рубли)
+	Months         int64                  `protobuf:"varint,2,opt,name=months,proto3" json:"months,omitempty"`                                       // срок
+	Program        *LoanProgram           `protobuf:"bytes,3,opt,name=program,proto3" json:"program,omitempty"`
+	InitialPayment int64                  `protobuf:"varint,4,opt,name=initial_payment,json=initialPayment,proto3" json:"initial_payment,omitempty"`                  // доступный взнос, 0 — не ограничен: возвращается минимальный взнос программы
+	PaymentType    PaymentType            `protobuf:"varint,5,opt,name=payment_type,json=paymentType,proto3,enum=entities.PaymentType" json:"payment_type,omitempty"` // для дифференцированных платежей бюджет ограничивает первый платеж
+	IssueDate      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=issue_date,json=issueDate,proto3" json:"issue_date,omitempty"`
+	PaymentDay     int32                  `protobuf:"varint,7,opt,name=payment_day,json=paymentDay,proto3" json:"payment_day,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AffordabilityRequest) Reset() {
+	*x = AffordabilityRequest{}
+	mi := &file_api_protos_entities_affordability_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AffordabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AffordabilityRequest) ProtoMessage() {}
+
+func (x *AffordabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_affordability_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AffordabilityRequest.ProtoReflect.Descriptor instead.
+func (*AffordabilityRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_affordability_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AffordabilityRequest) GetMonthlyPayment() int64 {
+	if x != nil {
+		return x.MonthlyPayment
+	}
+	return 0
+}
+
+func (x *AffordabilityRequest) GetMonths() int64 {
+	if x != nil {
+		return x.Months
+	}
+	return 0
+}
+
+func (x *AffordabilityRequest) GetProgram() *LoanProgram {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *AffordabilityRequest) GetInitialPayment() int64 {
+	if x != nil {
+		return x.InitialPayment
+	}
+	return 0
+}
+
+func (x *AffordabilityRequest) GetPaymentType() PaymentType {
+	if x != nil {
+		return x.PaymentType
+	}
+	return PaymentType_ANNUITY
+}
+
+func (x *AffordabilityRequest) GetIssueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssueDate
+	}
+	return nil
+}
+
+func (x *AffordabilityRequest) GetPaymentDay() int32 {
+	if x != nil {
+		return x.PaymentDay
+	}
+	return 0
+}
+
+// Максимальный кредит: стоимость объекта и взнос в params, сумма кредита и платежи в aggregates, как у /execute
+type AffordabilityResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Params        *LoanParams            `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"`
+	Program       *LoanProgram           `protobuf:"bytes,2,opt,name=program,proto3" json:"program,omitempty"`
+	Aggregates    *LoanAggregates        `protobuf:"bytes,3,opt,name=aggregates,proto3" json:"aggregates,omitempty"`
+	PaymentType   PaymentType            `protobuf:"varint,4,opt,name=payment_type,json=paymentType,proto3,enum=entities.PaymentType" json:"payment_type,omitempty"`
+	LimitedBy     AffordabilityLimit     `protobuf:"varint,5,opt,name=limited_by,json=limitedBy,proto3,enum=entities.AffordabilityLimit" json:"limited_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AffordabilityResult) Reset() {
+	*x = AffordabilityResult{}
+	mi := &file_api_protos_entities_affordability_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AffordabilityResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AffordabilityResult) ProtoMessage() {}
+
+func (x *AffordabilityResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_affordability_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AffordabilityResult.ProtoReflect.Descriptor instead.
+func (*AffordabilityResult) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_affordability_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AffordabilityResult) GetParams() *LoanParams {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *AffordabilityResult) GetProgram() *LoanProgram {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *AffordabilityResult) GetAggregates() *LoanAggregates {
+	if x != nil {
+		return x.Aggregates
+	}
+	return nil
+}
+
+func (x *AffordabilityResult) GetPaymentType() PaymentType {
+	if x != nil {
+		return x.PaymentType
+	}
+	return PaymentType_ANNUITY
+}
+
+func (x *AffordabilityResult) GetLimitedBy() AffordabilityLimit {
+	if x != nil {
+		return x.LimitedBy
+	}
+	return AffordabilityLimit_BUDGET
+}
+
+var File_api_protos_entities_affordability_proto protoreflect.FileDescriptor
+
+const file_api_protos_entities_affordability_proto_rawDesc = "" +
+	"\n" +
+	"'api/protos/entities/affordability.proto\x12\bentities\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1eapi/protos/entities/loan.proto\"\xc7\x02\n" +
+	"\x14AffordabilityRequest\x12'\n" +
+	"\x0fmonthly_payment\x18\x01 \x01(\x03R\x0emonthlyPayment\x12\x16\n" +
+	"\x06months\x18\x02 \x01(\x03R\x06months\x12/\n" +
+	"\aprogram\x18\x03 \x01(\v2\x15.entities.LoanProgramR\aprogram\x12'\n" +
+	"\x0finitial_payment\x18\x04 \x01(\x03R\x0einitialPayment\x128\n" +
+	"\fpayment_type\x18\x05 \x01(\x0e2\x15.entities.PaymentTypeR\vpaymentType\x129\n" +
+	"\n" +
+	"issue_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tissueDate\x12\x1f\n" +
+	"\vpayment_day\x18\a \x01(\x05R\n" +
+	"paymentDay\"\xa5\x02\n" +
+	"\x13AffordabilityResult\x12,\n" +
+	"\x06params\x18\x01 \x01(\v2\x14.entities.LoanParamsR\x06params\x12/\n" +
+	"\aprogram\x18\x02 \x01(\v2\x15.entities.LoanProgramR\aprogram\x128\n" +
+	"\n" +
+	"aggregates\x18\x03 \x01(\v2\x18.entities.LoanAggregatesR\n" +
+	"aggregates\x128\n" +
+	"\fpayment_type\x18\x04 \x01(\x0e2\x15.entities.PaymentTypeR\vpaymentType\x12;\n" +
+	"\n" +
+	"limited_by\x18\x05 \x01(\x0e2\x1c.entities.AffordabilityLimitR\tlimitedBy*G\n" +
+	"\x12AffordabilityLimit\x12\n" +
+	"\n" +
+	"\x06BUDGET\x10\x00\x12\x13\n" +
+	"\x0fINITIAL_PAYMENT\x10\x01\x12\x10\n" +
+	"\fMAX_LOAN_SUM\x10\x02B4Z2github.com/Dorji/sberInterview/api/protos/entitiesb\x06proto3"
+
+var (
+	file_api_protos_entities_affordability_proto_rawDescOnce sync.Once
+	file_api_protos_entities_affordability_proto_rawDescData []byte
+)
+
+func file_api_protos_entities_affordability_proto_rawDescGZIP() []byte {
+	file_api_protos_entities_affordability_proto_rawDescOnce.Do(func() {
+		file_api_protos_entities_affordability_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_protos_entities_affordability_proto_rawDesc), len(file_api_protos_entities_affordability_proto_rawDesc)))
+	})
+	return file_api_protos_entities_affordability_proto_rawDescData
+}
+
+var file_api_protos_entities_affordability_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_protos_entities_affordability_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_protos_entities_affordability_proto_goTypes = []any{
+	(AffordabilityLimit)(0),       // 0: entities.AffordabilityLimit
+	(*AffordabilityRequest)(nil),  // 1: entities.AffordabilityRequest
+	(*AffordabilityResult)(nil),   // 2: entities.AffordabilityResult
+	(*LoanProgram)(nil),           // 3: entities.LoanProgram
+	(PaymentType)(0),              // 4: entities.PaymentType
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+	(*LoanParams)(nil),            // 6: entities.LoanParams
+	(*LoanAggregates)(nil),        // 7: entities.LoanAggregates
+}
+var file_api_protos_entities_affordability_proto_depIdxs = []int32{
+	3, // 0: entities.AffordabilityRequest.program:type_name -> entities.LoanProgram
+	4, // 1: entities.AffordabilityRequest.payment_type:type_name -> entities.PaymentType
+	5, // 2: entities.AffordabilityRequest.issue_date:type_name -> google.protobuf.Timestamp
+	6, // 3: entities.AffordabilityResult.params:type_name -> entities.LoanParams
+	3, // 4: entities.AffordabilityResult.program:type_name -> entities.LoanProgram
+	7, // 5: entities.AffordabilityResult.aggregates:type_name -> entities.LoanAggregates
+	4, // 6: entities.AffordabilityResult.payment_type:type_name -> entities.PaymentType
+	0, // 7: entities.AffordabilityResult.limited_by:type_name -> entities.AffordabilityLimit
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_api_protos_entities_affordability_proto_init() }
+func file_api_protos_entities_affordability_proto_init() {
+	if File_api_protos_entities_affordability_proto != nil {
+		return
+	}
+	file_api_protos_entities_loan_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_protos_entities_affordability_proto_rawDesc), len(file_api_protos_entities_affordability_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_protos_entities_affordability_proto_goTypes,
+		DependencyIndexes: file_api_protos_entities_affordability_proto_depIdxs,
+		EnumInfos:         file_api_protos_entities_affordability_proto_enumTypes,
+		MessageInfos:      file_api_protos_entities_affordability_proto_msgTypes,
+	}.Build()
+	File_api_protos_entities_affordability_proto = out.File
+	file_api_protos_entities_affordability_proto_goTypes = nil
+	file_api_protos_entities_affordability_proto_depIdxs = nil
+}
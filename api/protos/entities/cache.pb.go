@@ -0,0 +1,323 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: api/protos/entities/cache.proto
+
+package entities
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Запись журнала кеша расчетов на диске
+type CacheRecord struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Op:
+	//
+	//	*CacheRecord_Add
+	//	*CacheRecord_Delete
+	//	*CacheRecord_Clear
+	Op            isCacheRecord_Op `protobuf_oneof:"op"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheRecord) Reset() {
+	*x = CacheRecord{}
+	mi := &file_api_protos_entities_cache_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheRecord) ProtoMessage() {}
+
+func (x *CacheRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_cache_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheRecord.ProtoReflect.Descriptor instead.
+func (*CacheRecord) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_cache_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CacheRecord) GetOp() isCacheRecord_Op {
+	if x != nil {
+		return x.Op
+	}
+	return nil
+}
+
+func (x *CacheRecord) GetAdd() *CacheEntry {
+	if x != nil {
+		if x, ok := x.Op.(*CacheRecord_Add); ok {
+			return x.Add
+		}
+	}
+	return nil
+}
+
+func (x *CacheRecord) GetDelete() int64 {
+	if x != nil {
+		if x, ok := x.Op.(*CacheRecord_Delete); ok {
+			return x.Delete
+		}
+	}
+	return 0
+}
+
+func (x *CacheRecord) GetClear() int64 {
+	if x != nil {
+		if x, ok := x.Op.(*CacheRecord_Clear); ok {
+			return x.Clear
+		}
+	}
+	return 0
+}
+
+type isCacheRecord_Op interface {
+	isCacheRecord_Op()
+}
+
+type CacheRecord_Add struct {
+	Add *CacheEntry `protobuf:"bytes,1,opt,name=add,proto3,oneof"`
+}
+
+type CacheRecord_Delete struct {
+	Delete int64 `protobuf:"varint,2,opt,name=delete,proto3,oneof"` // id удаленного расчета
+}
+
+type CacheRecord_Clear struct {
+	Clear int64 `protobuf:"varint,3,opt,name=clear,proto3,oneof"` // id меньше этого удалены через Clear
+}
+
+func (*CacheRecord_Add) isCacheRecord_Op() {}
+
+func (*CacheRecord_Delete) isCacheRecord_Op() {}
+
+func (*CacheRecord_Clear) isCacheRecord_Op() {}
+
+// Расчет в кеше вместе с ключом мемоизации
+type CacheEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *LoanResult            `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheEntry) Reset() {
+	*x = CacheEntry{}
+	mi := &file_api_protos_entities_cache_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheEntry) ProtoMessage() {}
+
+func (x *CacheEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_cache_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheEntry.ProtoReflect.Descriptor instead.
+func (*CacheEntry) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_cache_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CacheEntry) GetResult() *LoanResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *CacheEntry) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+// Снимок кеша: после него журнал начинается заново
+type CacheSnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NextId        int64                  `protobuf:"varint,1,opt,name=next_id,json=nextId,proto3" json:"next_id,omitempty"`
+	Entries       []*CacheEntry          `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	Deleted       []int64                `protobuf:"varint,3,rep,packed,name=deleted,proto3" json:"deleted,omitempty"`
+	ClearedBelow  int64                  `protobuf:"varint,4,opt,name=cleared_below,json=clearedBelow,proto3" json:"cleared_below,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheSnapshot) Reset() {
+	*x = CacheSnapshot{}
+	mi := &file_api_protos_entities_cache_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheSnapshot) ProtoMessage() {}
+
+func (x *CacheSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_cache_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheSnapshot.ProtoReflect.Descriptor instead.
+func (*CacheSnapshot) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_cache_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CacheSnapshot) GetNextId() int64 {
+	if x != nil {
+		return x.NextId
+	}
+	return 0
+}
+
+func (x *CacheSnapshot) GetEntries() []*CacheEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *CacheSnapshot) GetDeleted() []int64 {
+	if x != nil {
+		return x.Deleted
+	}
+	return nil
+}
+
+func (x *CacheSnapshot) GetClearedBelow() int64 {
+	if x != nil {
+		return x.ClearedBelow
+	}
+	return 0
+}
+
+var File_api_protos_entities_cache_proto protoreflect.FileDescriptor
+
+const file_api_protos_entities_cache_proto_rawDesc = "" +
+	"\n" +
+	"\x1fapi/protos/entities/cache.proto\x12\bentities\x1a\x1eapi/protos/entities/loan.proto\"o\n" +
+	"\vCacheRecord\x12(\n" +
+	"\x03add\x18\x01 \x01(\v2\x14.entities.CacheEntryH\x00R\x03add\x12\x18\n" +
+	"\x06delete\x18\x02 \x01(\x03H\x00R\x06delete\x12\x16\n" +
+	"\x05clear\x18\x03 \x01(\x03H\x00R\x05clearB\x04\n" +
+	"\x02op\"L\n" +
+	"\n" +
+	"CacheEntry\x12,\n" +
+	"\x06result\x18\x01 \x01(\v2\x14.entities.LoanResultR\x06result\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\"\x97\x01\n" +
+	"\rCacheSnapshot\x12\x17\n" +
+	"\anext_id\x18\x01 \x01(\x03R\x06nextId\x12.\n" +
+	"\aentries\x18\x02 \x03(\v2\x14.entities.CacheEntryR\aentries\x12\x18\n" +
+	"\adeleted\x18\x03 \x03(\x03R\adeleted\x12#\n" +
+	"\rcleared_below\x18\x04 \x01(\x03R\fclearedBelowB4Z2github.com/Dorji/sberInterview/api/protos/entitiesb\x06proto3"
+
+var (
+	file_api_protos_entities_cache_proto_rawDescOnce sync.Once
+	file_api_protos_entities_cache_proto_rawDescData []byte
+)
+
+func file_api_protos_entities_cache_proto_rawDescGZIP() []byte {
+	file_api_protos_entities_cache_proto_rawDescOnce.Do(func() {
+		file_api_protos_entities_cache_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_protos_entities_cache_proto_rawDesc), len(file_api_protos_entities_cache_proto_rawDesc)))
+	})
+	return file_api_protos_entities_cache_proto_rawDescData
+}
+
+var file_api_protos_entities_cache_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_api_protos_entities_cache_proto_goTypes = []any{
+	(*CacheRecord)(nil),   // 0: entities.CacheRecord
+	(*CacheEntry)(nil),    // 1: entities.CacheEntry
+	(*CacheSnapshot)(nil), // 2: entities.CacheSnapshot
+	(*LoanResult)(nil),    // 3: entities.LoanResult
+}
+var file_api_protos_entities_cache_proto_depIdxs = []int32{
+	1, // 0: entities.CacheRecord.add:type_name -> entities.CacheEntry
+	3, // 1: entities.CacheEntry.result:type_name -> entities.LoanResult
+	1, // 2: entities.CacheSnapshot.entries:type_name -> entities.CacheEntry
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_api_protos_entities_cache_proto_init() }
+func file_api_protos_entities_cache_proto_init() {
+	if File_api_protos_entities_cache_proto != nil {
+		return
+	}
+	file_api_protos_entities_loan_proto_init()
+	file_api_protos_entities_cache_proto_msgTypes[0].OneofWrappers = []any{
+		(*CacheRecord_Add)(nil),
+		(*CacheRecord_Delete)(nil),
+		(*CacheRecord_Clear)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_protos_entities_cache_proto_rawDesc), len(file_api_protos_entities_cache_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_protos_entities_cache_proto_goTypes,
+		DependencyIndexes: file_api_protos_entities_cache_proto_depIdxs,
+		MessageInfos:      file_api_protos_entities_cache_proto_msgTypes,
+	}.Build()
+	File_api_protos_entities_cache_proto = out.File
+	file_api_protos_entities_cache_proto_goTypes = nil
+	file_api_protos_entities_cache_proto_depIdxs = nil
+}
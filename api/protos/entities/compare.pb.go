@@ -0,0 +1,303 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: api/protos/entities/compare.proto
+
+package entities
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Условия одной программы в сравнении
+type ProgramOffer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Program       *LoanProgram           `protobuf:"bytes,1,opt,name=program,proto3" json:"program,omitempty"` // программа по коду
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`       // название программы
+	Aggregates    *LoanAggregates        `protobuf:"bytes,3,opt,name=aggregates,proto3" json:"aggregates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProgramOffer) Reset() {
+	*x = ProgramOffer{}
+	mi := &file_api_protos_entities_compare_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProgramOffer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgramOffer) ProtoMessage() {}
+
+func (x *ProgramOffer) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_compare_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgramOffer.ProtoReflect.Descriptor instead.
+func (*ProgramOffer) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_compare_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProgramOffer) GetProgram() *LoanProgram {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *ProgramOffer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProgramOffer) GetAggregates() *LoanAggregates {
+	if x != nil {
+		return x.Aggregates
+	}
+	return nil
+}
+
+// Программа, на которую запрос не проходит, с причинами отказа
+type IneligibleProgram struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Program       *LoanProgram           `protobuf:"bytes,1,opt,name=program,proto3" json:"program,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Violations    []*FieldViolation      `protobuf:"bytes,3,rep,name=violations,proto3" json:"violations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IneligibleProgram) Reset() {
+	*x = IneligibleProgram{}
+	mi := &file_api_protos_entities_compare_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IneligibleProgram) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IneligibleProgram) ProtoMessage() {}
+
+func (x *IneligibleProgram) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_compare_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IneligibleProgram.ProtoReflect.Descriptor instead.
+func (*IneligibleProgram) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_compare_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IneligibleProgram) GetProgram() *LoanProgram {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *IneligibleProgram) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IneligibleProgram) GetViolations() []*FieldViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+// Сравнение программ на одних параметрах (POST /compare)
+type CompareResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Params        *LoanParams            `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"`
+	PaymentType   PaymentType            `protobuf:"varint,2,opt,name=payment_type,json=paymentType,proto3,enum=entities.PaymentType" json:"payment_type,omitempty"`
+	Offers        []*ProgramOffer        `protobuf:"bytes,3,rep,name=offers,proto3" json:"offers,omitempty"`         // по возрастанию переплаты
+	Ineligible    []*IneligibleProgram   `protobuf:"bytes,4,rep,name=ineligible,proto3" json:"ineligible,omitempty"` // в порядке реестра
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompareResult) Reset() {
+	*x = CompareResult{}
+	mi := &file_api_protos_entities_compare_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareResult) ProtoMessage() {}
+
+func (x *CompareResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_compare_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareResult.ProtoReflect.Descriptor instead.
+func (*CompareResult) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_compare_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CompareResult) GetParams() *LoanParams {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *CompareResult) GetPaymentType() PaymentType {
+	if x != nil {
+		return x.PaymentType
+	}
+	return PaymentType_ANNUITY
+}
+
+func (x *CompareResult) GetOffers() []*ProgramOffer {
+	if x != nil {
+		return x.Offers
+	}
+	return nil
+}
+
+func (x *CompareResult) GetIneligible() []*IneligibleProgram {
+	if x != nil {
+		return x.Ineligible
+	}
+	return nil
+}
+
+var File_api_protos_entities_compare_proto protoreflect.FileDescriptor
+
+const file_api_protos_entities_compare_proto_rawDesc = "" +
+	"\n" +
+	"!api/protos/entities/compare.proto\x12\bentities\x1a\x1eapi/protos/entities/loan.proto\x1a api/protos/entities/errors.proto\"\x8d\x01\n" +
+	"\fProgramOffer\x12/\n" +
+	"\aprogram\x18\x01 \x01(\v2\x15.entities.LoanProgramR\aprogram\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x128\n" +
+	"\n" +
+	"aggregates\x18\x03 \x01(\v2\x18.entities.LoanAggregatesR\n" +
+	"aggregates\"\x92\x01\n" +
+	"\x11IneligibleProgram\x12/\n" +
+	"\aprogram\x18\x01 \x01(\v2\x15.entities.LoanProgramR\aprogram\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x128\n" +
+	"\n" +
+	"violations\x18\x03 \x03(\v2\x18.entities.FieldViolationR\n" +
+	"violations\"\xe4\x01\n" +
+	"\rCompareResult\x12,\n" +
+	"\x06params\x18\x01 \x01(\v2\x14.entities.LoanParamsR\x06params\x128\n" +
+	"\fpayment_type\x18\x02 \x01(\x0e2\x15.entities.PaymentTypeR\vpaymentType\x12.\n" +
+	"\x06offers\x18\x03 \x03(\v2\x16.entities.ProgramOfferR\x06offers\x12;\n" +
+	"\n" +
+	"ineligible\x18\x04 \x03(\v2\x1b.entities.IneligibleProgramR\n" +
+	"ineligibleB4Z2github.com/Dorji/sberInterview/api/protos/entitiesb\x06proto3"
+
+var (
+	file_api_protos_entities_compare_proto_rawDescOnce sync.Once
+	file_api_protos_entities_compare_proto_rawDescData []byte
+)
+
+func file_api_protos_entities_compare_proto_rawDescGZIP() []byte {
+	file_api_protos_entities_compare_proto_rawDescOnce.Do(func() {
+		file_api_protos_entities_compare_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_protos_entities_compare_proto_rawDesc), len(file_api_protos_entities_compare_proto_rawDesc)))
+	})
+	return file_api_protos_entities_compare_proto_rawDescData
+}
+
+var file_api_protos_entities_compare_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_api_protos_entities_compare_proto_goTypes = []any{
+	(*ProgramOffer)(nil),      // 0: entities.ProgramOffer
+	(*IneligibleProgram)(nil), // 1: entities.IneligibleProgram
+	(*CompareResult)(nil),     // 2: entities.CompareResult
+	(*LoanProgram)(nil),       // 3: entities.LoanProgram
+	(*LoanAggregates)(nil),    // 4: entities.LoanAggregates
+	(*FieldViolation)(nil),    // 5: entities.FieldViolation
+	(*LoanParams)(nil),        // 6: entities.LoanParams
+	(PaymentType)(0),          // 7: entities.PaymentType
+}
+var file_api_protos_entities_compare_proto_depIdxs = []int32{
+	3, // 0: entities.ProgramOffer.program:type_name -> entities.LoanProgram
+	4, // 1: entities.ProgramOffer.aggregates:type_name -> entities.LoanAggregates
+	3, // 2: entities.IneligibleProgram.program:type_name -> entities.LoanProgram
+	5, // 3: entities.IneligibleProgram.violations:type_name -> entities.FieldViolation
+	6, // 4: entities.CompareResult.params:type_name -> entities.LoanParams
+	7, // 5: entities.CompareResult.payment_type:type_name -> entities.PaymentType
+	0, // 6: entities.CompareResult.offers:type_name -> entities.ProgramOffer
+	1, // 7: entities.CompareResult.ineligible:type_name -> entities.IneligibleProgram
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_api_protos_entities_compare_proto_init() }
+func file_api_protos_entities_compare_proto_init() {
+	if File_api_protos_entities_compare_proto != nil {
+		return
+	}
+	file_api_protos_entities_loan_proto_init()
+	file_api_protos_entities_errors_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_protos_entities_compare_proto_rawDesc), len(file_api_protos_entities_compare_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_protos_entities_compare_proto_goTypes,
+		DependencyIndexes: file_api_protos_entities_compare_proto_depIdxs,
+		MessageInfos:      file_api_protos_entities_compare_proto_msgTypes,
+	}.Build()
+	File_api_protos_entities_compare_proto = out.File
+	file_api_protos_entities_compare_proto_goTypes = nil
+	file_api_protos_entities_compare_proto_depIdxs = nil
+}
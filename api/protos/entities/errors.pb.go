@@ -21,9 +21,12 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Тело ошибки HTTP API: {"error": "choose program"}
 type Error struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`         // машиночитаемая причина из google.rpc.ErrorInfo, если есть
+	Violations    []*FieldViolation      `protobuf:"bytes,3,rep,name=violations,proto3" json:"violations,omitempty"` // нарушения по полям из google.rpc.BadRequest
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -65,13 +68,96 @@ func (x *Error) GetError() string {
 	return ""
 }
 
+func (x *Error) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Error) GetViolations() []*FieldViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+// Нарушение в конкретном поле запроса
+type FieldViolation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldViolation) Reset() {
+	*x = FieldViolation{}
+	mi := &file_api_protos_entities_errors_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldViolation) ProtoMessage() {}
+
+func (x *FieldViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_errors_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldViolation.ProtoReflect.Descriptor instead.
+func (*FieldViolation) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_errors_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FieldViolation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 var File_api_protos_entities_errors_proto protoreflect.FileDescriptor
 
 const file_api_protos_entities_errors_proto_rawDesc = "" +
 	"\n" +
-	" api/protos/entities/errors.proto\x12\bentities\"\x1d\n" +
+	" api/protos/entities/errors.proto\x12\bentities\"o\n" +
 	"\x05Error\x12\x14\n" +
-	"\x05error\x18\x01 \x01(\tR\x05errorB4Z2github.com/Dorji/sberInterview/api/protos/entitiesb\x06proto3"
+	"\x05error\x18\x01 \x01(\tR\x05error\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x128\n" +
+	"\n" +
+	"violations\x18\x03 \x03(\v2\x18.entities.FieldViolationR\n" +
+	"violations\"X\n" +
+	"\x0eFieldViolation\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessageB4Z2github.com/Dorji/sberInterview/api/protos/entitiesb\x06proto3"
 
 var (
 	file_api_protos_entities_errors_proto_rawDescOnce sync.Once
@@ -85,16 +171,18 @@ func file_api_protos_entities_errors_proto_rawDescGZIP() []byte {
 	return file_api_protos_entities_errors_proto_rawDescData
 }
 
-var file_api_protos_entities_errors_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_api_protos_entities_errors_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_api_protos_entities_errors_proto_goTypes = []any{
-	(*Error)(nil), // 0: entities.Error
+	(*Error)(nil),          // 0: entities.Error
+	(*FieldViolation)(nil), // 1: entities.FieldViolation
 }
 var file_api_protos_entities_errors_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: entities.Error.violations:type_name -> entities.FieldViolation
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_api_protos_entities_errors_proto_init() }
@@ -108,7 +196,7 @@ func file_api_protos_entities_errors_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_protos_entities_errors_proto_rawDesc), len(file_api_protos_entities_errors_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
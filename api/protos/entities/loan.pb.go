@@ -22,31 +22,1116 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Схема погашения кредита
+type PaymentType int32
+
+const (
+	PaymentType_ANNUITY        PaymentType = 0 // аннуитетные (равные) платежи
+	PaymentType_DIFFERENTIATED PaymentType = 1 // дифференцированные (убывающие) платежи
+)
+
+// Enum value maps for PaymentType.
+var (
+	PaymentType_name = map[int32]string{
+		0: "ANNUITY",
+		1: "DIFFERENTIATED",
+	}
+	PaymentType_value = map[string]int32{
+		"ANNUITY":        0,
+		"DIFFERENTIATED": 1,
+	}
+)
+
+func (x PaymentType) Enum() *PaymentType {
+	p := new(PaymentType)
+	*p = x
+	return p
+}
+
+func (x PaymentType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PaymentType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_protos_entities_loan_proto_enumTypes[0].Descriptor()
+}
+
+func (PaymentType) Type() protoreflect.EnumType {
+	return &file_api_protos_entities_loan_proto_enumTypes[0]
+}
+
+func (x PaymentType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PaymentType.Descriptor instead.
+func (PaymentType) EnumDescriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{0}
+}
+
+// Поле сортировки списка расчетов
+type CacheSort int32
+
+const (
+	CacheSort_CREATED_AT      CacheSort = 0
+	CacheSort_MONTHLY_PAYMENT CacheSort = 1
+)
+
+// Enum value maps for CacheSort.
+var (
+	CacheSort_name = map[int32]string{
+		0: "CREATED_AT",
+		1: "MONTHLY_PAYMENT",
+	}
+	CacheSort_value = map[string]int32{
+		"CREATED_AT":      0,
+		"MONTHLY_PAYMENT": 1,
+	}
+)
+
+func (x CacheSort) Enum() *CacheSort {
+	p := new(CacheSort)
+	*p = x
+	return p
+}
+
+func (x CacheSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CacheSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_protos_entities_loan_proto_enumTypes[1].Descriptor()
+}
+
+func (CacheSort) Type() protoreflect.EnumType {
+	return &file_api_protos_entities_loan_proto_enumTypes[1]
+}
+
+func (x CacheSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CacheSort.Descriptor instead.
+func (CacheSort) EnumDescriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{1}
+}
+
 type LoanRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
-	ObjectCost     int64                  `protobuf:"varint,1,opt,name=object_cost,json=objectCost,proto3" json:"object_cost,omitempty"`             // стоимость объекта
-	InitialPayment int64                  `protobuf:"varint,2,opt,name=initial_payment,json=initialPayment,proto3" json:"initial_payment,omitempty"` // первоначальный взнос
-	Months         int64                  `protobuf:"varint,3,opt,name=months,proto3" json:"months,omitempty"`                                       // срок
-	Program        *LoanProgram           `protobuf:"bytes,4,opt,name=program,proto3" json:"program,omitempty"`                                      // блок программы кредита
+	ObjectCost     int64                  `protobuf:"varint,1,opt,name=object_cost,json=objectCost,proto3" json:"object_cost,omitempty"`                              // стоимость объекта
+	InitialPayment int64                  `protobuf:"varint,2,opt,name=initial_payment,json=initialPayment,proto3" json:"initial_payment,omitempty"`                  // первоначальный взнос
+	Months         int64                  `protobuf:"varint,3,opt,name=months,proto3" json:"months,omitempty"`                                                        // срок
+	Program        *LoanProgram           `protobuf:"bytes,4,opt,name=program,proto3" json:"program,omitempty"`                                                       // блок программы кредита
+	PaymentType    PaymentType            `protobuf:"varint,5,opt,name=payment_type,json=paymentType,proto3,enum=entities.PaymentType" json:"payment_type,omitempty"` // схема погашения (по умолчанию аннуитет)
+	IssueDate      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=issue_date,json=issueDate,proto3" json:"issue_date,omitempty"`                                  // дата выдачи (по умолчанию сегодня)
+	PaymentDay     int32                  `protobuf:"varint,7,opt,name=payment_day,json=paymentDay,proto3" json:"payment_day,omitempty"`                              // день платежа 1-31 (по умолчанию день выдачи)
+	Costs          *LoanCosts             `protobuf:"bytes,8,opt,name=costs,proto3" json:"costs,omitempty"`                                                           // комиссии и страховка для расчета ПСК
+	RatePeriods    []*RatePeriod          `protobuf:"bytes,9,rep,name=rate_periods,json=ratePeriods,proto3" json:"rate_periods,omitempty"`                            // ступенчатая ставка вместо периодов программы, дальше — ставка программы
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *LoanRequest) Reset() {
-	*x = LoanRequest{}
-	mi := &file_api_protos_entities_loan_proto_msgTypes[0]
+func (x *LoanRequest) Reset() {
+	*x = LoanRequest{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoanRequest) ProtoMessage() {}
+
+func (x *LoanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoanRequest.ProtoReflect.Descriptor instead.
+func (*LoanRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LoanRequest) GetObjectCost() int64 {
+	if x != nil {
+		return x.ObjectCost
+	}
+	return 0
+}
+
+func (x *LoanRequest) GetInitialPayment() int64 {
+	if x != nil {
+		return x.InitialPayment
+	}
+	return 0
+}
+
+func (x *LoanRequest) GetMonths() int64 {
+	if x != nil {
+		return x.Months
+	}
+	return 0
+}
+
+func (x *LoanRequest) GetProgram() *LoanProgram {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *LoanRequest) GetPaymentType() PaymentType {
+	if x != nil {
+		return x.PaymentType
+	}
+	return PaymentType_ANNUITY
+}
+
+func (x *LoanRequest) GetIssueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssueDate
+	}
+	return nil
+}
+
+func (x *LoanRequest) GetPaymentDay() int32 {
+	if x != nil {
+		return x.PaymentDay
+	}
+	return 0
+}
+
+func (x *LoanRequest) GetCosts() *LoanCosts {
+	if x != nil {
+		return x.Costs
+	}
+	return nil
+}
+
+func (x *LoanRequest) GetRatePeriods() []*RatePeriod {
+	if x != nil {
+		return x.RatePeriods
+	}
+	return nil
+}
+
+// Период ступенчатой ставки: первые months месяцев (0 — до конца срока) по ставке rate_bps
+type RatePeriod struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Months        int64                  `protobuf:"varint,1,opt,name=months,proto3" json:"months,omitempty"`
+	RateBps       int64                  `protobuf:"varint,2,opt,name=rate_bps,json=rateBps,proto3" json:"rate_bps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RatePeriod) Reset() {
+	*x = RatePeriod{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RatePeriod) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatePeriod) ProtoMessage() {}
+
+func (x *RatePeriod) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatePeriod.ProtoReflect.Descriptor instead.
+func (*RatePeriod) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RatePeriod) GetMonths() int64 {
+	if x != nil {
+		return x.Months
+	}
+	return 0
+}
+
+func (x *RatePeriod) GetRateBps() int64 {
+	if x != nil {
+		return x.RateBps
+	}
+	return 0
+}
+
+// Непроцентные расходы заемщика по кредиту
+type LoanCosts struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	OneOffFee        *Money                 `protobuf:"bytes,1,opt,name=one_off_fee,json=oneOffFee,proto3" json:"one_off_fee,omitempty"`                       // разовая комиссия при выдаче
+	MonthlyFee       *Money                 `protobuf:"bytes,2,opt,name=monthly_fee,json=monthlyFee,proto3" json:"monthly_fee,omitempty"`                      // комиссия с каждым плановым платежом
+	AnnualInsurance  *Money                 `protobuf:"bytes,3,opt,name=annual_insurance,json=annualInsurance,proto3" json:"annual_insurance,omitempty"`       // страховая премия в начале каждого года кредита
+	InsuranceRateBps int64                  `protobuf:"varint,4,opt,name=insurance_rate_bps,json=insuranceRateBps,proto3" json:"insurance_rate_bps,omitempty"` // или премия в базисных пунктах от остатка долга на начало года
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *LoanCosts) Reset() {
+	*x = LoanCosts{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoanCosts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoanCosts) ProtoMessage() {}
+
+func (x *LoanCosts) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoanCosts.ProtoReflect.Descriptor instead.
+func (*LoanCosts) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LoanCosts) GetOneOffFee() *Money {
+	if x != nil {
+		return x.OneOffFee
+	}
+	return nil
+}
+
+func (x *LoanCosts) GetMonthlyFee() *Money {
+	if x != nil {
+		return x.MonthlyFee
+	}
+	return nil
+}
+
+func (x *LoanCosts) GetAnnualInsurance() *Money {
+	if x != nil {
+		return x.AnnualInsurance
+	}
+	return nil
+}
+
+func (x *LoanCosts) GetInsuranceRateBps() int64 {
+	if x != nil {
+		return x.InsuranceRateBps
+	}
+	return 0
+}
+
+// Блок программы кредита
+type LoanProgram struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Salary        bool                   `protobuf:"varint,1,opt,name=salary,proto3" json:"salary,omitempty"`     // корпоративная программа
+	Military      bool                   `protobuf:"varint,2,opt,name=military,proto3" json:"military,omitempty"` // военная ипотека
+	Base          bool                   `protobuf:"varint,3,opt,name=base,proto3" json:"base,omitempty"`         // базовая программа
+	Code          string                 `protobuf:"bytes,4,opt,name=code,proto3" json:"code,omitempty"`          // код программы из реестра (salary, military, base или заданной в конфиге)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoanProgram) Reset() {
+	*x = LoanProgram{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoanProgram) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoanProgram) ProtoMessage() {}
+
+func (x *LoanProgram) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoanProgram.ProtoReflect.Descriptor instead.
+func (*LoanProgram) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LoanProgram) GetSalary() bool {
+	if x != nil {
+		return x.Salary
+	}
+	return false
+}
+
+func (x *LoanProgram) GetMilitary() bool {
+	if x != nil {
+		return x.Military
+	}
+	return false
+}
+
+func (x *LoanProgram) GetBase() bool {
+	if x != nil {
+		return x.Base
+	}
+	return false
+}
+
+func (x *LoanProgram) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+// Блок агрегированных данных
+type LoanAggregates struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Rate            int64                  `protobuf:"varint,1,opt,name=rate,proto3" json:"rate,omitempty"`                                               // ставка (% годовых), v1: целые проценты, см. rate_bps
+	LoanSum         int64                  `protobuf:"varint,2,opt,name=loan_sum,json=loanSum,proto3" json:"loan_sum,omitempty"`                          // сумма кредита
+	MonthlyPayment  int64                  `protobuf:"varint,3,opt,name=monthly_payment,json=monthlyPayment,proto3" json:"monthly_payment,omitempty"`     // платеж в месяц
+	Overpayment     int64                  `protobuf:"varint,4,opt,name=overpayment,proto3" json:"overpayment,omitempty"`                                 // переплата
+	LastPaymentDate *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_payment_date,json=lastPaymentDate,proto3" json:"last_payment_date,omitempty"` // дата последнего платежа
+	FirstPayment    int64                  `protobuf:"varint,6,opt,name=first_payment,json=firstPayment,proto3" json:"first_payment,omitempty"`           // первый платеж
+	LastPayment     int64                  `protobuf:"varint,7,opt,name=last_payment,json=lastPayment,proto3" json:"last_payment,omitempty"`              // последний платеж
+	Exact           *ExactAmounts          `protobuf:"bytes,8,opt,name=exact,proto3" json:"exact,omitempty"`                                              // те же суммы с точностью до копейки
+	RateBps         int64                  `protobuf:"varint,9,opt,name=rate_bps,json=rateBps,proto3" json:"rate_bps,omitempty"`                          // v2: ставка в базисных пунктах (850 = 8.5%), rate в v1 округлена до целых процентов вниз
+	FullCostRate    int64                  `protobuf:"varint,10,opt,name=full_cost_rate,json=fullCostRate,proto3" json:"full_cost_rate,omitempty"`        // ПСК в тысячных долях процента (8312 = 8.312% годовых)
+	FullCost        *Money                 `protobuf:"bytes,11,opt,name=full_cost,json=fullCost,proto3" json:"full_cost,omitempty"`                       // ПСК в денежном выражении: проценты и непроцентные расходы
+	Costs           *CostBreakdown         `protobuf:"bytes,12,opt,name=costs,proto3" json:"costs,omitempty"`                                             // непроцентные расходы
+	RatePeriods     []*RatePeriodSummary   `protobuf:"bytes,13,rep,name=rate_periods,json=ratePeriods,proto3" json:"rate_periods,omitempty"`              // периоды ставки (только для ступенчатой ставки), rate_bps — ставка первого
+	KeyRate         *KeyRateInfo           `protobuf:"bytes,14,opt,name=key_rate,json=keyRate,proto3" json:"key_rate,omitempty"`                          // ключевая ставка (только для плавающей ставки программы)
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *LoanAggregates) Reset() {
+	*x = LoanAggregates{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoanAggregates) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoanAggregates) ProtoMessage() {}
+
+func (x *LoanAggregates) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoanAggregates.ProtoReflect.Descriptor instead.
+func (*LoanAggregates) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LoanAggregates) GetRate() int64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *LoanAggregates) GetLoanSum() int64 {
+	if x != nil {
+		return x.LoanSum
+	}
+	return 0
+}
+
+func (x *LoanAggregates) GetMonthlyPayment() int64 {
+	if x != nil {
+		return x.MonthlyPayment
+	}
+	return 0
+}
+
+func (x *LoanAggregates) GetOverpayment() int64 {
+	if x != nil {
+		return x.Overpayment
+	}
+	return 0
+}
+
+func (x *LoanAggregates) GetLastPaymentDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastPaymentDate
+	}
+	return nil
+}
+
+func (x *LoanAggregates) GetFirstPayment() int64 {
+	if x != nil {
+		return x.FirstPayment
+	}
+	return 0
+}
+
+func (x *LoanAggregates) GetLastPayment() int64 {
+	if x != nil {
+		return x.LastPayment
+	}
+	return 0
+}
+
+func (x *LoanAggregates) GetExact() *ExactAmounts {
+	if x != nil {
+		return x.Exact
+	}
+	return nil
+}
+
+func (x *LoanAggregates) GetRateBps() int64 {
+	if x != nil {
+		return x.RateBps
+	}
+	return 0
+}
+
+func (x *LoanAggregates) GetFullCostRate() int64 {
+	if x != nil {
+		return x.FullCostRate
+	}
+	return 0
+}
+
+func (x *LoanAggregates) GetFullCost() *Money {
+	if x != nil {
+		return x.FullCost
+	}
+	return nil
+}
+
+func (x *LoanAggregates) GetCosts() *CostBreakdown {
+	if x != nil {
+		return x.Costs
+	}
+	return nil
+}
+
+func (x *LoanAggregates) GetRatePeriods() []*RatePeriodSummary {
+	if x != nil {
+		return x.RatePeriods
+	}
+	return nil
+}
+
+func (x *LoanAggregates) GetKeyRate() *KeyRateInfo {
+	if x != nil {
+		return x.KeyRate
+	}
+	return nil
+}
+
+// Ключевая ставка, от которой посчитана плавающая ставка: rate_bps = key_rate_bps + spread_bps
+type KeyRateInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyRateBps    int64                  `protobuf:"varint,1,opt,name=key_rate_bps,json=keyRateBps,proto3" json:"key_rate_bps,omitempty"` // ключевая ставка в базисных пунктах
+	Date          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`                                  // дата, с которой действует ключевая ставка
+	SpreadBps     int64                  `protobuf:"varint,3,opt,name=spread_bps,json=spreadBps,proto3" json:"spread_bps,omitempty"`      // надбавка программы
+	Stale         bool                   `protobuf:"varint,4,opt,name=stale,proto3" json:"stale,omitempty"`                               // источник недоступен, взято последнее полученное значение
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyRateInfo) Reset() {
+	*x = KeyRateInfo{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyRateInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyRateInfo) ProtoMessage() {}
+
+func (x *KeyRateInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyRateInfo.ProtoReflect.Descriptor instead.
+func (*KeyRateInfo) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *KeyRateInfo) GetKeyRateBps() int64 {
+	if x != nil {
+		return x.KeyRateBps
+	}
+	return 0
+}
+
+func (x *KeyRateInfo) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *KeyRateInfo) GetSpreadBps() int64 {
+	if x != nil {
+		return x.SpreadBps
+	}
+	return 0
+}
+
+func (x *KeyRateInfo) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+// Итоги периода ступенчатой ставки. Аннуитет пересчитывается на остаток долга и срока в начале периода.
+type RatePeriodSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromMonth     int64                  `protobuf:"varint,1,opt,name=from_month,json=fromMonth,proto3" json:"from_month,omitempty"` // первый платеж периода
+	ToMonth       int64                  `protobuf:"varint,2,opt,name=to_month,json=toMonth,proto3" json:"to_month,omitempty"`       // последний платеж периода
+	RateBps       int64                  `protobuf:"varint,3,opt,name=rate_bps,json=rateBps,proto3" json:"rate_bps,omitempty"`       // ставка периода в базисных пунктах
+	Payment       *Money                 `protobuf:"bytes,4,opt,name=payment,proto3" json:"payment,omitempty"`                       // первый платеж периода
+	Interest      *Money                 `protobuf:"bytes,5,opt,name=interest,proto3" json:"interest,omitempty"`                     // проценты за период
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RatePeriodSummary) Reset() {
+	*x = RatePeriodSummary{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RatePeriodSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatePeriodSummary) ProtoMessage() {}
+
+func (x *RatePeriodSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatePeriodSummary.ProtoReflect.Descriptor instead.
+func (*RatePeriodSummary) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RatePeriodSummary) GetFromMonth() int64 {
+	if x != nil {
+		return x.FromMonth
+	}
+	return 0
+}
+
+func (x *RatePeriodSummary) GetToMonth() int64 {
+	if x != nil {
+		return x.ToMonth
+	}
+	return 0
+}
+
+func (x *RatePeriodSummary) GetRateBps() int64 {
+	if x != nil {
+		return x.RateBps
+	}
+	return 0
+}
+
+func (x *RatePeriodSummary) GetPayment() *Money {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+func (x *RatePeriodSummary) GetInterest() *Money {
+	if x != nil {
+		return x.Interest
+	}
+	return nil
+}
+
+// Непроцентные расходы за весь срок кредита
+type CostBreakdown struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OneOffFees    *Money                 `protobuf:"bytes,1,opt,name=one_off_fees,json=oneOffFees,proto3" json:"one_off_fees,omitempty"`  // разовые комиссии
+	MonthlyFees   *Money                 `protobuf:"bytes,2,opt,name=monthly_fees,json=monthlyFees,proto3" json:"monthly_fees,omitempty"` // ежемесячные комиссии
+	Insurance     *Money                 `protobuf:"bytes,3,opt,name=insurance,proto3" json:"insurance,omitempty"`                        // страховые премии
+	Total         *Money                 `protobuf:"bytes,4,opt,name=total,proto3" json:"total,omitempty"`                                // всего
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CostBreakdown) Reset() {
+	*x = CostBreakdown{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CostBreakdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CostBreakdown) ProtoMessage() {}
+
+func (x *CostBreakdown) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CostBreakdown.ProtoReflect.Descriptor instead.
+func (*CostBreakdown) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CostBreakdown) GetOneOffFees() *Money {
+	if x != nil {
+		return x.OneOffFees
+	}
+	return nil
+}
+
+func (x *CostBreakdown) GetMonthlyFees() *Money {
+	if x != nil {
+		return x.MonthlyFees
+	}
+	return nil
+}
+
+func (x *CostBreakdown) GetInsurance() *Money {
+	if x != nil {
+		return x.Insurance
+	}
+	return nil
+}
+
+func (x *CostBreakdown) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+// Денежная сумма с точностью до копейки
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rubles        int64                  `protobuf:"varint,1,opt,name=rubles,proto3" json:"rubles,omitempty"`   // рубли
+	Kopecks       int32                  `protobuf:"varint,2,opt,name=kopecks,proto3" json:"kopecks,omitempty"` // копейки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Money) GetRubles() int64 {
+	if x != nil {
+		return x.Rubles
+	}
+	return 0
+}
+
+func (x *Money) GetKopecks() int32 {
+	if x != nil {
+		return x.Kopecks
+	}
+	return 0
+}
+
+// Суммы по кредиту с точностью до копейки.
+// Рублевые поля LoanAggregates округлены из них: платежи вверх, переплата математически.
+type ExactAmounts struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	LoanSum        *Money                 `protobuf:"bytes,1,opt,name=loan_sum,json=loanSum,proto3" json:"loan_sum,omitempty"`                      // сумма кредита
+	MonthlyPayment *Money                 `protobuf:"bytes,2,opt,name=monthly_payment,json=monthlyPayment,proto3" json:"monthly_payment,omitempty"` // платеж в месяц (первый платеж)
+	Overpayment    *Money                 `protobuf:"bytes,3,opt,name=overpayment,proto3" json:"overpayment,omitempty"`                             // переплата, равна сумме процентов по графику
+	FirstPayment   *Money                 `protobuf:"bytes,4,opt,name=first_payment,json=firstPayment,proto3" json:"first_payment,omitempty"`       // первый платеж
+	LastPayment    *Money                 `protobuf:"bytes,5,opt,name=last_payment,json=lastPayment,proto3" json:"last_payment,omitempty"`          // последний платеж с учетом корректировки
+	TotalPayment   *Money                 `protobuf:"bytes,6,opt,name=total_payment,json=totalPayment,proto3" json:"total_payment,omitempty"`       // всего выплат по графику
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExactAmounts) Reset() {
+	*x = ExactAmounts{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExactAmounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExactAmounts) ProtoMessage() {}
+
+func (x *ExactAmounts) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExactAmounts.ProtoReflect.Descriptor instead.
+func (*ExactAmounts) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExactAmounts) GetLoanSum() *Money {
+	if x != nil {
+		return x.LoanSum
+	}
+	return nil
+}
+
+func (x *ExactAmounts) GetMonthlyPayment() *Money {
+	if x != nil {
+		return x.MonthlyPayment
+	}
+	return nil
+}
+
+func (x *ExactAmounts) GetOverpayment() *Money {
+	if x != nil {
+		return x.Overpayment
+	}
+	return nil
+}
+
+func (x *ExactAmounts) GetFirstPayment() *Money {
+	if x != nil {
+		return x.FirstPayment
+	}
+	return nil
+}
+
+func (x *ExactAmounts) GetLastPayment() *Money {
+	if x != nil {
+		return x.LastPayment
+	}
+	return nil
+}
+
+func (x *ExactAmounts) GetTotalPayment() *Money {
+	if x != nil {
+		return x.TotalPayment
+	}
+	return nil
+}
+
+// Итоговый ответ
+type LoanResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Params        *LoanParams            `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"`
+	Program       *LoanProgram           `protobuf:"bytes,2,opt,name=program,proto3" json:"program,omitempty"`
+	Aggregates    *LoanAggregates        `protobuf:"bytes,3,opt,name=aggregates,proto3" json:"aggregates,omitempty"`
+	PaymentType   PaymentType            `protobuf:"varint,4,opt,name=payment_type,json=paymentType,proto3,enum=entities.PaymentType" json:"payment_type,omitempty"` // схема погашения
+	Schedule      []*ScheduleRow         `protobuf:"bytes,5,rep,name=schedule,proto3" json:"schedule,omitempty"`                                                     // график (только для дифференцированных платежей и ступенчатой ставки)
+	Id            int64                  `protobuf:"varint,6,opt,name=id,proto3" json:"id,omitempty"`                                                                // id расчета в кеше
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                                  // время расчета
+	RateVersion   string                 `protobuf:"bytes,8,opt,name=rate_version,json=rateVersion,proto3" json:"rate_version,omitempty"`                            // версия таблицы ставок на дату выдачи, пусто — ставки реестра без версий
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoanResult) Reset() {
+	*x = LoanResult{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoanResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoanResult) ProtoMessage() {}
+
+func (x *LoanResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoanResult.ProtoReflect.Descriptor instead.
+func (*LoanResult) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LoanResult) GetParams() *LoanParams {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *LoanResult) GetProgram() *LoanProgram {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *LoanResult) GetAggregates() *LoanAggregates {
+	if x != nil {
+		return x.Aggregates
+	}
+	return nil
+}
+
+func (x *LoanResult) GetPaymentType() PaymentType {
+	if x != nil {
+		return x.PaymentType
+	}
+	return PaymentType_ANNUITY
+}
+
+func (x *LoanResult) GetSchedule() []*ScheduleRow {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+func (x *LoanResult) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LoanResult) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *LoanResult) GetRateVersion() string {
+	if x != nil {
+		return x.RateVersion
+	}
+	return ""
+}
+
+// Идентификатор расчета в кеше
+type CalculationID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculationID) Reset() {
+	*x = CalculationID{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculationID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculationID) ProtoMessage() {}
+
+func (x *CalculationID) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculationID.ProtoReflect.Descriptor instead.
+func (*CalculationID) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CalculationID) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// Обертка для ответа (если нужно)
+type LoanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *LoanResult            `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoanResponse) Reset() {
+	*x = LoanResponse{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoanResponse) ProtoMessage() {}
+
+func (x *LoanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoanResponse.ProtoReflect.Descriptor instead.
+func (*LoanResponse) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LoanResponse) GetResult() *LoanResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type CacheResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*LoanResult          `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // пустой, если страниц больше нет
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheResult) Reset() {
+	*x = CacheResult{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LoanRequest) String() string {
+func (x *CacheResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoanRequest) ProtoMessage() {}
+func (*CacheResult) ProtoMessage() {}
 
-func (x *LoanRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_protos_entities_loan_proto_msgTypes[0]
+func (x *CacheResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -57,64 +1142,58 @@ func (x *LoanRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoanRequest.ProtoReflect.Descriptor instead.
-func (*LoanRequest) Descriptor() ([]byte, []int) {
-	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{0}
-}
-
-func (x *LoanRequest) GetObjectCost() int64 {
-	if x != nil {
-		return x.ObjectCost
-	}
-	return 0
-}
-
-func (x *LoanRequest) GetInitialPayment() int64 {
-	if x != nil {
-		return x.InitialPayment
-	}
-	return 0
+// Deprecated: Use CacheResult.ProtoReflect.Descriptor instead.
+func (*CacheResult) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *LoanRequest) GetMonths() int64 {
+func (x *CacheResult) GetResults() []*LoanResult {
 	if x != nil {
-		return x.Months
+		return x.Results
 	}
-	return 0
+	return nil
 }
 
-func (x *LoanRequest) GetProgram() *LoanProgram {
+func (x *CacheResult) GetNextPageToken() string {
 	if x != nil {
-		return x.Program
+		return x.NextPageToken
 	}
-	return nil
+	return ""
 }
 
-// Блок программы кредита
-type LoanProgram struct {
+// Фильтры, сортировка и пагинация для GET /cache. Нулевые значения не фильтруют.
+type CacheRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Salary        bool                   `protobuf:"varint,1,opt,name=salary,proto3" json:"salary,omitempty"`     // корпоративная программа
-	Military      bool                   `protobuf:"varint,2,opt,name=military,proto3" json:"military,omitempty"` // военная ипотека
-	Base          bool                   `protobuf:"varint,3,opt,name=base,proto3" json:"base,omitempty"`         // базовая программа
+	Program       *LoanProgram           `protobuf:"bytes,1,opt,name=program,proto3" json:"program,omitempty"`
+	MinLoanSum    int64                  `protobuf:"varint,2,opt,name=min_loan_sum,json=minLoanSum,proto3" json:"min_loan_sum,omitempty"`
+	MaxLoanSum    int64                  `protobuf:"varint,3,opt,name=max_loan_sum,json=maxLoanSum,proto3" json:"max_loan_sum,omitempty"`
+	MinMonths     int64                  `protobuf:"varint,4,opt,name=min_months,json=minMonths,proto3" json:"min_months,omitempty"`
+	MaxMonths     int64                  `protobuf:"varint,5,opt,name=max_months,json=maxMonths,proto3" json:"max_months,omitempty"`
+	CreatedFrom   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"` // включительно
+	CreatedTo     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`       // не включительно
+	Sort          CacheSort              `protobuf:"varint,8,opt,name=sort,proto3,enum=entities.CacheSort" json:"sort,omitempty"`
+	Desc          bool                   `protobuf:"varint,9,opt,name=desc,proto3" json:"desc,omitempty"`
+	PageSize      int32                  `protobuf:"varint,10,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // по умолчанию 50, не больше 1000
+	PageToken     string                 `protobuf:"bytes,11,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token из предыдущего ответа
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LoanProgram) Reset() {
-	*x = LoanProgram{}
-	mi := &file_api_protos_entities_loan_proto_msgTypes[1]
+func (x *CacheRequest) Reset() {
+	*x = CacheRequest{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LoanProgram) String() string {
+func (x *CacheRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoanProgram) ProtoMessage() {}
+func (*CacheRequest) ProtoMessage() {}
 
-func (x *LoanProgram) ProtoReflect() protoreflect.Message {
-	mi := &file_api_protos_entities_loan_proto_msgTypes[1]
+func (x *CacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -125,134 +1204,117 @@ func (x *LoanProgram) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoanProgram.ProtoReflect.Descriptor instead.
-func (*LoanProgram) Descriptor() ([]byte, []int) {
-	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use CacheRequest.ProtoReflect.Descriptor instead.
+func (*CacheRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *LoanProgram) GetSalary() bool {
+func (x *CacheRequest) GetProgram() *LoanProgram {
 	if x != nil {
-		return x.Salary
+		return x.Program
 	}
-	return false
+	return nil
 }
 
-func (x *LoanProgram) GetMilitary() bool {
+func (x *CacheRequest) GetMinLoanSum() int64 {
 	if x != nil {
-		return x.Military
+		return x.MinLoanSum
 	}
-	return false
+	return 0
 }
 
-func (x *LoanProgram) GetBase() bool {
+func (x *CacheRequest) GetMaxLoanSum() int64 {
 	if x != nil {
-		return x.Base
+		return x.MaxLoanSum
 	}
-	return false
-}
-
-// Блок агрегированных данных
-type LoanAggregates struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Rate            int64                  `protobuf:"varint,1,opt,name=rate,proto3" json:"rate,omitempty"`                                               // ставка (% годовых)
-	LoanSum         int64                  `protobuf:"varint,2,opt,name=loan_sum,json=loanSum,proto3" json:"loan_sum,omitempty"`                          // сумма кредита
-	MonthlyPayment  int64                  `protobuf:"varint,3,opt,name=monthly_payment,json=monthlyPayment,proto3" json:"monthly_payment,omitempty"`     // платеж в месяц
-	Overpayment     int64                  `protobuf:"varint,4,opt,name=overpayment,proto3" json:"overpayment,omitempty"`                                 // переплата
-	LastPaymentDate *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_payment_date,json=lastPaymentDate,proto3" json:"last_payment_date,omitempty"` // дата последнего платежа
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
-}
-
-func (x *LoanAggregates) Reset() {
-	*x = LoanAggregates{}
-	mi := &file_api_protos_entities_loan_proto_msgTypes[2]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	return 0
 }
 
-func (x *LoanAggregates) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *CacheRequest) GetMinMonths() int64 {
+	if x != nil {
+		return x.MinMonths
+	}
+	return 0
 }
 
-func (*LoanAggregates) ProtoMessage() {}
-
-func (x *LoanAggregates) ProtoReflect() protoreflect.Message {
-	mi := &file_api_protos_entities_loan_proto_msgTypes[2]
+func (x *CacheRequest) GetMaxMonths() int64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.MaxMonths
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use LoanAggregates.ProtoReflect.Descriptor instead.
-func (*LoanAggregates) Descriptor() ([]byte, []int) {
-	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{2}
+func (x *CacheRequest) GetCreatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return nil
 }
 
-func (x *LoanAggregates) GetRate() int64 {
+func (x *CacheRequest) GetCreatedTo() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Rate
+		return x.CreatedTo
 	}
-	return 0
+	return nil
 }
 
-func (x *LoanAggregates) GetLoanSum() int64 {
+func (x *CacheRequest) GetSort() CacheSort {
 	if x != nil {
-		return x.LoanSum
+		return x.Sort
 	}
-	return 0
+	return CacheSort_CREATED_AT
 }
 
-func (x *LoanAggregates) GetMonthlyPayment() int64 {
+func (x *CacheRequest) GetDesc() bool {
 	if x != nil {
-		return x.MonthlyPayment
+		return x.Desc
 	}
-	return 0
+	return false
 }
 
-func (x *LoanAggregates) GetOverpayment() int64 {
+func (x *CacheRequest) GetPageSize() int32 {
 	if x != nil {
-		return x.Overpayment
+		return x.PageSize
 	}
 	return 0
 }
 
-func (x *LoanAggregates) GetLastPaymentDate() *timestamppb.Timestamp {
+func (x *CacheRequest) GetPageToken() string {
 	if x != nil {
-		return x.LastPaymentDate
+		return x.PageToken
 	}
-	return nil
+	return ""
 }
 
-// Итоговый ответ
-type LoanResult struct {
+// Строка графика платежей
+type ScheduleRow struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Params        *LoanParams            `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"`
-	Program       *LoanProgram           `protobuf:"bytes,2,opt,name=program,proto3" json:"program,omitempty"`
-	Aggregates    *LoanAggregates        `protobuf:"bytes,3,opt,name=aggregates,proto3" json:"aggregates,omitempty"`
+	Month         int64                  `protobuf:"varint,1,opt,name=month,proto3" json:"month,omitempty"`                               // номер платежа
+	PaymentDate   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=payment_date,json=paymentDate,proto3" json:"payment_date,omitempty"` // дата платежа
+	Payment       *Money                 `protobuf:"bytes,7,opt,name=payment,proto3" json:"payment,omitempty"`                            // платеж
+	Interest      *Money                 `protobuf:"bytes,8,opt,name=interest,proto3" json:"interest,omitempty"`                          // в т.ч. проценты
+	Principal     *Money                 `protobuf:"bytes,9,opt,name=principal,proto3" json:"principal,omitempty"`                        // в т.ч. основной долг
+	Balance       *Money                 `protobuf:"bytes,10,opt,name=balance,proto3" json:"balance,omitempty"`                           // остаток долга после платежа
+	Prepayment    *Money                 `protobuf:"bytes,11,opt,name=prepayment,proto3" json:"prepayment,omitempty"`                     // досрочный платеж сверх планового (только в /prepayment)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LoanResult) Reset() {
-	*x = LoanResult{}
-	mi := &file_api_protos_entities_loan_proto_msgTypes[3]
+func (x *ScheduleRow) Reset() {
+	*x = ScheduleRow{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LoanResult) String() string {
+func (x *ScheduleRow) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoanResult) ProtoMessage() {}
+func (*ScheduleRow) ProtoMessage() {}
 
-func (x *LoanResult) ProtoReflect() protoreflect.Message {
-	mi := &file_api_protos_entities_loan_proto_msgTypes[3]
+func (x *ScheduleRow) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -263,99 +1325,86 @@ func (x *LoanResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoanResult.ProtoReflect.Descriptor instead.
-func (*LoanResult) Descriptor() ([]byte, []int) {
-	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ScheduleRow.ProtoReflect.Descriptor instead.
+func (*ScheduleRow) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *LoanResult) GetParams() *LoanParams {
+func (x *ScheduleRow) GetMonth() int64 {
 	if x != nil {
-		return x.Params
+		return x.Month
 	}
-	return nil
+	return 0
 }
 
-func (x *LoanResult) GetProgram() *LoanProgram {
+func (x *ScheduleRow) GetPaymentDate() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Program
+		return x.PaymentDate
 	}
 	return nil
 }
 
-func (x *LoanResult) GetAggregates() *LoanAggregates {
+func (x *ScheduleRow) GetPayment() *Money {
 	if x != nil {
-		return x.Aggregates
+		return x.Payment
 	}
 	return nil
 }
 
-// Обертка для ответа (если нужно)
-type LoanResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Result        *LoanResult            `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *LoanResponse) Reset() {
-	*x = LoanResponse{}
-	mi := &file_api_protos_entities_loan_proto_msgTypes[4]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *LoanResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ScheduleRow) GetInterest() *Money {
+	if x != nil {
+		return x.Interest
+	}
+	return nil
 }
 
-func (*LoanResponse) ProtoMessage() {}
-
-func (x *LoanResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_protos_entities_loan_proto_msgTypes[4]
+func (x *ScheduleRow) GetPrincipal() *Money {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Principal
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use LoanResponse.ProtoReflect.Descriptor instead.
-func (*LoanResponse) Descriptor() ([]byte, []int) {
-	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{4}
+func (x *ScheduleRow) GetBalance() *Money {
+	if x != nil {
+		return x.Balance
+	}
+	return nil
 }
 
-func (x *LoanResponse) GetResult() *LoanResult {
+func (x *ScheduleRow) GetPrepayment() *Money {
 	if x != nil {
-		return x.Result
+		return x.Prepayment
 	}
 	return nil
 }
 
-type CacheResult struct {
+// График платежей по кредиту
+type ScheduleResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Results       []*LoanResult          `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Params        *LoanParams            `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"`
+	Program       *LoanProgram           `protobuf:"bytes,2,opt,name=program,proto3" json:"program,omitempty"`
+	Aggregates    *LoanAggregates        `protobuf:"bytes,3,opt,name=aggregates,proto3" json:"aggregates,omitempty"`
+	Rows          []*ScheduleRow         `protobuf:"bytes,4,rep,name=rows,proto3" json:"rows,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CacheResult) Reset() {
-	*x = CacheResult{}
-	mi := &file_api_protos_entities_loan_proto_msgTypes[5]
+func (x *ScheduleResult) Reset() {
+	*x = ScheduleResult{}
+	mi := &file_api_protos_entities_loan_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CacheResult) String() string {
+func (x *ScheduleResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CacheResult) ProtoMessage() {}
+func (*ScheduleResult) ProtoMessage() {}
 
-func (x *CacheResult) ProtoReflect() protoreflect.Message {
-	mi := &file_api_protos_entities_loan_proto_msgTypes[5]
+func (x *ScheduleResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_loan_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -366,14 +1415,35 @@ func (x *CacheResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CacheResult.ProtoReflect.Descriptor instead.
-func (*CacheResult) Descriptor() ([]byte, []int) {
-	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ScheduleResult.ProtoReflect.Descriptor instead.
+func (*ScheduleResult) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *CacheResult) GetResults() []*LoanResult {
+func (x *ScheduleResult) GetParams() *LoanParams {
 	if x != nil {
-		return x.Results
+		return x.Params
+	}
+	return nil
+}
+
+func (x *ScheduleResult) GetProgram() *LoanProgram {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *ScheduleResult) GetAggregates() *LoanAggregates {
+	if x != nil {
+		return x.Aggregates
+	}
+	return nil
+}
+
+func (x *ScheduleResult) GetRows() []*ScheduleRow {
+	if x != nil {
+		return x.Rows
 	}
 	return nil
 }
@@ -384,13 +1454,16 @@ type LoanParams struct {
 	ObjectCost     int64                  `protobuf:"varint,1,opt,name=object_cost,json=objectCost,proto3" json:"object_cost,omitempty"`             // стоимость объекта (рубли)
 	InitialPayment int64                  `protobuf:"varint,2,opt,name=initial_payment,json=initialPayment,proto3" json:"initial_payment,omitempty"` // первоначальный взнос
 	Months         int64                  `protobuf:"varint,3,opt,name=months,proto3" json:"months,omitempty"`                                       // срок (месяцы)
+	IssueDate      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=issue_date,json=issueDate,proto3" json:"issue_date,omitempty"`                 // дата выдачи
+	PaymentDay     int32                  `protobuf:"varint,5,opt,name=payment_day,json=paymentDay,proto3" json:"payment_day,omitempty"`             // день платежа
+	Costs          *LoanCosts             `protobuf:"bytes,6,opt,name=costs,proto3" json:"costs,omitempty"`                                          // комиссии и страховка из запроса
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
 func (x *LoanParams) Reset() {
 	*x = LoanParams{}
-	mi := &file_api_protos_entities_loan_proto_msgTypes[6]
+	mi := &file_api_protos_entities_loan_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -402,7 +1475,7 @@ func (x *LoanParams) String() string {
 func (*LoanParams) ProtoMessage() {}
 
 func (x *LoanParams) ProtoReflect() protoreflect.Message {
-	mi := &file_api_protos_entities_loan_proto_msgTypes[6]
+	mi := &file_api_protos_entities_loan_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -415,7 +1488,7 @@ func (x *LoanParams) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoanParams.ProtoReflect.Descriptor instead.
 func (*LoanParams) Descriptor() ([]byte, []int) {
-	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{6}
+	return file_api_protos_entities_loan_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *LoanParams) GetObjectCost() int64 {
@@ -439,44 +1512,181 @@ func (x *LoanParams) GetMonths() int64 {
 	return 0
 }
 
+func (x *LoanParams) GetIssueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssueDate
+	}
+	return nil
+}
+
+func (x *LoanParams) GetPaymentDay() int32 {
+	if x != nil {
+		return x.PaymentDay
+	}
+	return 0
+}
+
+func (x *LoanParams) GetCosts() *LoanCosts {
+	if x != nil {
+		return x.Costs
+	}
+	return nil
+}
+
 var File_api_protos_entities_loan_proto protoreflect.FileDescriptor
 
 const file_api_protos_entities_loan_proto_rawDesc = "" +
 	"\n" +
-	"\x1eapi/protos/entities/loan.proto\x12\bentities\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa0\x01\n" +
+	"\x1eapi/protos/entities/loan.proto\x12\bentities\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9a\x03\n" +
 	"\vLoanRequest\x12\x1f\n" +
 	"\vobject_cost\x18\x01 \x01(\x03R\n" +
 	"objectCost\x12'\n" +
 	"\x0finitial_payment\x18\x02 \x01(\x03R\x0einitialPayment\x12\x16\n" +
 	"\x06months\x18\x03 \x01(\x03R\x06months\x12/\n" +
-	"\aprogram\x18\x04 \x01(\v2\x15.entities.LoanProgramR\aprogram\"U\n" +
+	"\aprogram\x18\x04 \x01(\v2\x15.entities.LoanProgramR\aprogram\x128\n" +
+	"\fpayment_type\x18\x05 \x01(\x0e2\x15.entities.PaymentTypeR\vpaymentType\x129\n" +
+	"\n" +
+	"issue_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tissueDate\x12\x1f\n" +
+	"\vpayment_day\x18\a \x01(\x05R\n" +
+	"paymentDay\x12)\n" +
+	"\x05costs\x18\b \x01(\v2\x13.entities.LoanCostsR\x05costs\x127\n" +
+	"\frate_periods\x18\t \x03(\v2\x14.entities.RatePeriodR\vratePeriods\"?\n" +
+	"\n" +
+	"RatePeriod\x12\x16\n" +
+	"\x06months\x18\x01 \x01(\x03R\x06months\x12\x19\n" +
+	"\brate_bps\x18\x02 \x01(\x03R\arateBps\"\xd8\x01\n" +
+	"\tLoanCosts\x12/\n" +
+	"\vone_off_fee\x18\x01 \x01(\v2\x0f.entities.MoneyR\toneOffFee\x120\n" +
+	"\vmonthly_fee\x18\x02 \x01(\v2\x0f.entities.MoneyR\n" +
+	"monthlyFee\x12:\n" +
+	"\x10annual_insurance\x18\x03 \x01(\v2\x0f.entities.MoneyR\x0fannualInsurance\x12,\n" +
+	"\x12insurance_rate_bps\x18\x04 \x01(\x03R\x10insuranceRateBps\"i\n" +
 	"\vLoanProgram\x12\x16\n" +
 	"\x06salary\x18\x01 \x01(\bR\x06salary\x12\x1a\n" +
 	"\bmilitary\x18\x02 \x01(\bR\bmilitary\x12\x12\n" +
-	"\x04base\x18\x03 \x01(\bR\x04base\"\xd2\x01\n" +
+	"\x04base\x18\x03 \x01(\bR\x04base\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\tR\x04code\"\xd8\x04\n" +
 	"\x0eLoanAggregates\x12\x12\n" +
 	"\x04rate\x18\x01 \x01(\x03R\x04rate\x12\x19\n" +
 	"\bloan_sum\x18\x02 \x01(\x03R\aloanSum\x12'\n" +
 	"\x0fmonthly_payment\x18\x03 \x01(\x03R\x0emonthlyPayment\x12 \n" +
 	"\voverpayment\x18\x04 \x01(\x03R\voverpayment\x12F\n" +
-	"\x11last_payment_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0flastPaymentDate\"\xa5\x01\n" +
+	"\x11last_payment_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0flastPaymentDate\x12#\n" +
+	"\rfirst_payment\x18\x06 \x01(\x03R\ffirstPayment\x12!\n" +
+	"\flast_payment\x18\a \x01(\x03R\vlastPayment\x12,\n" +
+	"\x05exact\x18\b \x01(\v2\x16.entities.ExactAmountsR\x05exact\x12\x19\n" +
+	"\brate_bps\x18\t \x01(\x03R\arateBps\x12$\n" +
+	"\x0efull_cost_rate\x18\n" +
+	" \x01(\x03R\ffullCostRate\x12,\n" +
+	"\tfull_cost\x18\v \x01(\v2\x0f.entities.MoneyR\bfullCost\x12-\n" +
+	"\x05costs\x18\f \x01(\v2\x17.entities.CostBreakdownR\x05costs\x12>\n" +
+	"\frate_periods\x18\r \x03(\v2\x1b.entities.RatePeriodSummaryR\vratePeriods\x120\n" +
+	"\bkey_rate\x18\x0e \x01(\v2\x15.entities.KeyRateInfoR\akeyRate\"\x94\x01\n" +
+	"\vKeyRateInfo\x12 \n" +
+	"\fkey_rate_bps\x18\x01 \x01(\x03R\n" +
+	"keyRateBps\x12.\n" +
+	"\x04date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x1d\n" +
+	"\n" +
+	"spread_bps\x18\x03 \x01(\x03R\tspreadBps\x12\x14\n" +
+	"\x05stale\x18\x04 \x01(\bR\x05stale\"\xc0\x01\n" +
+	"\x11RatePeriodSummary\x12\x1d\n" +
+	"\n" +
+	"from_month\x18\x01 \x01(\x03R\tfromMonth\x12\x19\n" +
+	"\bto_month\x18\x02 \x01(\x03R\atoMonth\x12\x19\n" +
+	"\brate_bps\x18\x03 \x01(\x03R\arateBps\x12)\n" +
+	"\apayment\x18\x04 \x01(\v2\x0f.entities.MoneyR\apayment\x12+\n" +
+	"\binterest\x18\x05 \x01(\v2\x0f.entities.MoneyR\binterest\"\xcc\x01\n" +
+	"\rCostBreakdown\x121\n" +
+	"\fone_off_fees\x18\x01 \x01(\v2\x0f.entities.MoneyR\n" +
+	"oneOffFees\x122\n" +
+	"\fmonthly_fees\x18\x02 \x01(\v2\x0f.entities.MoneyR\vmonthlyFees\x12-\n" +
+	"\tinsurance\x18\x03 \x01(\v2\x0f.entities.MoneyR\tinsurance\x12%\n" +
+	"\x05total\x18\x04 \x01(\v2\x0f.entities.MoneyR\x05total\"9\n" +
+	"\x05Money\x12\x16\n" +
+	"\x06rubles\x18\x01 \x01(\x03R\x06rubles\x12\x18\n" +
+	"\akopecks\x18\x02 \x01(\x05R\akopecks\"\xc7\x02\n" +
+	"\fExactAmounts\x12*\n" +
+	"\bloan_sum\x18\x01 \x01(\v2\x0f.entities.MoneyR\aloanSum\x128\n" +
+	"\x0fmonthly_payment\x18\x02 \x01(\v2\x0f.entities.MoneyR\x0emonthlyPayment\x121\n" +
+	"\voverpayment\x18\x03 \x01(\v2\x0f.entities.MoneyR\voverpayment\x124\n" +
+	"\rfirst_payment\x18\x04 \x01(\v2\x0f.entities.MoneyR\ffirstPayment\x122\n" +
+	"\flast_payment\x18\x05 \x01(\v2\x0f.entities.MoneyR\vlastPayment\x124\n" +
+	"\rtotal_payment\x18\x06 \x01(\v2\x0f.entities.MoneyR\ftotalPayment\"\x80\x03\n" +
 	"\n" +
 	"LoanResult\x12,\n" +
 	"\x06params\x18\x01 \x01(\v2\x14.entities.LoanParamsR\x06params\x12/\n" +
 	"\aprogram\x18\x02 \x01(\v2\x15.entities.LoanProgramR\aprogram\x128\n" +
 	"\n" +
 	"aggregates\x18\x03 \x01(\v2\x18.entities.LoanAggregatesR\n" +
-	"aggregates\"<\n" +
+	"aggregates\x128\n" +
+	"\fpayment_type\x18\x04 \x01(\x0e2\x15.entities.PaymentTypeR\vpaymentType\x121\n" +
+	"\bschedule\x18\x05 \x03(\v2\x15.entities.ScheduleRowR\bschedule\x12\x0e\n" +
+	"\x02id\x18\x06 \x01(\x03R\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12!\n" +
+	"\frate_version\x18\b \x01(\tR\vrateVersion\"\x1f\n" +
+	"\rCalculationID\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"<\n" +
 	"\fLoanResponse\x12,\n" +
-	"\x06result\x18\x01 \x01(\v2\x14.entities.LoanResultR\x06result\"=\n" +
+	"\x06result\x18\x01 \x01(\v2\x14.entities.LoanResultR\x06result\"e\n" +
 	"\vCacheResult\x12.\n" +
-	"\aresults\x18\x01 \x03(\v2\x14.entities.LoanResultR\aresults\"n\n" +
+	"\aresults\x18\x01 \x03(\v2\x14.entities.LoanResultR\aresults\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xb4\x03\n" +
+	"\fCacheRequest\x12/\n" +
+	"\aprogram\x18\x01 \x01(\v2\x15.entities.LoanProgramR\aprogram\x12 \n" +
+	"\fmin_loan_sum\x18\x02 \x01(\x03R\n" +
+	"minLoanSum\x12 \n" +
+	"\fmax_loan_sum\x18\x03 \x01(\x03R\n" +
+	"maxLoanSum\x12\x1d\n" +
+	"\n" +
+	"min_months\x18\x04 \x01(\x03R\tminMonths\x12\x1d\n" +
+	"\n" +
+	"max_months\x18\x05 \x01(\x03R\tmaxMonths\x12=\n" +
+	"\fcreated_from\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedFrom\x129\n" +
+	"\n" +
+	"created_to\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedTo\x12'\n" +
+	"\x04sort\x18\b \x01(\x0e2\x13.entities.CacheSortR\x04sort\x12\x12\n" +
+	"\x04desc\x18\t \x01(\bR\x04desc\x12\x1b\n" +
+	"\tpage_size\x18\n" +
+	" \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\v \x01(\tR\tpageToken\"\xcb\x02\n" +
+	"\vScheduleRow\x12\x14\n" +
+	"\x05month\x18\x01 \x01(\x03R\x05month\x12=\n" +
+	"\fpayment_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vpaymentDate\x12)\n" +
+	"\apayment\x18\a \x01(\v2\x0f.entities.MoneyR\apayment\x12+\n" +
+	"\binterest\x18\b \x01(\v2\x0f.entities.MoneyR\binterest\x12-\n" +
+	"\tprincipal\x18\t \x01(\v2\x0f.entities.MoneyR\tprincipal\x12)\n" +
+	"\abalance\x18\n" +
+	" \x01(\v2\x0f.entities.MoneyR\abalance\x12/\n" +
+	"\n" +
+	"prepayment\x18\v \x01(\v2\x0f.entities.MoneyR\n" +
+	"prepaymentJ\x04\b\x03\x10\a\"\xd4\x01\n" +
+	"\x0eScheduleResult\x12,\n" +
+	"\x06params\x18\x01 \x01(\v2\x14.entities.LoanParamsR\x06params\x12/\n" +
+	"\aprogram\x18\x02 \x01(\v2\x15.entities.LoanProgramR\aprogram\x128\n" +
+	"\n" +
+	"aggregates\x18\x03 \x01(\v2\x18.entities.LoanAggregatesR\n" +
+	"aggregates\x12)\n" +
+	"\x04rows\x18\x04 \x03(\v2\x15.entities.ScheduleRowR\x04rows\"\xf5\x01\n" +
 	"\n" +
 	"LoanParams\x12\x1f\n" +
 	"\vobject_cost\x18\x01 \x01(\x03R\n" +
 	"objectCost\x12'\n" +
 	"\x0finitial_payment\x18\x02 \x01(\x03R\x0einitialPayment\x12\x16\n" +
-	"\x06months\x18\x03 \x01(\x03R\x06monthsB4Z2github.com/Dorji/sberInterview/api/protos/entitiesb\x06proto3"
+	"\x06months\x18\x03 \x01(\x03R\x06months\x129\n" +
+	"\n" +
+	"issue_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tissueDate\x12\x1f\n" +
+	"\vpayment_day\x18\x05 \x01(\x05R\n" +
+	"paymentDay\x12)\n" +
+	"\x05costs\x18\x06 \x01(\v2\x13.entities.LoanCostsR\x05costs*.\n" +
+	"\vPaymentType\x12\v\n" +
+	"\aANNUITY\x10\x00\x12\x12\n" +
+	"\x0eDIFFERENTIATED\x10\x01*0\n" +
+	"\tCacheSort\x12\x0e\n" +
+	"\n" +
+	"CREATED_AT\x10\x00\x12\x13\n" +
+	"\x0fMONTHLY_PAYMENT\x10\x01B4Z2github.com/Dorji/sberInterview/api/protos/entitiesb\x06proto3"
 
 var (
 	file_api_protos_entities_loan_proto_rawDescOnce sync.Once
@@ -490,30 +1700,88 @@ func file_api_protos_entities_loan_proto_rawDescGZIP() []byte {
 	return file_api_protos_entities_loan_proto_rawDescData
 }
 
-var file_api_protos_entities_loan_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_api_protos_entities_loan_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_api_protos_entities_loan_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_api_protos_entities_loan_proto_goTypes = []any{
-	(*LoanRequest)(nil),           // 0: entities.LoanRequest
-	(*LoanProgram)(nil),           // 1: entities.LoanProgram
-	(*LoanAggregates)(nil),        // 2: entities.LoanAggregates
-	(*LoanResult)(nil),            // 3: entities.LoanResult
-	(*LoanResponse)(nil),          // 4: entities.LoanResponse
-	(*CacheResult)(nil),           // 5: entities.CacheResult
-	(*LoanParams)(nil),            // 6: entities.LoanParams
-	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+	(PaymentType)(0),              // 0: entities.PaymentType
+	(CacheSort)(0),                // 1: entities.CacheSort
+	(*LoanRequest)(nil),           // 2: entities.LoanRequest
+	(*RatePeriod)(nil),            // 3: entities.RatePeriod
+	(*LoanCosts)(nil),             // 4: entities.LoanCosts
+	(*LoanProgram)(nil),           // 5: entities.LoanProgram
+	(*LoanAggregates)(nil),        // 6: entities.LoanAggregates
+	(*KeyRateInfo)(nil),           // 7: entities.KeyRateInfo
+	(*RatePeriodSummary)(nil),     // 8: entities.RatePeriodSummary
+	(*CostBreakdown)(nil),         // 9: entities.CostBreakdown
+	(*Money)(nil),                 // 10: entities.Money
+	(*ExactAmounts)(nil),          // 11: entities.ExactAmounts
+	(*LoanResult)(nil),            // 12: entities.LoanResult
+	(*CalculationID)(nil),         // 13: entities.CalculationID
+	(*LoanResponse)(nil),          // 14: entities.LoanResponse
+	(*CacheResult)(nil),           // 15: entities.CacheResult
+	(*CacheRequest)(nil),          // 16: entities.CacheRequest
+	(*ScheduleRow)(nil),           // 17: entities.ScheduleRow
+	(*ScheduleResult)(nil),        // 18: entities.ScheduleResult
+	(*LoanParams)(nil),            // 19: entities.LoanParams
+	(*timestamppb.Timestamp)(nil), // 20: google.protobuf.Timestamp
 }
 var file_api_protos_entities_loan_proto_depIdxs = []int32{
-	1, // 0: entities.LoanRequest.program:type_name -> entities.LoanProgram
-	7, // 1: entities.LoanAggregates.last_payment_date:type_name -> google.protobuf.Timestamp
-	6, // 2: entities.LoanResult.params:type_name -> entities.LoanParams
-	1, // 3: entities.LoanResult.program:type_name -> entities.LoanProgram
-	2, // 4: entities.LoanResult.aggregates:type_name -> entities.LoanAggregates
-	3, // 5: entities.LoanResponse.result:type_name -> entities.LoanResult
-	3, // 6: entities.CacheResult.results:type_name -> entities.LoanResult
-	7, // [7:7] is the sub-list for method output_type
-	7, // [7:7] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	7, // [7:7] is the sub-list for extension extendee
-	0, // [0:7] is the sub-list for field type_name
+	5,  // 0: entities.LoanRequest.program:type_name -> entities.LoanProgram
+	0,  // 1: entities.LoanRequest.payment_type:type_name -> entities.PaymentType
+	20, // 2: entities.LoanRequest.issue_date:type_name -> google.protobuf.Timestamp
+	4,  // 3: entities.LoanRequest.costs:type_name -> entities.LoanCosts
+	3,  // 4: entities.LoanRequest.rate_periods:type_name -> entities.RatePeriod
+	10, // 5: entities.LoanCosts.one_off_fee:type_name -> entities.Money
+	10, // 6: entities.LoanCosts.monthly_fee:type_name -> entities.Money
+	10, // 7: entities.LoanCosts.annual_insurance:type_name -> entities.Money
+	20, // 8: entities.LoanAggregates.last_payment_date:type_name -> google.protobuf.Timestamp
+	11, // 9: entities.LoanAggregates.exact:type_name -> entities.ExactAmounts
+	10, // 10: entities.LoanAggregates.full_cost:type_name -> entities.Money
+	9,  // 11: entities.LoanAggregates.costs:type_name -> entities.CostBreakdown
+	8,  // 12: entities.LoanAggregates.rate_periods:type_name -> entities.RatePeriodSummary
+	7,  // 13: entities.LoanAggregates.key_rate:type_name -> entities.KeyRateInfo
+	20, // 14: entities.KeyRateInfo.date:type_name -> google.protobuf.Timestamp
+	10, // 15: entities.RatePeriodSummary.payment:type_name -> entities.Money
+	10, // 16: entities.RatePeriodSummary.interest:type_name -> entities.Money
+	10, // 17: entities.CostBreakdown.one_off_fees:type_name -> entities.Money
+	10, // 18: entities.CostBreakdown.monthly_fees:type_name -> entities.Money
+	10, // 19: entities.CostBreakdown.insurance:type_name -> entities.Money
+	10, // 20: entities.CostBreakdown.total:type_name -> entities.Money
+	10, // 21: entities.ExactAmounts.loan_sum:type_name -> entities.Money
+	10, // 22: entities.ExactAmounts.monthly_payment:type_name -> entities.Money
+	10, // 23: entities.ExactAmounts.overpayment:type_name -> entities.Money
+	10, // 24: entities.ExactAmounts.first_payment:type_name -> entities.Money
+	10, // 25: entities.ExactAmounts.last_payment:type_name -> entities.Money
+	10, // 26: entities.ExactAmounts.total_payment:type_name -> entities.Money
+	19, // 27: entities.LoanResult.params:type_name -> entities.LoanParams
+	5,  // 28: entities.LoanResult.program:type_name -> entities.LoanProgram
+	6,  // 29: entities.LoanResult.aggregates:type_name -> entities.LoanAggregates
+	0,  // 30: entities.LoanResult.payment_type:type_name -> entities.PaymentType
+	17, // 31: entities.LoanResult.schedule:type_name -> entities.ScheduleRow
+	20, // 32: entities.LoanResult.created_at:type_name -> google.protobuf.Timestamp
+	12, // 33: entities.LoanResponse.result:type_name -> entities.LoanResult
+	12, // 34: entities.CacheResult.results:type_name -> entities.LoanResult
+	5,  // 35: entities.CacheRequest.program:type_name -> entities.LoanProgram
+	20, // 36: entities.CacheRequest.created_from:type_name -> google.protobuf.Timestamp
+	20, // 37: entities.CacheRequest.created_to:type_name -> google.protobuf.Timestamp
+	1,  // 38: entities.CacheRequest.sort:type_name -> entities.CacheSort
+	20, // 39: entities.ScheduleRow.payment_date:type_name -> google.protobuf.Timestamp
+	10, // 40: entities.ScheduleRow.payment:type_name -> entities.Money
+	10, // 41: entities.ScheduleRow.interest:type_name -> entities.Money
+	10, // 42: entities.ScheduleRow.principal:type_name -> entities.Money
+	10, // 43: entities.ScheduleRow.balance:type_name -> entities.Money
+	10, // 44: entities.ScheduleRow.prepayment:type_name -> entities.Money
+	19, // 45: entities.ScheduleResult.params:type_name -> entities.LoanParams
+	5,  // 46: entities.ScheduleResult.program:type_name -> entities.LoanProgram
+	6,  // 47: entities.ScheduleResult.aggregates:type_name -> entities.LoanAggregates
+	17, // 48: entities.ScheduleResult.rows:type_name -> entities.ScheduleRow
+	20, // 49: entities.LoanParams.issue_date:type_name -> google.protobuf.Timestamp
+	4,  // 50: entities.LoanParams.costs:type_name -> entities.LoanCosts
+	51, // [51:51] is the sub-list for method output_type
+	51, // [51:51] is the sub-list for method input_type
+	51, // [51:51] is the sub-list for extension type_name
+	51, // [51:51] is the sub-list for extension extendee
+	0,  // [0:51] is the sub-list for field type_name
 }
 
 func init() { file_api_protos_entities_loan_proto_init() }
@@ -526,13 +1794,14 @@ func file_api_protos_entities_loan_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_protos_entities_loan_proto_rawDesc), len(file_api_protos_entities_loan_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      2,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_api_protos_entities_loan_proto_goTypes,
 		DependencyIndexes: file_api_protos_entities_loan_proto_depIdxs,
+		EnumInfos:         file_api_protos_entities_loan_proto_enumTypes,
 		MessageInfos:      file_api_protos_entities_loan_proto_msgTypes,
 	}.Build()
 	File_api_protos_entities_loan_proto = out.File
@@ -0,0 +1,449 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: api/protos/entities/prepayment.proto
+
+package entities
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Что уменьшает досрочный платеж
+type PrepaymentMode int32
+
+const (
+	PrepaymentMode_REDUCE_TERM    PrepaymentMode = 0 // срок, платеж остается прежним
+	PrepaymentMode_REDUCE_PAYMENT PrepaymentMode = 1 // платеж, срок остается прежним
+)
+
+// Enum value maps for PrepaymentMode.
+var (
+	PrepaymentMode_name = map[int32]string{
+		0: "REDUCE_TERM",
+		1: "REDUCE_PAYMENT",
+	}
+	PrepaymentMode_value = map[string]int32{
+		"REDUCE_TERM":    0,
+		"REDUCE_PAYMENT": 1,
+	}
+)
+
+func (x PrepaymentMode) Enum() *PrepaymentMode {
+	p := new(PrepaymentMode)
+	*p = x
+	return p
+}
+
+func (x PrepaymentMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PrepaymentMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_protos_entities_prepayment_proto_enumTypes[0].Descriptor()
+}
+
+func (PrepaymentMode) Type() protoreflect.EnumType {
+	return &file_api_protos_entities_prepayment_proto_enumTypes[0]
+}
+
+func (x PrepaymentMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PrepaymentMode.Descriptor instead.
+func (PrepaymentMode) EnumDescriptor() ([]byte, []int) {
+	return file_api_protos_entities_prepayment_proto_rawDescGZIP(), []int{0}
+}
+
+// Досрочный платеж. Вносится вместе с ближайшим плановым платежом не раньше даты date.
+type Prepayment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`     // дата (первого) досрочного платежа
+	Amount        *Money                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"` // сумма сверх планового платежа
+	Mode          PrepaymentMode         `protobuf:"varint,3,opt,name=mode,proto3,enum=entities.PrepaymentMode" json:"mode,omitempty"`
+	EveryMonths   int32                  `protobuf:"varint,4,opt,name=every_months,json=everyMonths,proto3" json:"every_months,omitempty"` // 0 — разовый, N — повторять каждые N месяцев
+	Count         int32                  `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"`                                // число повторений регулярного платежа, 0 — до погашения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Prepayment) Reset() {
+	*x = Prepayment{}
+	mi := &file_api_protos_entities_prepayment_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Prepayment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Prepayment) ProtoMessage() {}
+
+func (x *Prepayment) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_prepayment_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Prepayment.ProtoReflect.Descriptor instead.
+func (*Prepayment) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_prepayment_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Prepayment) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *Prepayment) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Prepayment) GetMode() PrepaymentMode {
+	if x != nil {
+		return x.Mode
+	}
+	return PrepaymentMode_REDUCE_TERM
+}
+
+func (x *Prepayment) GetEveryMonths() int32 {
+	if x != nil {
+		return x.EveryMonths
+	}
+	return 0
+}
+
+func (x *Prepayment) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Досрочное погашение по сохраненному расчету или по параметрам кредита (POST /prepayment)
+type PrepaymentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Loan:
+	//
+	//	*PrepaymentRequest_CalculationId
+	//	*PrepaymentRequest_Request
+	Loan          isPrepaymentRequest_Loan `protobuf_oneof:"loan"`
+	Prepayments   []*Prepayment            `protobuf:"bytes,3,rep,name=prepayments,proto3" json:"prepayments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PrepaymentRequest) Reset() {
+	*x = PrepaymentRequest{}
+	mi := &file_api_protos_entities_prepayment_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PrepaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrepaymentRequest) ProtoMessage() {}
+
+func (x *PrepaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_prepayment_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrepaymentRequest.ProtoReflect.Descriptor instead.
+func (*PrepaymentRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_prepayment_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PrepaymentRequest) GetLoan() isPrepaymentRequest_Loan {
+	if x != nil {
+		return x.Loan
+	}
+	return nil
+}
+
+func (x *PrepaymentRequest) GetCalculationId() int64 {
+	if x != nil {
+		if x, ok := x.Loan.(*PrepaymentRequest_CalculationId); ok {
+			return x.CalculationId
+		}
+	}
+	return 0
+}
+
+func (x *PrepaymentRequest) GetRequest() *LoanRequest {
+	if x != nil {
+		if x, ok := x.Loan.(*PrepaymentRequest_Request); ok {
+			return x.Request
+		}
+	}
+	return nil
+}
+
+func (x *PrepaymentRequest) GetPrepayments() []*Prepayment {
+	if x != nil {
+		return x.Prepayments
+	}
+	return nil
+}
+
+type isPrepaymentRequest_Loan interface {
+	isPrepaymentRequest_Loan()
+}
+
+type PrepaymentRequest_CalculationId struct {
+	CalculationId int64 `protobuf:"varint,1,opt,name=calculation_id,json=calculationId,proto3,oneof"` // id расчета в кеше
+}
+
+type PrepaymentRequest_Request struct {
+	Request *LoanRequest `protobuf:"bytes,2,opt,name=request,proto3,oneof"` // параметры кредита, как для /execute
+}
+
+func (*PrepaymentRequest_CalculationId) isPrepaymentRequest_Loan() {}
+
+func (*PrepaymentRequest_Request) isPrepaymentRequest_Loan() {}
+
+// График с досрочными платежами в сравнении с исходным расчетом
+type PrepaymentResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Params        *LoanParams            `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"`
+	Program       *LoanProgram           `protobuf:"bytes,2,opt,name=program,proto3" json:"program,omitempty"`
+	PaymentType   PaymentType            `protobuf:"varint,3,opt,name=payment_type,json=paymentType,proto3,enum=entities.PaymentType" json:"payment_type,omitempty"`
+	Original      *LoanAggregates        `protobuf:"bytes,4,opt,name=original,proto3" json:"original,omitempty"`                                // исходный расчет без досрочных платежей
+	Aggregates    *LoanAggregates        `protobuf:"bytes,5,opt,name=aggregates,proto3" json:"aggregates,omitempty"`                            // после досрочных платежей, last_payment_date — новая дата
+	Rows          []*ScheduleRow         `protobuf:"bytes,6,rep,name=rows,proto3" json:"rows,omitempty"`                                        // досрочный платеж в поле prepayment
+	InterestSaved *Money                 `protobuf:"bytes,7,opt,name=interest_saved,json=interestSaved,proto3" json:"interest_saved,omitempty"` // экономия на процентах
+	MonthsSaved   int64                  `protobuf:"varint,8,opt,name=months_saved,json=monthsSaved,proto3" json:"months_saved,omitempty"`      // на сколько платежей сократился срок
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PrepaymentResult) Reset() {
+	*x = PrepaymentResult{}
+	mi := &file_api_protos_entities_prepayment_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PrepaymentResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrepaymentResult) ProtoMessage() {}
+
+func (x *PrepaymentResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_prepayment_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrepaymentResult.ProtoReflect.Descriptor instead.
+func (*PrepaymentResult) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_prepayment_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PrepaymentResult) GetParams() *LoanParams {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *PrepaymentResult) GetProgram() *LoanProgram {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *PrepaymentResult) GetPaymentType() PaymentType {
+	if x != nil {
+		return x.PaymentType
+	}
+	return PaymentType_ANNUITY
+}
+
+func (x *PrepaymentResult) GetOriginal() *LoanAggregates {
+	if x != nil {
+		return x.Original
+	}
+	return nil
+}
+
+func (x *PrepaymentResult) GetAggregates() *LoanAggregates {
+	if x != nil {
+		return x.Aggregates
+	}
+	return nil
+}
+
+func (x *PrepaymentResult) GetRows() []*ScheduleRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *PrepaymentResult) GetInterestSaved() *Money {
+	if x != nil {
+		return x.InterestSaved
+	}
+	return nil
+}
+
+func (x *PrepaymentResult) GetMonthsSaved() int64 {
+	if x != nil {
+		return x.MonthsSaved
+	}
+	return 0
+}
+
+var File_api_protos_entities_prepayment_proto protoreflect.FileDescriptor
+
+const file_api_protos_entities_prepayment_proto_rawDesc = "" +
+	"\n" +
+	"$api/protos/entities/prepayment.proto\x12\bentities\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1eapi/protos/entities/loan.proto\"\xcc\x01\n" +
+	"\n" +
+	"Prepayment\x12.\n" +
+	"\x04date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12'\n" +
+	"\x06amount\x18\x02 \x01(\v2\x0f.entities.MoneyR\x06amount\x12,\n" +
+	"\x04mode\x18\x03 \x01(\x0e2\x18.entities.PrepaymentModeR\x04mode\x12!\n" +
+	"\fevery_months\x18\x04 \x01(\x05R\veveryMonths\x12\x14\n" +
+	"\x05count\x18\x05 \x01(\x05R\x05count\"\xaf\x01\n" +
+	"\x11PrepaymentRequest\x12'\n" +
+	"\x0ecalculation_id\x18\x01 \x01(\x03H\x00R\rcalculationId\x121\n" +
+	"\arequest\x18\x02 \x01(\v2\x15.entities.LoanRequestH\x00R\arequest\x126\n" +
+	"\vprepayments\x18\x03 \x03(\v2\x14.entities.PrepaymentR\vprepaymentsB\x06\n" +
+	"\x04loan\"\xa1\x03\n" +
+	"\x10PrepaymentResult\x12,\n" +
+	"\x06params\x18\x01 \x01(\v2\x14.entities.LoanParamsR\x06params\x12/\n" +
+	"\aprogram\x18\x02 \x01(\v2\x15.entities.LoanProgramR\aprogram\x128\n" +
+	"\fpayment_type\x18\x03 \x01(\x0e2\x15.entities.PaymentTypeR\vpaymentType\x124\n" +
+	"\boriginal\x18\x04 \x01(\v2\x18.entities.LoanAggregatesR\boriginal\x128\n" +
+	"\n" +
+	"aggregates\x18\x05 \x01(\v2\x18.entities.LoanAggregatesR\n" +
+	"aggregates\x12)\n" +
+	"\x04rows\x18\x06 \x03(\v2\x15.entities.ScheduleRowR\x04rows\x126\n" +
+	"\x0einterest_saved\x18\a \x01(\v2\x0f.entities.MoneyR\rinterestSaved\x12!\n" +
+	"\fmonths_saved\x18\b \x01(\x03R\vmonthsSaved*5\n" +
+	"\x0ePrepaymentMode\x12\x0f\n" +
+	"\vREDUCE_TERM\x10\x00\x12\x12\n" +
+	"\x0eREDUCE_PAYMENT\x10\x01B4Z2github.com/Dorji/sberInterview/api/protos/entitiesb\x06proto3"
+
+var (
+	file_api_protos_entities_prepayment_proto_rawDescOnce sync.Once
+	file_api_protos_entities_prepayment_proto_rawDescData []byte
+)
+
+func file_api_protos_entities_prepayment_proto_rawDescGZIP() []byte {
+	file_api_protos_entities_prepayment_proto_rawDescOnce.Do(func() {
+		file_api_protos_entities_prepayment_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_protos_entities_prepayment_proto_rawDesc), len(file_api_protos_entities_prepayment_proto_rawDesc)))
+	})
+	return file_api_protos_entities_prepayment_proto_rawDescData
+}
+
+var file_api_protos_entities_prepayment_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_protos_entities_prepayment_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_api_protos_entities_prepayment_proto_goTypes = []any{
+	(PrepaymentMode)(0),           // 0: entities.PrepaymentMode
+	(*Prepayment)(nil),            // 1: entities.Prepayment
+	(*PrepaymentRequest)(nil),     // 2: entities.PrepaymentRequest
+	(*PrepaymentResult)(nil),      // 3: entities.PrepaymentResult
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+	(*Money)(nil),                 // 5: entities.Money
+	(*LoanRequest)(nil),           // 6: entities.LoanRequest
+	(*LoanParams)(nil),            // 7: entities.LoanParams
+	(*LoanProgram)(nil),           // 8: entities.LoanProgram
+	(PaymentType)(0),              // 9: entities.PaymentType
+	(*LoanAggregates)(nil),        // 10: entities.LoanAggregates
+	(*ScheduleRow)(nil),           // 11: entities.ScheduleRow
+}
+var file_api_protos_entities_prepayment_proto_depIdxs = []int32{
+	4,  // 0: entities.Prepayment.date:type_name -> google.protobuf.Timestamp
+	5,  // 1: entities.Prepayment.amount:type_name -> entities.Money
+	0,  // 2: entities.Prepayment.mode:type_name -> entities.PrepaymentMode
+	6,  // 3: entities.PrepaymentRequest.request:type_name -> entities.LoanRequest
+	1,  // 4: entities.PrepaymentRequest.prepayments:type_name -> entities.Prepayment
+	7,  // 5: entities.PrepaymentResult.params:type_name -> entities.LoanParams
+	8,  // 6: entities.PrepaymentResult.program:type_name -> entities.LoanProgram
+	9,  // 7: entities.PrepaymentResult.payment_type:type_name -> entities.PaymentType
+	10, // 8: entities.PrepaymentResult.original:type_name -> entities.LoanAggregates
+	10, // 9: entities.PrepaymentResult.aggregates:type_name -> entities.LoanAggregates
+	11, // 10: entities.PrepaymentResult.rows:type_name -> entities.ScheduleRow
+	5,  // 11: entities.PrepaymentResult.interest_saved:type_name -> entities.Money
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_api_protos_entities_prepayment_proto_init() }
+func file_api_protos_entities_prepayment_proto_init() {
+	if File_api_protos_entities_prepayment_proto != nil {
+		return
+	}
+	file_api_protos_entities_loan_proto_init()
+	file_api_protos_entities_prepayment_proto_msgTypes[1].OneofWrappers = []any{
+		(*PrepaymentRequest_CalculationId)(nil),
+		(*PrepaymentRequest_Request)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_protos_entities_prepayment_proto_rawDesc), len(file_api_protos_entities_prepayment_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_protos_entities_prepayment_proto_goTypes,
+		DependencyIndexes: file_api_protos_entities_prepayment_proto_depIdxs,
+		EnumInfos:         file_api_protos_entities_prepayment_proto_enumTypes,
+		MessageInfos:      file_api_protos_entities_prepayment_proto_msgTypes,
+	}.Build()
+	File_api_protos_entities_prepayment_proto = out.File
+	file_api_protos_entities_prepayment_proto_goTypes = nil
+	file_api_protos_entities_prepayment_proto_depIdxs = nil
+}
@@ -0,0 +1,486 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: api/protos/entities/rates.proto
+
+package entities
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Ставка программы в версии таблицы ставок
+type ProgramRate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`                                  // код программы
+	RateBps       int64                  `protobuf:"varint,2,opt,name=rate_bps,json=rateBps,proto3" json:"rate_bps,omitempty"`            // годовая ставка (для плавающей не задается)
+	RatePeriods   []*RatePeriod          `protobuf:"bytes,3,rep,name=rate_periods,json=ratePeriods,proto3" json:"rate_periods,omitempty"` // ступенчатая ставка в начале срока
+	RateType      string                 `protobuf:"bytes,4,opt,name=rate_type,json=rateType,proto3" json:"rate_type,omitempty"`          // fixed или key_rate
+	SpreadBps     int64                  `protobuf:"varint,5,opt,name=spread_bps,json=spreadBps,proto3" json:"spread_bps,omitempty"`      // надбавка к ключевой ставке
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProgramRate) Reset() {
+	*x = ProgramRate{}
+	mi := &file_api_protos_entities_rates_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProgramRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgramRate) ProtoMessage() {}
+
+func (x *ProgramRate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_rates_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgramRate.ProtoReflect.Descriptor instead.
+func (*ProgramRate) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_rates_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProgramRate) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ProgramRate) GetRateBps() int64 {
+	if x != nil {
+		return x.RateBps
+	}
+	return 0
+}
+
+func (x *ProgramRate) GetRatePeriods() []*RatePeriod {
+	if x != nil {
+		return x.RatePeriods
+	}
+	return nil
+}
+
+func (x *ProgramRate) GetRateType() string {
+	if x != nil {
+		return x.RateType
+	}
+	return ""
+}
+
+func (x *ProgramRate) GetSpreadBps() int64 {
+	if x != nil {
+		return x.SpreadBps
+	}
+	return 0
+}
+
+// Версия таблицы ставок: действует с effective_from включительно до effective_to не включительно
+type RateVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EffectiveFrom *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=effective_from,json=effectiveFrom,proto3" json:"effective_from,omitempty"`
+	EffectiveTo   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=effective_to,json=effectiveTo,proto3" json:"effective_to,omitempty"` // пусто — действует до сих пор
+	Rates         []*ProgramRate         `protobuf:"bytes,4,rep,name=rates,proto3" json:"rates,omitempty"`                                // действующие ставки всех программ реестра
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateVersion) Reset() {
+	*x = RateVersion{}
+	mi := &file_api_protos_entities_rates_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateVersion) ProtoMessage() {}
+
+func (x *RateVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_rates_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateVersion.ProtoReflect.Descriptor instead.
+func (*RateVersion) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_rates_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RateVersion) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RateVersion) GetEffectiveFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EffectiveFrom
+	}
+	return nil
+}
+
+func (x *RateVersion) GetEffectiveTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EffectiveTo
+	}
+	return nil
+}
+
+func (x *RateVersion) GetRates() []*ProgramRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+// Версии таблицы ставок по возрастанию начала действия
+type RateVersionList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      []*RateVersion         `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateVersionList) Reset() {
+	*x = RateVersionList{}
+	mi := &file_api_protos_entities_rates_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateVersionList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateVersionList) ProtoMessage() {}
+
+func (x *RateVersionList) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_rates_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateVersionList.ProtoReflect.Descriptor instead.
+func (*RateVersionList) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_rates_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RateVersionList) GetVersions() []*RateVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+// Две версии для сравнения (GET /rates/versions/diff?from=...&to=...)
+type RateVersionDiffRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateVersionDiffRequest) Reset() {
+	*x = RateVersionDiffRequest{}
+	mi := &file_api_protos_entities_rates_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateVersionDiffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateVersionDiffRequest) ProtoMessage() {}
+
+func (x *RateVersionDiffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_rates_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateVersionDiffRequest.ProtoReflect.Descriptor instead.
+func (*RateVersionDiffRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_rates_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RateVersionDiffRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *RateVersionDiffRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+// Изменение ставки программы
+type ProgramRateChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	From          *ProgramRate           `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *ProgramRate           `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProgramRateChange) Reset() {
+	*x = ProgramRateChange{}
+	mi := &file_api_protos_entities_rates_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProgramRateChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgramRateChange) ProtoMessage() {}
+
+func (x *ProgramRateChange) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_rates_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgramRateChange.ProtoReflect.Descriptor instead.
+func (*ProgramRateChange) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_rates_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ProgramRateChange) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ProgramRateChange) GetFrom() *ProgramRate {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ProgramRateChange) GetTo() *ProgramRate {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+// Разница двух версий: только программы с изменившейся ставкой
+type RateVersionDiff struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *RateVersion           `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            *RateVersion           `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Changes       []*ProgramRateChange   `protobuf:"bytes,3,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateVersionDiff) Reset() {
+	*x = RateVersionDiff{}
+	mi := &file_api_protos_entities_rates_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateVersionDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateVersionDiff) ProtoMessage() {}
+
+func (x *RateVersionDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_rates_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateVersionDiff.ProtoReflect.Descriptor instead.
+func (*RateVersionDiff) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_rates_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RateVersionDiff) GetFrom() *RateVersion {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *RateVersionDiff) GetTo() *RateVersion {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *RateVersionDiff) GetChanges() []*ProgramRateChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+var File_api_protos_entities_rates_proto protoreflect.FileDescriptor
+
+const file_api_protos_entities_rates_proto_rawDesc = "" +
+	"\n" +
+	"\x1fapi/protos/entities/rates.proto\x12\bentities\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1eapi/protos/entities/loan.proto\"\xb1\x01\n" +
+	"\vProgramRate\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x19\n" +
+	"\brate_bps\x18\x02 \x01(\x03R\arateBps\x127\n" +
+	"\frate_periods\x18\x03 \x03(\v2\x14.entities.RatePeriodR\vratePeriods\x12\x1b\n" +
+	"\trate_type\x18\x04 \x01(\tR\brateType\x12\x1d\n" +
+	"\n" +
+	"spread_bps\x18\x05 \x01(\x03R\tspreadBps\"\xcc\x01\n" +
+	"\vRateVersion\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12A\n" +
+	"\x0eeffective_from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\reffectiveFrom\x12=\n" +
+	"\feffective_to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\veffectiveTo\x12+\n" +
+	"\x05rates\x18\x04 \x03(\v2\x15.entities.ProgramRateR\x05rates\"D\n" +
+	"\x0fRateVersionList\x121\n" +
+	"\bversions\x18\x01 \x03(\v2\x15.entities.RateVersionR\bversions\"<\n" +
+	"\x16RateVersionDiffRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\"y\n" +
+	"\x11ProgramRateChange\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12)\n" +
+	"\x04from\x18\x02 \x01(\v2\x15.entities.ProgramRateR\x04from\x12%\n" +
+	"\x02to\x18\x03 \x01(\v2\x15.entities.ProgramRateR\x02to\"\x9a\x01\n" +
+	"\x0fRateVersionDiff\x12)\n" +
+	"\x04from\x18\x01 \x01(\v2\x15.entities.RateVersionR\x04from\x12%\n" +
+	"\x02to\x18\x02 \x01(\v2\x15.entities.RateVersionR\x02to\x125\n" +
+	"\achanges\x18\x03 \x03(\v2\x1b.entities.ProgramRateChangeR\achangesB4Z2github.com/Dorji/sberInterview/api/protos/entitiesb\x06proto3"
+
+var (
+	file_api_protos_entities_rates_proto_rawDescOnce sync.Once
+	file_api_protos_entities_rates_proto_rawDescData []byte
+)
+
+func file_api_protos_entities_rates_proto_rawDescGZIP() []byte {
+	file_api_protos_entities_rates_proto_rawDescOnce.Do(func() {
+		file_api_protos_entities_rates_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_protos_entities_rates_proto_rawDesc), len(file_api_protos_entities_rates_proto_rawDesc)))
+	})
+	return file_api_protos_entities_rates_proto_rawDescData
+}
+
+var file_api_protos_entities_rates_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_api_protos_entities_rates_proto_goTypes = []any{
+	(*ProgramRate)(nil),            // 0: entities.ProgramRate
+	(*RateVersion)(nil),            // 1: entities.RateVersion
+	(*RateVersionList)(nil),        // 2: entities.RateVersionList
+	(*RateVersionDiffRequest)(nil), // 3: entities.RateVersionDiffRequest
+	(*ProgramRateChange)(nil),      // 4: entities.ProgramRateChange
+	(*RateVersionDiff)(nil),        // 5: entities.RateVersionDiff
+	(*RatePeriod)(nil),             // 6: entities.RatePeriod
+	(*timestamppb.Timestamp)(nil),  // 7: google.protobuf.Timestamp
+}
+var file_api_protos_entities_rates_proto_depIdxs = []int32{
+	6,  // 0: entities.ProgramRate.rate_periods:type_name -> entities.RatePeriod
+	7,  // 1: entities.RateVersion.effective_from:type_name -> google.protobuf.Timestamp
+	7,  // 2: entities.RateVersion.effective_to:type_name -> google.protobuf.Timestamp
+	0,  // 3: entities.RateVersion.rates:type_name -> entities.ProgramRate
+	1,  // 4: entities.RateVersionList.versions:type_name -> entities.RateVersion
+	0,  // 5: entities.ProgramRateChange.from:type_name -> entities.ProgramRate
+	0,  // 6: entities.ProgramRateChange.to:type_name -> entities.ProgramRate
+	1,  // 7: entities.RateVersionDiff.from:type_name -> entities.RateVersion
+	1,  // 8: entities.RateVersionDiff.to:type_name -> entities.RateVersion
+	4,  // 9: entities.RateVersionDiff.changes:type_name -> entities.ProgramRateChange
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_api_protos_entities_rates_proto_init() }
+func file_api_protos_entities_rates_proto_init() {
+	if File_api_protos_entities_rates_proto != nil {
+		return
+	}
+	file_api_protos_entities_loan_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_protos_entities_rates_proto_rawDesc), len(file_api_protos_entities_rates_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_protos_entities_rates_proto_goTypes,
+		DependencyIndexes: file_api_protos_entities_rates_proto_depIdxs,
+		MessageInfos:      file_api_protos_entities_rates_proto_msgTypes,
+	}.Build()
+	File_api_protos_entities_rates_proto = out.File
+	file_api_protos_entities_rates_proto_goTypes = nil
+	file_api_protos_entities_rates_proto_depIdxs = nil
+}
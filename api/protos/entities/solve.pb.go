@@ -0,0 +1,363 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: api/protos/entities/solve.proto
+
+package entities
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Неизвестное, которое нужно найти по трем остальным.
+// Значения с префиксом, так как MONTHLY_PAYMENT уже есть в CacheSort
+type SolveFor int32
+
+const (
+	SolveFor_SOLVE_MONTHLY_PAYMENT SolveFor = 0 // платеж по стоимости, взносу и сроку, как /execute
+	SolveFor_SOLVE_MONTHS          SolveFor = 1 // наименьший срок, при котором платеж укладывается в бюджет
+	SolveFor_SOLVE_INITIAL_PAYMENT SolveFor = 2 // наименьший взнос, при котором платеж укладывается в бюджет
+	SolveFor_SOLVE_OBJECT_COST     SolveFor = 3 // наибольшая стоимость объекта при заданном взносе и бюджете
+)
+
+// Enum value maps for SolveFor.
+var (
+	SolveFor_name = map[int32]string{
+		0: "SOLVE_MONTHLY_PAYMENT",
+		1: "SOLVE_MONTHS",
+		2: "SOLVE_INITIAL_PAYMENT",
+		3: "SOLVE_OBJECT_COST",
+	}
+	SolveFor_value = map[string]int32{
+		"SOLVE_MONTHLY_PAYMENT": 0,
+		"SOLVE_MONTHS":          1,
+		"SOLVE_INITIAL_PAYMENT": 2,
+		"SOLVE_OBJECT_COST":     3,
+	}
+)
+
+func (x SolveFor) Enum() *SolveFor {
+	p := new(SolveFor)
+	*p = x
+	return p
+}
+
+func (x SolveFor) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SolveFor) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_protos_entities_solve_proto_enumTypes[0].Descriptor()
+}
+
+func (SolveFor) Type() protoreflect.EnumType {
+	return &file_api_protos_entities_solve_proto_enumTypes[0]
+}
+
+func (x SolveFor) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SolveFor.Descriptor instead.
+func (SolveFor) EnumDescriptor() ([]byte, []int) {
+	return file_api_protos_entities_solve_proto_rawDescGZIP(), []int{0}
+}
+
+// Подбор одного из параметров кредита по трем остальным (POST /solve).
+// Поле, которое ищется, игнорируется.
+type SolveRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SolveFor       SolveFor               `protobuf:"varint,1,opt,name=solve_for,json=solveFor,proto3,enum=entities.SolveFor" json:"solve_for,omitempty"`
+	ObjectCost     int64                  `protobuf:"varint,2,opt,name=object_cost,json=objectCost,proto3" json:"object_cost,omitempty"`             // стоимость объекта
+	InitialPayment int64                  `protobuf:"varint,3,opt,name=initial_payment,json=initialPayment,proto3" json:"initial_payment,omitempty"` // первоначальный взнос
+	Months         int64                  `protobuf:"varint,4,opt,name=months,proto3" json:"months,omitempty"`                                       // срок
+	MonthlyPayment int64                  `protobuf:"varint,5,opt,name=monthly_payment,json=monthlyPayment,proto3" json:"monthly_payment,omitempty"` // бюджет на (первый) платеж в месяц (рубли)
+	Program        *LoanProgram           `protobuf:"bytes,6,opt,name=program,proto3" json:"program,omitempty"`
+	PaymentType    PaymentType            `protobuf:"varint,7,opt,name=payment_type,json=paymentType,proto3,enum=entities.PaymentType" json:"payment_type,omitempty"`
+	IssueDate      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=issue_date,json=issueDate,proto3" json:"issue_date,omitempty"`
+	PaymentDay     int32                  `protobuf:"varint,9,opt,name=payment_day,json=paymentDay,proto3" json:"payment_day,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SolveRequest) Reset() {
+	*x = SolveRequest{}
+	mi := &file_api_protos_entities_solve_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SolveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SolveRequest) ProtoMessage() {}
+
+func (x *SolveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_solve_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SolveRequest.ProtoReflect.Descriptor instead.
+func (*SolveRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_solve_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SolveRequest) GetSolveFor() SolveFor {
+	if x != nil {
+		return x.SolveFor
+	}
+	return SolveFor_SOLVE_MONTHLY_PAYMENT
+}
+
+func (x *SolveRequest) GetObjectCost() int64 {
+	if x != nil {
+		return x.ObjectCost
+	}
+	return 0
+}
+
+func (x *SolveRequest) GetInitialPayment() int64 {
+	if x != nil {
+		return x.InitialPayment
+	}
+	return 0
+}
+
+func (x *SolveRequest) GetMonths() int64 {
+	if x != nil {
+		return x.Months
+	}
+	return 0
+}
+
+func (x *SolveRequest) GetMonthlyPayment() int64 {
+	if x != nil {
+		return x.MonthlyPayment
+	}
+	return 0
+}
+
+func (x *SolveRequest) GetProgram() *LoanProgram {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *SolveRequest) GetPaymentType() PaymentType {
+	if x != nil {
+		return x.PaymentType
+	}
+	return PaymentType_ANNUITY
+}
+
+func (x *SolveRequest) GetIssueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssueDate
+	}
+	return nil
+}
+
+func (x *SolveRequest) GetPaymentDay() int32 {
+	if x != nil {
+		return x.PaymentDay
+	}
+	return 0
+}
+
+// Найденный кредит: параметры в params, платежи в aggregates, как у /execute
+type SolveResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SolveFor      SolveFor               `protobuf:"varint,1,opt,name=solve_for,json=solveFor,proto3,enum=entities.SolveFor" json:"solve_for,omitempty"`
+	Params        *LoanParams            `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+	Program       *LoanProgram           `protobuf:"bytes,3,opt,name=program,proto3" json:"program,omitempty"`
+	Aggregates    *LoanAggregates        `protobuf:"bytes,4,opt,name=aggregates,proto3" json:"aggregates,omitempty"`
+	PaymentType   PaymentType            `protobuf:"varint,5,opt,name=payment_type,json=paymentType,proto3,enum=entities.PaymentType" json:"payment_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SolveResult) Reset() {
+	*x = SolveResult{}
+	mi := &file_api_protos_entities_solve_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SolveResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SolveResult) ProtoMessage() {}
+
+func (x *SolveResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_entities_solve_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SolveResult.ProtoReflect.Descriptor instead.
+func (*SolveResult) Descriptor() ([]byte, []int) {
+	return file_api_protos_entities_solve_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SolveResult) GetSolveFor() SolveFor {
+	if x != nil {
+		return x.SolveFor
+	}
+	return SolveFor_SOLVE_MONTHLY_PAYMENT
+}
+
+func (x *SolveResult) GetParams() *LoanParams {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *SolveResult) GetProgram() *LoanProgram {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *SolveResult) GetAggregates() *LoanAggregates {
+	if x != nil {
+		return x.Aggregates
+	}
+	return nil
+}
+
+func (x *SolveResult) GetPaymentType() PaymentType {
+	if x != nil {
+		return x.PaymentType
+	}
+	return PaymentType_ANNUITY
+}
+
+var File_api_protos_entities_solve_proto protoreflect.FileDescriptor
+
+const file_api_protos_entities_solve_proto_rawDesc = "" +
+	"\n" +
+	"\x1fapi/protos/entities/solve.proto\x12\bentities\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1eapi/protos/entities/loan.proto\"\x91\x03\n" +
+	"\fSolveRequest\x12/\n" +
+	"\tsolve_for\x18\x01 \x01(\x0e2\x12.entities.SolveForR\bsolveFor\x12\x1f\n" +
+	"\vobject_cost\x18\x02 \x01(\x03R\n" +
+	"objectCost\x12'\n" +
+	"\x0finitial_payment\x18\x03 \x01(\x03R\x0einitialPayment\x12\x16\n" +
+	"\x06months\x18\x04 \x01(\x03R\x06months\x12'\n" +
+	"\x0fmonthly_payment\x18\x05 \x01(\x03R\x0emonthlyPayment\x12/\n" +
+	"\aprogram\x18\x06 \x01(\v2\x15.entities.LoanProgramR\aprogram\x128\n" +
+	"\fpayment_type\x18\a \x01(\x0e2\x15.entities.PaymentTypeR\vpaymentType\x129\n" +
+	"\n" +
+	"issue_date\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tissueDate\x12\x1f\n" +
+	"\vpayment_day\x18\t \x01(\x05R\n" +
+	"paymentDay\"\x91\x02\n" +
+	"\vSolveResult\x12/\n" +
+	"\tsolve_for\x18\x01 \x01(\x0e2\x12.entities.SolveForR\bsolveFor\x12,\n" +
+	"\x06params\x18\x02 \x01(\v2\x14.entities.LoanParamsR\x06params\x12/\n" +
+	"\aprogram\x18\x03 \x01(\v2\x15.entities.LoanProgramR\aprogram\x128\n" +
+	"\n" +
+	"aggregates\x18\x04 \x01(\v2\x18.entities.LoanAggregatesR\n" +
+	"aggregates\x128\n" +
+	"\fpayment_type\x18\x05 \x01(\x0e2\x15.entities.PaymentTypeR\vpaymentType*i\n" +
+	"\bSolveFor\x12\x19\n" +
+	"\x15SOLVE_MONTHLY_PAYMENT\x10\x00\x12\x10\n" +
+	"\fSOLVE_MONTHS\x10\x01\x12\x19\n" +
+	"\x15SOLVE_INITIAL_PAYMENT\x10\x02\x12\x15\n" +
+	"\x11SOLVE_OBJECT_COST\x10\x03B4Z2github.com/Dorji/sberInterview/api/protos/entitiesb\x06proto3"
+
+var (
+	file_api_protos_entities_solve_proto_rawDescOnce sync.Once
+	file_api_protos_entities_solve_proto_rawDescData []byte
+)
+
+func file_api_protos_entities_solve_proto_rawDescGZIP() []byte {
+	file_api_protos_entities_solve_proto_rawDescOnce.Do(func() {
+		file_api_protos_entities_solve_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_protos_entities_solve_proto_rawDesc), len(file_api_protos_entities_solve_proto_rawDesc)))
+	})
+	return file_api_protos_entities_solve_proto_rawDescData
+}
+
+var file_api_protos_entities_solve_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_protos_entities_solve_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_protos_entities_solve_proto_goTypes = []any{
+	(SolveFor)(0),                 // 0: entities.SolveFor
+	(*SolveRequest)(nil),          // 1: entities.SolveRequest
+	(*SolveResult)(nil),           // 2: entities.SolveResult
+	(*LoanProgram)(nil),           // 3: entities.LoanProgram
+	(PaymentType)(0),              // 4: entities.PaymentType
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+	(*LoanParams)(nil),            // 6: entities.LoanParams
+	(*LoanAggregates)(nil),        // 7: entities.LoanAggregates
+}
+var file_api_protos_entities_solve_proto_depIdxs = []int32{
+	0, // 0: entities.SolveRequest.solve_for:type_name -> entities.SolveFor
+	3, // 1: entities.SolveRequest.program:type_name -> entities.LoanProgram
+	4, // 2: entities.SolveRequest.payment_type:type_name -> entities.PaymentType
+	5, // 3: entities.SolveRequest.issue_date:type_name -> google.protobuf.Timestamp
+	0, // 4: entities.SolveResult.solve_for:type_name -> entities.SolveFor
+	6, // 5: entities.SolveResult.params:type_name -> entities.LoanParams
+	3, // 6: entities.SolveResult.program:type_name -> entities.LoanProgram
+	7, // 7: entities.SolveResult.aggregates:type_name -> entities.LoanAggregates
+	4, // 8: entities.SolveResult.payment_type:type_name -> entities.PaymentType
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	9, // [9:9] is the sub-list for extension type_name
+	9, // [9:9] is the sub-list for extension extendee
+	0, // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_api_protos_entities_solve_proto_init() }
+func file_api_protos_entities_solve_proto_init() {
+	if File_api_protos_entities_solve_proto != nil {
+		return
+	}
+	file_api_protos_entities_loan_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_protos_entities_solve_proto_rawDesc), len(file_api_protos_entities_solve_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_protos_entities_solve_proto_goTypes,
+		DependencyIndexes: file_api_protos_entities_solve_proto_depIdxs,
+		EnumInfos:         file_api_protos_entities_solve_proto_enumTypes,
+		MessageInfos:      file_api_protos_entities_solve_proto_msgTypes,
+	}.Build()
+	File_api_protos_entities_solve_proto = out.File
+	file_api_protos_entities_solve_proto_goTypes = nil
+	file_api_protos_entities_solve_proto_depIdxs = nil
+}
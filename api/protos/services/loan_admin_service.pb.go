@@ -0,0 +1,84 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: api/protos/services/loan_admin_service.proto
+
+package services
+
+import (
+	entities "github.com/Dorji/sberInterview/api/protos/entities"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_api_protos_services_loan_admin_service_proto protoreflect.FileDescriptor
+
+const file_api_protos_services_loan_admin_service_proto_rawDesc = "" +
+	"\n" +
+	",api/protos/services/loan_admin_service.proto\x12\bservices\x1a\x1cgoogle/api/annotations.proto\x1a\x1fapi/protos/entities/admin.proto\x1a\x1fapi/protos/entities/rates.proto2\xff\x03\n" +
+	"\x10LoanAdminService\x12]\n" +
+	"\fListPrograms\x12\x1d.entities.ListProgramsRequest\x1a\x15.entities.ProgramList\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/admin/programs\x12Q\n" +
+	"\rCreateProgram\x12\x11.entities.Program\x1a\x11.entities.Program\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/admin/programs\x12k\n" +
+	"\rUpdateProgram\x12\x1e.entities.UpdateProgramRequest\x1a\x11.entities.Program\"'\x82\xd3\xe4\x93\x02!:\aprogram\x1a\x16/admin/programs/{code}\x12b\n" +
+	"\x0eSetProgramRate\x12\x15.entities.ProgramRate\x1a\x11.entities.Program\"&\x82\xd3\xe4\x93\x02 :\x01*\x1a\x1b/admin/programs/{code}/rate\x12h\n" +
+	"\x11DeactivateProgram\x12\x15.entities.ProgramCode\x1a\x11.entities.Program\")\x82\xd3\xe4\x93\x02#\"!/admin/programs/{code}/deactivateB4Z2github.com/Dorji/sberInterview/api/protos/servicesb\x06proto3"
+
+var file_api_protos_services_loan_admin_service_proto_goTypes = []any{
+	(*entities.ListProgramsRequest)(nil),  // 0: entities.ListProgramsRequest
+	(*entities.Program)(nil),              // 1: entities.Program
+	(*entities.UpdateProgramRequest)(nil), // 2: entities.UpdateProgramRequest
+	(*entities.ProgramRate)(nil),          // 3: entities.ProgramRate
+	(*entities.ProgramCode)(nil),          // 4: entities.ProgramCode
+	(*entities.ProgramList)(nil),          // 5: entities.ProgramList
+}
+var file_api_protos_services_loan_admin_service_proto_depIdxs = []int32{
+	0, // 0: services.LoanAdminService.ListPrograms:input_type -> entities.ListProgramsRequest
+	1, // 1: services.LoanAdminService.CreateProgram:input_type -> entities.Program
+	2, // 2: services.LoanAdminService.UpdateProgram:input_type -> entities.UpdateProgramRequest
+	3, // 3: services.LoanAdminService.SetProgramRate:input_type -> entities.ProgramRate
+	4, // 4: services.LoanAdminService.DeactivateProgram:input_type -> entities.ProgramCode
+	5, // 5: services.LoanAdminService.ListPrograms:output_type -> entities.ProgramList
+	1, // 6: services.LoanAdminService.CreateProgram:output_type -> entities.Program
+	1, // 7: services.LoanAdminService.UpdateProgram:output_type -> entities.Program
+	1, // 8: services.LoanAdminService.SetProgramRate:output_type -> entities.Program
+	1, // 9: services.LoanAdminService.DeactivateProgram:output_type -> entities.Program
+	5, // [5:10] is the sub-list for method output_type
+	0, // [0:5] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_api_protos_services_loan_admin_service_proto_init() }
+func file_api_protos_services_loan_admin_service_proto_init() {
+	if File_api_protos_services_loan_admin_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_protos_services_loan_admin_service_proto_rawDesc), len(file_api_protos_services_loan_admin_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_protos_services_loan_admin_service_proto_goTypes,
+		DependencyIndexes: file_api_protos_services_loan_admin_service_proto_depIdxs,
+	}.Build()
+	File_api_protos_services_loan_admin_service_proto = out.File
+	file_api_protos_services_loan_admin_service_proto_goTypes = nil
+	file_api_protos_services_loan_admin_service_proto_depIdxs = nil
+}
@@ -0,0 +1,292 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: api/protos/services/loan_admin_service.proto
+
+package services
+
+import (
+	context "context"
+	entities "github.com/Dorji/sberInterview/api/protos/entities"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LoanAdminService_ListPrograms_FullMethodName      = "/services.LoanAdminService/ListPrograms"
+	LoanAdminService_CreateProgram_FullMethodName     = "/services.LoanAdminService/CreateProgram"
+	LoanAdminService_UpdateProgram_FullMethodName     = "/services.LoanAdminService/UpdateProgram"
+	LoanAdminService_SetProgramRate_FullMethodName    = "/services.LoanAdminService/SetProgramRate"
+	LoanAdminService_DeactivateProgram_FullMethodName = "/services.LoanAdminService/DeactivateProgram"
+)
+
+// LoanAdminServiceClient is the client API for LoanAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Управление программами и ставками. Сервис доступен только на админском порту,
+// каждый вызов требует токена, каждое изменение пишется в журнал
+type LoanAdminServiceClient interface {
+	// Программы реестра (GET /admin/programs)
+	ListPrograms(ctx context.Context, in *entities.ListProgramsRequest, opts ...grpc.CallOption) (*entities.ProgramList, error)
+	// Новая программа (POST /admin/programs)
+	CreateProgram(ctx context.Context, in *entities.Program, opts ...grpc.CallOption) (*entities.Program, error)
+	// Замена программы целиком (PUT /admin/programs/{code}), включая включение выключенной
+	UpdateProgram(ctx context.Context, in *entities.UpdateProgramRequest, opts ...grpc.CallOption) (*entities.Program, error)
+	// Новая ставка программы без изменения остальных полей (PUT /admin/programs/{code}/rate)
+	SetProgramRate(ctx context.Context, in *entities.ProgramRate, opts ...grpc.CallOption) (*entities.Program, error)
+	// Выключение программы (POST /admin/programs/{code}/deactivate): она остается в реестре,
+	// но не выбирается в новых расчетах
+	DeactivateProgram(ctx context.Context, in *entities.ProgramCode, opts ...grpc.CallOption) (*entities.Program, error)
+}
+
+type loanAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLoanAdminServiceClient(cc grpc.ClientConnInterface) LoanAdminServiceClient {
+	return &loanAdminServiceClient{cc}
+}
+
+func (c *loanAdminServiceClient) ListPrograms(ctx context.Context, in *entities.ListProgramsRequest, opts ...grpc.CallOption) (*entities.ProgramList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(entities.ProgramList)
+	err := c.cc.Invoke(ctx, LoanAdminService_ListPrograms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanAdminServiceClient) CreateProgram(ctx context.Context, in *entities.Program, opts ...grpc.CallOption) (*entities.Program, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(entities.Program)
+	err := c.cc.Invoke(ctx, LoanAdminService_CreateProgram_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanAdminServiceClient) UpdateProgram(ctx context.Context, in *entities.UpdateProgramRequest, opts ...grpc.CallOption) (*entities.Program, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(entities.Program)
+	err := c.cc.Invoke(ctx, LoanAdminService_UpdateProgram_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanAdminServiceClient) SetProgramRate(ctx context.Context, in *entities.ProgramRate, opts ...grpc.CallOption) (*entities.Program, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(entities.Program)
+	err := c.cc.Invoke(ctx, LoanAdminService_SetProgramRate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanAdminServiceClient) DeactivateProgram(ctx context.Context, in *entities.ProgramCode, opts ...grpc.CallOption) (*entities.Program, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(entities.Program)
+	err := c.cc.Invoke(ctx, LoanAdminService_DeactivateProgram_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LoanAdminServiceServer is the server API for LoanAdminService service.
+// All implementations must embed UnimplementedLoanAdminServiceServer
+// for forward compatibility.
+//
+// Управление программами и ставками. Сервис доступен только на админском порту,
+// каждый вызов требует токена, каждое изменение пишется в журнал
+type LoanAdminServiceServer interface {
+	// Программы реестра (GET /admin/programs)
+	ListPrograms(context.Context, *entities.ListProgramsRequest) (*entities.ProgramList, error)
+	// Новая программа (POST /admin/programs)
+	CreateProgram(context.Context, *entities.Program) (*entities.Program, error)
+	// Замена программы целиком (PUT /admin/programs/{code}), включая включение выключенной
+	UpdateProgram(context.Context, *entities.UpdateProgramRequest) (*entities.Program, error)
+	// Новая ставка программы без изменения остальных полей (PUT /admin/programs/{code}/rate)
+	SetProgramRate(context.Context, *entities.ProgramRate) (*entities.Program, error)
+	// Выключение программы (POST /admin/programs/{code}/deactivate): она остается в реестре,
+	// но не выбирается в новых расчетах
+	DeactivateProgram(context.Context, *entities.ProgramCode) (*entities.Program, error)
+	mustEmbedUnimplementedLoanAdminServiceServer()
+}
+
+// UnimplementedLoanAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLoanAdminServiceServer struct{}
+
+func (UnimplementedLoanAdminServiceServer) ListPrograms(context.Context, *entities.ListProgramsRequest) (*entities.ProgramList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPrograms not implemented")
+}
+func (UnimplementedLoanAdminServiceServer) CreateProgram(context.Context, *entities.Program) (*entities.Program, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProgram not implemented")
+}
+func (UnimplementedLoanAdminServiceServer) UpdateProgram(context.Context, *entities.UpdateProgramRequest) (*entities.Program, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProgram not implemented")
+}
+func (UnimplementedLoanAdminServiceServer) SetProgramRate(context.Context, *entities.ProgramRate) (*entities.Program, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetProgramRate not implemented")
+}
+func (UnimplementedLoanAdminServiceServer) DeactivateProgram(context.Context, *entities.ProgramCode) (*entities.Program, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeactivateProgram not implemented")
+}
+func (UnimplementedLoanAdminServiceServer) mustEmbedUnimplementedLoanAdminServiceServer() {}
+func (UnimplementedLoanAdminServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeLoanAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LoanAdminServiceServer will
+// result in compilation errors.
+type UnsafeLoanAdminServiceServer interface {
+	mustEmbedUnimplementedLoanAdminServiceServer()
+}
+
+func RegisterLoanAdminServiceServer(s grpc.ServiceRegistrar, srv LoanAdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedLoanAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LoanAdminService_ServiceDesc, srv)
+}
+
+func _LoanAdminService_ListPrograms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(entities.ListProgramsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanAdminServiceServer).ListPrograms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanAdminService_ListPrograms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanAdminServiceServer).ListPrograms(ctx, req.(*entities.ListProgramsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanAdminService_CreateProgram_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(entities.Program)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanAdminServiceServer).CreateProgram(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanAdminService_CreateProgram_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanAdminServiceServer).CreateProgram(ctx, req.(*entities.Program))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanAdminService_UpdateProgram_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(entities.UpdateProgramRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanAdminServiceServer).UpdateProgram(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanAdminService_UpdateProgram_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanAdminServiceServer).UpdateProgram(ctx, req.(*entities.UpdateProgramRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanAdminService_SetProgramRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(entities.ProgramRate)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanAdminServiceServer).SetProgramRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanAdminService_SetProgramRate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanAdminServiceServer).SetProgramRate(ctx, req.(*entities.ProgramRate))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanAdminService_DeactivateProgram_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(entities.ProgramCode)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanAdminServiceServer).DeactivateProgram(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanAdminService_DeactivateProgram_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanAdminServiceServer).DeactivateProgram(ctx, req.(*entities.ProgramCode))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LoanAdminService_ServiceDesc is the grpc.ServiceDesc for LoanAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LoanAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "services.LoanAdminService",
+	HandlerType: (*LoanAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListPrograms",
+			Handler:    _LoanAdminService_ListPrograms_Handler,
+		},
+		{
+			MethodName: "CreateProgram",
+			Handler:    _LoanAdminService_CreateProgram_Handler,
+		},
+		{
+			MethodName: "UpdateProgram",
+			Handler:    _LoanAdminService_UpdateProgram_Handler,
+		},
+		{
+			MethodName: "SetProgramRate",
+			Handler:    _LoanAdminService_SetProgramRate_Handler,
+		},
+		{
+			MethodName: "DeactivateProgram",
+			Handler:    _LoanAdminService_DeactivateProgram_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/protos/services/loan_admin_service.proto",
+}
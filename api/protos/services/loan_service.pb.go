@@ -27,27 +27,67 @@ var File_api_protos_services_loan_service_proto protoreflect.FileDescriptor
 
 const file_api_protos_services_loan_service_proto_rawDesc = "" +
 	"\n" +
-	"&api/protos/services/loan_service.proto\x12\bservices\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1eapi/protos/entities/loan.proto2\xa2\x01\n" +
+	"&api/protos/services/loan_service.proto\x12\bservices\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1eapi/protos/entities/loan.proto\x1a!api/protos/entities/compare.proto\x1a$api/protos/entities/prepayment.proto\x1a'api/protos/entities/affordability.proto\x1a\x1fapi/protos/entities/solve.proto\x1a\x1fapi/protos/entities/rates.proto2\xd2\a\n" +
 	"\vLoanService\x12K\n" +
-	"\aExecute\x12\x15.entities.LoanRequest\x1a\x14.entities.LoanResult\"\x13\x82\xd3\xe4\x93\x02\r:\x01*\"\b/execute\x12F\n" +
-	"\x05Cache\x12\x16.google.protobuf.Empty\x1a\x15.entities.CacheResult\"\x0e\x82\xd3\xe4\x93\x02\b\x12\x06/cacheB4Z2github.com/Dorji/sberInterview/api/protos/servicesb\x06proto3"
+	"\aExecute\x12\x15.entities.LoanRequest\x1a\x14.entities.LoanResult\"\x13\x82\xd3\xe4\x93\x02\r:\x01*\"\b/execute\x12Q\n" +
+	"\bSchedule\x12\x15.entities.LoanRequest\x1a\x18.entities.ScheduleResult\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/schedule\x12N\n" +
+	"\aCompare\x12\x15.entities.LoanRequest\x1a\x17.entities.CompareResult\"\x13\x82\xd3\xe4\x93\x02\r:\x01*\"\b/compare\x12Y\n" +
+	"\x06Prepay\x12\x1b.entities.PrepaymentRequest\x1a\x1a.entities.PrepaymentResult\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/prepayment\x12i\n" +
+	"\rAffordability\x12\x1e.entities.AffordabilityRequest\x1a\x1d.entities.AffordabilityResult\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/affordability\x12I\n" +
+	"\x05Solve\x12\x16.entities.SolveRequest\x1a\x15.entities.SolveResult\"\x11\x82\xd3\xe4\x93\x02\v:\x01*\"\x06/solve\x12Z\n" +
+	"\fRateVersions\x12\x16.google.protobuf.Empty\x1a\x19.entities.RateVersionList\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/rates/versions\x12m\n" +
+	"\x10DiffRateVersions\x12 .entities.RateVersionDiffRequest\x1a\x19.entities.RateVersionDiff\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/rates/versions/diff\x12F\n" +
+	"\x05Cache\x12\x16.entities.CacheRequest\x1a\x15.entities.CacheResult\"\x0e\x82\xd3\xe4\x93\x02\b\x12\x06/cache\x12T\n" +
+	"\x0eGetCalculation\x12\x17.entities.CalculationID\x1a\x14.entities.LoanResult\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/cache/{id}\x12Y\n" +
+	"\x11DeleteCalculation\x12\x17.entities.CalculationID\x1a\x16.google.protobuf.Empty\"\x13\x82\xd3\xe4\x93\x02\r*\v/cache/{id}B4Z2github.com/Dorji/sberInterview/api/protos/servicesb\x06proto3"
 
 var file_api_protos_services_loan_service_proto_goTypes = []any{
-	(*entities.LoanRequest)(nil), // 0: entities.LoanRequest
-	(*emptypb.Empty)(nil),        // 1: google.protobuf.Empty
-	(*entities.LoanResult)(nil),  // 2: entities.LoanResult
-	(*entities.CacheResult)(nil), // 3: entities.CacheResult
+	(*entities.LoanRequest)(nil),            // 0: entities.LoanRequest
+	(*entities.PrepaymentRequest)(nil),      // 1: entities.PrepaymentRequest
+	(*entities.AffordabilityRequest)(nil),   // 2: entities.AffordabilityRequest
+	(*entities.SolveRequest)(nil),           // 3: entities.SolveRequest
+	(*emptypb.Empty)(nil),                   // 4: google.protobuf.Empty
+	(*entities.RateVersionDiffRequest)(nil), // 5: entities.RateVersionDiffRequest
+	(*entities.CacheRequest)(nil),           // 6: entities.CacheRequest
+	(*entities.CalculationID)(nil),          // 7: entities.CalculationID
+	(*entities.LoanResult)(nil),             // 8: entities.LoanResult
+	(*entities.ScheduleResult)(nil),         // 9: entities.ScheduleResult
+	(*entities.CompareResult)(nil),          // 10: entities.CompareResult
+	(*entities.PrepaymentResult)(nil),       // 11: entities.PrepaymentResult
+	(*entities.AffordabilityResult)(nil),    // 12: entities.AffordabilityResult
+	(*entities.SolveResult)(nil),            // 13: entities.SolveResult
+	(*entities.RateVersionList)(nil),        // 14: entities.RateVersionList
+	(*entities.RateVersionDiff)(nil),        // 15: entities.RateVersionDiff
+	(*entities.CacheResult)(nil),            // 16: entities.CacheResult
 }
 var file_api_protos_services_loan_service_proto_depIdxs = []int32{
-	0, // 0: services.LoanService.Execute:input_type -> entities.LoanRequest
-	1, // 1: services.LoanService.Cache:input_type -> google.protobuf.Empty
-	2, // 2: services.LoanService.Execute:output_type -> entities.LoanResult
-	3, // 3: services.LoanService.Cache:output_type -> entities.CacheResult
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: services.LoanService.Execute:input_type -> entities.LoanRequest
+	0,  // 1: services.LoanService.Schedule:input_type -> entities.LoanRequest
+	0,  // 2: services.LoanService.Compare:input_type -> entities.LoanRequest
+	1,  // 3: services.LoanService.Prepay:input_type -> entities.PrepaymentRequest
+	2,  // 4: services.LoanService.Affordability:input_type -> entities.AffordabilityRequest
+	3,  // 5: services.LoanService.Solve:input_type -> entities.SolveRequest
+	4,  // 6: services.LoanService.RateVersions:input_type -> google.protobuf.Empty
+	5,  // 7: services.LoanService.DiffRateVersions:input_type -> entities.RateVersionDiffRequest
+	6,  // 8: services.LoanService.Cache:input_type -> entities.CacheRequest
+	7,  // 9: services.LoanService.GetCalculation:input_type -> entities.CalculationID
+	7,  // 10: services.LoanService.DeleteCalculation:input_type -> entities.CalculationID
+	8,  // 11: services.LoanService.Execute:output_type -> entities.LoanResult
+	9,  // 12: services.LoanService.Schedule:output_type -> entities.ScheduleResult
+	10, // 13: services.LoanService.Compare:output_type -> entities.CompareResult
+	11, // 14: services.LoanService.Prepay:output_type -> entities.PrepaymentResult
+	12, // 15: services.LoanService.Affordability:output_type -> entities.AffordabilityResult
+	13, // 16: services.LoanService.Solve:output_type -> entities.SolveResult
+	14, // 17: services.LoanService.RateVersions:output_type -> entities.RateVersionList
+	15, // 18: services.LoanService.DiffRateVersions:output_type -> entities.RateVersionDiff
+	16, // 19: services.LoanService.Cache:output_type -> entities.CacheResult
+	8,  // 20: services.LoanService.GetCalculation:output_type -> entities.LoanResult
+	4,  // 21: services.LoanService.DeleteCalculation:output_type -> google.protobuf.Empty
+	11, // [11:22] is the sub-list for method output_type
+	0,  // [0:11] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_api_protos_services_loan_service_proto_init() }
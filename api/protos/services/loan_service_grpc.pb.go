@@ -21,8 +21,17 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	LoanService_Execute_FullMethodName = "/services.LoanService/Execute"
-	LoanService_Cache_FullMethodName   = "/services.LoanService/Cache"
+	LoanService_Execute_FullMethodName           = "/services.LoanService/Execute"
+	LoanService_Schedule_FullMethodName          = "/services.LoanService/Schedule"
+	LoanService_Compare_FullMethodName           = "/services.LoanService/Compare"
+	LoanService_Prepay_FullMethodName            = "/services.LoanService/Prepay"
+	LoanService_Affordability_FullMethodName     = "/services.LoanService/Affordability"
+	LoanService_Solve_FullMethodName             = "/services.LoanService/Solve"
+	LoanService_RateVersions_FullMethodName      = "/services.LoanService/RateVersions"
+	LoanService_DiffRateVersions_FullMethodName  = "/services.LoanService/DiffRateVersions"
+	LoanService_Cache_FullMethodName             = "/services.LoanService/Cache"
+	LoanService_GetCalculation_FullMethodName    = "/services.LoanService/GetCalculation"
+	LoanService_DeleteCalculation_FullMethodName = "/services.LoanService/DeleteCalculation"
 )
 
 // LoanServiceClient is the client API for LoanService service.
@@ -31,8 +40,27 @@ const (
 type LoanServiceClient interface {
 	// Пример gRPC-метода с HTTP-ручкой (POST /execute)
 	Execute(ctx context.Context, in *entities.LoanRequest, opts ...grpc.CallOption) (*entities.LoanResult, error)
-	// GET /cache
-	Cache(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*entities.CacheResult, error)
+	// Помесячный график платежей (POST /schedule)
+	Schedule(ctx context.Context, in *entities.LoanRequest, opts ...grpc.CallOption) (*entities.ScheduleResult, error)
+	// Расчет по всем программам реестра на одних параметрах (POST /compare).
+	// Поле program запроса не используется, в кеш ничего не сохраняется
+	Compare(ctx context.Context, in *entities.LoanRequest, opts ...grpc.CallOption) (*entities.CompareResult, error)
+	// Пересчет графика с досрочными платежами (POST /prepayment), в кеш не сохраняется
+	Prepay(ctx context.Context, in *entities.PrepaymentRequest, opts ...grpc.CallOption) (*entities.PrepaymentResult, error)
+	// Максимальный кредит и стоимость объекта по ежемесячному бюджету (POST /affordability), в кеш не сохраняется
+	Affordability(ctx context.Context, in *entities.AffordabilityRequest, opts ...grpc.CallOption) (*entities.AffordabilityResult, error)
+	// Подбор срока, взноса, стоимости или платежа по трем остальным параметрам (POST /solve), в кеш не сохраняется
+	Solve(ctx context.Context, in *entities.SolveRequest, opts ...grpc.CallOption) (*entities.SolveResult, error)
+	// Версии таблицы ставок программ (GET /rates/versions)
+	RateVersions(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*entities.RateVersionList, error)
+	// Сравнение ставок двух версий (GET /rates/versions/diff?from=...&to=...)
+	DiffRateVersions(ctx context.Context, in *entities.RateVersionDiffRequest, opts ...grpc.CallOption) (*entities.RateVersionDiff, error)
+	// GET /cache, фильтры и пагинация передаются query-параметрами
+	Cache(ctx context.Context, in *entities.CacheRequest, opts ...grpc.CallOption) (*entities.CacheResult, error)
+	// Расчет из кеша по id (GET /cache/{id})
+	GetCalculation(ctx context.Context, in *entities.CalculationID, opts ...grpc.CallOption) (*entities.LoanResult, error)
+	// Удаление расчета из кеша (DELETE /cache/{id})
+	DeleteCalculation(ctx context.Context, in *entities.CalculationID, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type loanServiceClient struct {
@@ -53,7 +81,77 @@ func (c *loanServiceClient) Execute(ctx context.Context, in *entities.LoanReques
 	return out, nil
 }
 
-func (c *loanServiceClient) Cache(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*entities.CacheResult, error) {
+func (c *loanServiceClient) Schedule(ctx context.Context, in *entities.LoanRequest, opts ...grpc.CallOption) (*entities.ScheduleResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(entities.ScheduleResult)
+	err := c.cc.Invoke(ctx, LoanService_Schedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) Compare(ctx context.Context, in *entities.LoanRequest, opts ...grpc.CallOption) (*entities.CompareResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(entities.CompareResult)
+	err := c.cc.Invoke(ctx, LoanService_Compare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) Prepay(ctx context.Context, in *entities.PrepaymentRequest, opts ...grpc.CallOption) (*entities.PrepaymentResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(entities.PrepaymentResult)
+	err := c.cc.Invoke(ctx, LoanService_Prepay_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) Affordability(ctx context.Context, in *entities.AffordabilityRequest, opts ...grpc.CallOption) (*entities.AffordabilityResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(entities.AffordabilityResult)
+	err := c.cc.Invoke(ctx, LoanService_Affordability_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) Solve(ctx context.Context, in *entities.SolveRequest, opts ...grpc.CallOption) (*entities.SolveResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(entities.SolveResult)
+	err := c.cc.Invoke(ctx, LoanService_Solve_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) RateVersions(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*entities.RateVersionList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(entities.RateVersionList)
+	err := c.cc.Invoke(ctx, LoanService_RateVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) DiffRateVersions(ctx context.Context, in *entities.RateVersionDiffRequest, opts ...grpc.CallOption) (*entities.RateVersionDiff, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(entities.RateVersionDiff)
+	err := c.cc.Invoke(ctx, LoanService_DiffRateVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) Cache(ctx context.Context, in *entities.CacheRequest, opts ...grpc.CallOption) (*entities.CacheResult, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(entities.CacheResult)
 	err := c.cc.Invoke(ctx, LoanService_Cache_FullMethodName, in, out, cOpts...)
@@ -63,14 +161,53 @@ func (c *loanServiceClient) Cache(ctx context.Context, in *emptypb.Empty, opts .
 	return out, nil
 }
 
+func (c *loanServiceClient) GetCalculation(ctx context.Context, in *entities.CalculationID, opts ...grpc.CallOption) (*entities.LoanResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(entities.LoanResult)
+	err := c.cc.Invoke(ctx, LoanService_GetCalculation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) DeleteCalculation(ctx context.Context, in *entities.CalculationID, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, LoanService_DeleteCalculation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LoanServiceServer is the server API for LoanService service.
 // All implementations must embed UnimplementedLoanServiceServer
 // for forward compatibility.
 type LoanServiceServer interface {
 	// Пример gRPC-метода с HTTP-ручкой (POST /execute)
 	Execute(context.Context, *entities.LoanRequest) (*entities.LoanResult, error)
-	// GET /cache
-	Cache(context.Context, *emptypb.Empty) (*entities.CacheResult, error)
+	// Помесячный график платежей (POST /schedule)
+	Schedule(context.Context, *entities.LoanRequest) (*entities.ScheduleResult, error)
+	// Расчет по всем программам реестра на одних параметрах (POST /compare).
+	// Поле program запроса не используется, в кеш ничего не сохраняется
+	Compare(context.Context, *entities.LoanRequest) (*entities.CompareResult, error)
+	// Пересчет графика с досрочными платежами (POST /prepayment), в кеш не сохраняется
+	Prepay(context.Context, *entities.PrepaymentRequest) (*entities.PrepaymentResult, error)
+	// Максимальный кредит и стоимость объекта по ежемесячному бюджету (POST /affordability), в кеш не сохраняется
+	Affordability(context.Context, *entities.AffordabilityRequest) (*entities.AffordabilityResult, error)
+	// Подбор срока, взноса, стоимости или платежа по трем остальным параметрам (POST /solve), в кеш не сохраняется
+	Solve(context.Context, *entities.SolveRequest) (*entities.SolveResult, error)
+	// Версии таблицы ставок программ (GET /rates/versions)
+	RateVersions(context.Context, *emptypb.Empty) (*entities.RateVersionList, error)
+	// Сравнение ставок двух версий (GET /rates/versions/diff?from=...&to=...)
+	DiffRateVersions(context.Context, *entities.RateVersionDiffRequest) (*entities.RateVersionDiff, error)
+	// GET /cache, фильтры и пагинация передаются query-параметрами
+	Cache(context.Context, *entities.CacheRequest) (*entities.CacheResult, error)
+	// Расчет из кеша по id (GET /cache/{id})
+	GetCalculation(context.Context, *entities.CalculationID) (*entities.LoanResult, error)
+	// Удаление расчета из кеша (DELETE /cache/{id})
+	DeleteCalculation(context.Context, *entities.CalculationID) (*emptypb.Empty, error)
 	mustEmbedUnimplementedLoanServiceServer()
 }
 
@@ -84,9 +221,36 @@ type UnimplementedLoanServiceServer struct{}
 func (UnimplementedLoanServiceServer) Execute(context.Context, *entities.LoanRequest) (*entities.LoanResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Execute not implemented")
 }
-func (UnimplementedLoanServiceServer) Cache(context.Context, *emptypb.Empty) (*entities.CacheResult, error) {
+func (UnimplementedLoanServiceServer) Schedule(context.Context, *entities.LoanRequest) (*entities.ScheduleResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Schedule not implemented")
+}
+func (UnimplementedLoanServiceServer) Compare(context.Context, *entities.LoanRequest) (*entities.CompareResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Compare not implemented")
+}
+func (UnimplementedLoanServiceServer) Prepay(context.Context, *entities.PrepaymentRequest) (*entities.PrepaymentResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Prepay not implemented")
+}
+func (UnimplementedLoanServiceServer) Affordability(context.Context, *entities.AffordabilityRequest) (*entities.AffordabilityResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Affordability not implemented")
+}
+func (UnimplementedLoanServiceServer) Solve(context.Context, *entities.SolveRequest) (*entities.SolveResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Solve not implemented")
+}
+func (UnimplementedLoanServiceServer) RateVersions(context.Context, *emptypb.Empty) (*entities.RateVersionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RateVersions not implemented")
+}
+func (UnimplementedLoanServiceServer) DiffRateVersions(context.Context, *entities.RateVersionDiffRequest) (*entities.RateVersionDiff, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffRateVersions not implemented")
+}
+func (UnimplementedLoanServiceServer) Cache(context.Context, *entities.CacheRequest) (*entities.CacheResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Cache not implemented")
 }
+func (UnimplementedLoanServiceServer) GetCalculation(context.Context, *entities.CalculationID) (*entities.LoanResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCalculation not implemented")
+}
+func (UnimplementedLoanServiceServer) DeleteCalculation(context.Context, *entities.CalculationID) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCalculation not implemented")
+}
 func (UnimplementedLoanServiceServer) mustEmbedUnimplementedLoanServiceServer() {}
 func (UnimplementedLoanServiceServer) testEmbeddedByValue()                     {}
 
@@ -126,11 +290,137 @@ func _LoanService_Execute_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
-func _LoanService_Cache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _LoanService_Schedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(entities.LoanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).Schedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_Schedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).Schedule(ctx, req.(*entities.LoanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_Compare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(entities.LoanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).Compare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_Compare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).Compare(ctx, req.(*entities.LoanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_Prepay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(entities.PrepaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).Prepay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_Prepay_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).Prepay(ctx, req.(*entities.PrepaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_Affordability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(entities.AffordabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).Affordability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_Affordability_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).Affordability(ctx, req.(*entities.AffordabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_Solve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(entities.SolveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).Solve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_Solve_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).Solve(ctx, req.(*entities.SolveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_RateVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).RateVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_RateVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).RateVersions(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_DiffRateVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(entities.RateVersionDiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).DiffRateVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_DiffRateVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).DiffRateVersions(ctx, req.(*entities.RateVersionDiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_Cache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(entities.CacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
 	if interceptor == nil {
 		return srv.(LoanServiceServer).Cache(ctx, in)
 	}
@@ -139,7 +429,43 @@ func _LoanService_Cache_Handler(srv interface{}, ctx context.Context, dec func(i
 		FullMethod: LoanService_Cache_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LoanServiceServer).Cache(ctx, req.(*emptypb.Empty))
+		return srv.(LoanServiceServer).Cache(ctx, req.(*entities.CacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_GetCalculation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(entities.CalculationID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).GetCalculation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_GetCalculation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).GetCalculation(ctx, req.(*entities.CalculationID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_DeleteCalculation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(entities.CalculationID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).DeleteCalculation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_DeleteCalculation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).DeleteCalculation(ctx, req.(*entities.CalculationID))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -155,10 +481,46 @@ var LoanService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Execute",
 			Handler:    _LoanService_Execute_Handler,
 		},
+		{
+			MethodName: "Schedule",
+			Handler:    _LoanService_Schedule_Handler,
+		},
+		{
+			MethodName: "Compare",
+			Handler:    _LoanService_Compare_Handler,
+		},
+		{
+			MethodName: "Prepay",
+			Handler:    _LoanService_Prepay_Handler,
+		},
+		{
+			MethodName: "Affordability",
+			Handler:    _LoanService_Affordability_Handler,
+		},
+		{
+			MethodName: "Solve",
+			Handler:    _LoanService_Solve_Handler,
+		},
+		{
+			MethodName: "RateVersions",
+			Handler:    _LoanService_RateVersions_Handler,
+		},
+		{
+			MethodName: "DiffRateVersions",
+			Handler:    _LoanService_DiffRateVersions_Handler,
+		},
 		{
 			MethodName: "Cache",
 			Handler:    _LoanService_Cache_Handler,
 		},
+		{
+			MethodName: "GetCalculation",
+			Handler:    _LoanService_GetCalculation_Handler,
+		},
+		{
+			MethodName: "DeleteCalculation",
+			Handler:    _LoanService_DeleteCalculation_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/protos/services/loan_service.proto",
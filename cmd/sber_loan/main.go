@@ -2,12 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
+	"expvar"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -17,7 +21,9 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	"github.com/Dorji/sberInterview/api/protos/services"
+	db "github.com/Dorji/sberInterview/internal/db/storage"
 	"github.com/Dorji/sberInterview/internal/loanservice"
+	"github.com/Dorji/sberInterview/internal/loanservice/gateway"
 	"github.com/Dorji/sberInterview/internal/loanservice/interceptors"
 	loadconfig "github.com/Dorji/sberInterview/internal/loanservice/load_config"
 	"github.com/Dorji/sberInterview/internal/loanservice/storage"
@@ -28,9 +34,15 @@ var (
 	grpcAddr string
 )
 
+const (
+	configPath = "config.yml"
+	// configPollInterval — как часто проверять изменения файлов конфига и программ
+	configPollInterval = 2 * time.Second
+)
+
 func main() {
 	// Load configuration
-	config, err := loadconfig.LoadConfig("config.yml")
+	config, err := loadconfig.LoadConfig(configPath)
 	if err != nil {
 		log.Printf("Config warning: %v", err)
 	}
@@ -48,7 +60,86 @@ func main() {
 			interceptors.LoggingUnaryInterceptor,
 		),
 	)
-	registerGRPCHandlers(grpcSrv)
+	programs, err := config.ProgramRegistry()
+	if err != nil {
+		log.Fatalf("invalid loan programs: %v", err)
+	}
+	keyRates, err := config.KeyRateProvider()
+	if err != nil {
+		log.Fatalf("invalid key rate config: %v", err)
+	}
+	backend, err := config.StorageBackend()
+	if err != nil {
+		log.Fatalf("invalid storage config: %v", err)
+	}
+	var (
+		repo        storage.CalculationRepository
+		myCache     *storage.LoanCache
+		fileStore   *storage.FileStore
+		persistence *storage.Persistence
+	)
+	switch backend {
+	case loadconfig.StorageFile:
+		fileStore, err = storage.OpenFileStore(config.Storage.Path)
+		if errors.Is(err, storage.ErrCorrupted) {
+			// Битый хвост файла обрезан, сервер стартует с уцелевшими расчетами
+			log.Printf("Storage open warning: %v", err)
+		} else if err != nil {
+			log.Fatalf("failed to open storage: %v", err)
+		}
+		expvar.Publish("loan_storage", expvar.Func(func() any { return fileStore.Stats() }))
+		repo = fileStore
+	default:
+		cacheOpts, err := config.CacheOptions()
+		if err != nil {
+			log.Fatalf("invalid cache config: %v", err)
+		}
+		if config.Cache.PersistDir != "" {
+			persistence, err = storage.OpenPersistence(config.Cache.PersistDir)
+			if err != nil {
+				log.Fatalf("failed to open cache persistence: %v", err)
+			}
+			cacheOpts = append(cacheOpts, storage.WithPersistence(persistence))
+		}
+		myCache = storage.NewLoanCache(cacheOpts...)
+		if err := myCache.Restore(); err != nil {
+			// Поврежденные записи пропускаются, сервер стартует с тем, что удалось восстановить
+			log.Printf("Cache restore warning: %v", err)
+		}
+		if persistence != nil && config.Cache.SnapshotInterval > 0 {
+			go snapshotLoop(ctx, myCache, config.Cache.SnapshotInterval)
+		}
+		expvar.Publish("loan_cache", expvar.Func(func() any { return myCache.Stats() }))
+		repo = myCache
+	}
+	ls := registerGRPCHandlers(grpcSrv, repo, programs, keyRates, config.Cache.Memoize)
+
+	// Перезагрузка ставок и источника ключевой ставки по SIGHUP и при изменении файлов
+	reloader := loadconfig.NewReloader(configPath, config, func(old, new *loadconfig.Config) error {
+		programs, err := new.ProgramRegistry()
+		if err != nil {
+			return err
+		}
+		// Источник пересоздается только при смене настроек, чтобы не терять кеш ставок
+		if new.KeyRate != old.KeyRate {
+			if keyRates, err = new.KeyRateProvider(); err != nil {
+				return err
+			}
+		}
+		return ls.Reload(programs, keyRates)
+	})
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go reloader.Watch(ctx, configPollInterval, hupChan)
+
+	// Админский API управления программами на отдельном порту
+	var adminSrv *adminServer
+	if config.Admin.Port != "" {
+		adminSrv, err = startAdminServer(ctx, config, ls, programs, reloader.Locker())
+		if err != nil {
+			log.Fatalf("failed to start admin API: %v", err)
+		}
+	}
 
 	// 2. Start gRPC server
 	lis, err := net.Listen("tcp", grpcAddr)
@@ -66,7 +157,10 @@ func main() {
 	httpMux := http.NewServeMux()
 	
 	// 4. Create gRPC Gateway router
-	gwMux := runtime.NewServeMux()
+	gwMux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, gateway.NewMarshaler()),
+		runtime.WithErrorHandler(gateway.ErrorHandler),
+	)
 	if err := registerHTTPHandlers(ctx, gwMux); err != nil {
 		log.Fatalf("failed to register HTTP handlers: %v", err)
 	}
@@ -74,6 +168,20 @@ func main() {
 	// 5. Combine routers
 	httpMux.Handle("/", gwMux)
 
+	// Счетчики кеша и хранилища — только на служебном адресе, не на публичном порту
+	var debugSrv *http.Server
+	if config.Debug.Addr != "" {
+		debugMux := http.NewServeMux()
+		debugMux.Handle("/debug/vars", expvar.Handler())
+		debugSrv = &http.Server{Addr: config.Debug.Addr, Handler: debugMux}
+		go func() {
+			log.Printf("Debug server listening on %s", config.Debug.Addr)
+			if err := debugSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Debug server error: %v", err)
+			}
+		}()
+	}
+
 	// 6. Configure HTTP server
 	httpSrv := &http.Server{
 		Addr:    httpAddr,
@@ -81,7 +189,9 @@ func main() {
 	}
 
 	// 7. Graceful shutdown
+	stopped := make(chan struct{})
 	go func() {
+		defer close(stopped)
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
@@ -96,6 +206,27 @@ func main() {
 		}
 
 		grpcSrv.GracefulStop()
+		if debugSrv != nil {
+			if err := debugSrv.Shutdown(shutdownCtx); err != nil {
+				log.Printf("Debug server shutdown error: %v", err)
+			}
+		}
+		if adminSrv != nil {
+			adminSrv.shutdown(shutdownCtx)
+		}
+		if persistence != nil {
+			if err := myCache.Snapshot(); err != nil {
+				log.Printf("Cache snapshot error: %v", err)
+			}
+			if err := persistence.Close(); err != nil {
+				log.Printf("Cache persistence close error: %v", err)
+			}
+		}
+		if fileStore != nil {
+			if err := fileStore.Close(); err != nil {
+				log.Printf("Storage close error: %v", err)
+			}
+		}
 		log.Println("Servers stopped gracefully")
 	}()
 
@@ -104,17 +235,137 @@ func main() {
 	if err := httpSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		log.Fatalf("HTTP server error: %v", err)
 	}
+	// Ждем остановки gRPC и сохранения кеша
+	<-stopped
 }
 
 
-func registerGRPCHandlers(grpcSrv *grpc.Server) {
-	myCache := storage.NewLoanCache()
-	ls, err := loanservice.NewLoanService(myCache)
+// snapshotLoop периодически сохраняет снимок кеша, чтобы журнал не рос бесконечно
+func snapshotLoop(ctx context.Context, cache *storage.LoanCache, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := cache.Snapshot(); err != nil {
+				log.Printf("Cache snapshot error: %v", err)
+			}
+		}
+	}
+}
+
+func registerGRPCHandlers(grpcSrv *grpc.Server, repo storage.CalculationRepository, programs *db.ProgramRegistry, keyRates db.KeyRateProvider, memoize bool) *loanservice.LoanServiceServer {
+	ls, err := loanservice.NewLoanService(repo,
+		loanservice.WithPrograms(programs),
+		loanservice.WithKeyRates(keyRates),
+		loanservice.WithMemoization(memoize),
+	)
 	if err != nil {
 		log.Fatalf("start NewLoanService error: %v", err)
 	}
 	services.RegisterLoanServiceServer(grpcSrv, ls)
 	reflection.Register(grpcSrv)
+	return ls
+}
+
+// adminServer — gRPC и HTTP-ручки /admin/ админского API на одном порту
+type adminServer struct {
+	httpSrv *http.Server
+	grpcSrv *grpc.Server
+	audit   *db.FileAuditLog
+}
+
+// startAdminServer поднимает админский API. gRPC и gateway обслуживаются одним HTTP-сервером:
+// запросы gRPC по HTTP/2 без TLS уходят в grpcSrv, остальные — в gateway, который вызывает
+// тот же порт, чтобы токен проверялся в одном месте.
+func startAdminServer(ctx context.Context, config *loadconfig.Config, ls *loanservice.LoanServiceServer, programs *db.ProgramRegistry, lock sync.Locker) (*adminServer, error) {
+	tokens, err := config.AdminTokens()
+	if err != nil {
+		return nil, err
+	}
+	auth, err := interceptors.NewTokenAuth(tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	// Пустое хранилище заполняется текущим реестром, дальше программы меняются только через API
+	store := db.NewFileProgramStore(config.ProgramsFile)
+	if _, err := store.Load(); errors.Is(err, os.ErrNotExist) {
+		if err := store.Save(programs.All()); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	audit, err := db.OpenFileAuditLog(config.Admin.AuditPath)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := loanservice.NewLoanAdminService(ls, store, audit, lock)
+	if err != nil {
+		return nil, err
+	}
+
+	adminAddr := ":" + config.Admin.Port
+	grpcSrv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			interceptors.RecoveryUnaryInterceptor,
+			interceptors.LoggingUnaryInterceptor,
+			auth.UnaryInterceptor,
+		),
+	)
+	services.RegisterLoanAdminServiceServer(grpcSrv, admin)
+
+	gwMux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, gateway.NewMarshaler()),
+		runtime.WithErrorHandler(gateway.ErrorHandler),
+	)
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if err := services.RegisterLoanAdminServiceHandlerFromEndpoint(ctx, gwMux, adminAddr, opts); err != nil {
+		return nil, fmt.Errorf("failed to register admin gateway: %v", err)
+	}
+	httpMux := http.NewServeMux()
+	httpMux.Handle("/admin/", gwMux)
+
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	httpSrv := &http.Server{
+		Addr: adminAddr,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+				grpcSrv.ServeHTTP(w, r)
+				return
+			}
+			interceptors.RecoveryMiddleware(interceptors.LoggingMiddleware(httpMux)).ServeHTTP(w, r)
+		}),
+		Protocols: protocols,
+	}
+
+	lis, err := net.Listen("tcp", adminAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %v", err)
+	}
+	go func() {
+		log.Printf("Admin API listening on %s", adminAddr)
+		if err := httpSrv.Serve(lis); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Admin API error: %v", err)
+		}
+	}()
+	return &adminServer{httpSrv: httpSrv, grpcSrv: grpcSrv, audit: audit}, nil
+}
+
+// shutdown останавливает админский API и закрывает журнал изменений
+func (s *adminServer) shutdown(ctx context.Context) {
+	if err := s.httpSrv.Shutdown(ctx); err != nil {
+		log.Printf("Admin API shutdown error: %v", err)
+	}
+	s.grpcSrv.Stop()
+	if err := s.audit.Close(); err != nil {
+		log.Printf("Audit log close error: %v", err)
+	}
 }
 
 func registerHTTPHandlers(ctx context.Context, mux *runtime.ServeMux) error {
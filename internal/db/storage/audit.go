@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Действия с программами в журнале изменений
+const (
+	AuditCreate     = "create"
+	AuditUpdate     = "update"
+	AuditSetRate    = "set_rate"
+	AuditDeactivate = "deactivate"
+)
+
+// AuditEntry — запись журнала изменений программ: кто, когда и что поменял
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"`
+	Action string    `json:"action"`
+	Code   string    `json:"code"`
+	Before *Program  `json:"before,omitempty"` // пусто при создании
+	After  *Program  `json:"after"`
+}
+
+// AuditLog — журнал изменений программ
+type AuditLog interface {
+	Record(entry AuditEntry) error
+}
+
+// FileAuditLog дописывает записи в файл по одной JSON-строке и сбрасывает их на диск
+type FileAuditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFileAuditLog открывает журнал на дозапись, файл создается при первом открытии
+func OpenFileAuditLog(path string) (*FileAuditLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %v", err)
+	}
+	return &FileAuditLog{file: file}, nil
+}
+
+// Record дописывает запись и ждет ее сброса на диск
+func (l *FileAuditLog) Record(entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("audit record: %v", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.file.Write(line); err != nil {
+		return fmt.Errorf("audit record: %v", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("audit record: %v", err)
+	}
+	return nil
+}
+
+// Close закрывает файл журнала
+func (l *FileAuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dorji/sberInterview/internal/money"
+)
+
+// ErrNoKeyRate — у источника нет ключевой ставки на запрошенную дату
+var ErrNoKeyRate = errors.New("no key rate for the date")
+
+// KeyRate — ключевая ставка Банка России, действующая с даты Date
+type KeyRate struct {
+	Rate  money.BasisPoints
+	Date  time.Time
+	Stale bool // источник недоступен, отдано последнее полученное значение
+}
+
+// KeyRateProvider — источник ключевой ставки для программ с плавающей ставкой
+type KeyRateProvider interface {
+	// KeyRate возвращает ставку, действующую на дату date
+	KeyRate(ctx context.Context, date time.Time) (KeyRate, error)
+}
+
+// effectiveKeyRate выбирает из значений ставку с последней датой не позже date
+func effectiveKeyRate(rates []KeyRate, date time.Time) (KeyRate, error) {
+	var res KeyRate
+	found := false
+	for _, r := range rates {
+		if !r.Date.After(date) && (!found || r.Date.After(res.Date)) {
+			res, found = r, true
+		}
+	}
+	if !found {
+		return KeyRate{}, fmt.Errorf("%w %s", ErrNoKeyRate, date.Format(time.DateOnly))
+	}
+	return res, nil
+}
+
+// parseKeyRate переводит ставку в процентах ("16.00" или "16,00") в базисные пункты
+func parseKeyRate(s string) (money.BasisPoints, error) {
+	percent, ok := new(big.Rat).SetString(strings.Replace(strings.TrimSpace(s), ",", ".", 1))
+	if !ok {
+		return 0, fmt.Errorf("invalid key rate %q", s)
+	}
+	bps := percent.Mul(percent, big.NewRat(100, 1))
+	if !bps.IsInt() || bps.Sign() <= 0 {
+		return 0, fmt.Errorf("invalid key rate %q", s)
+	}
+	return money.BasisPoints(bps.Num().Int64()), nil
+}
+
+// dateOnly отбрасывает время, оставляя дату в UTC
+func dateOnly(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// maxCachedKeyRateDates ограничивает число дат в кеше: даты выдачи выбирает клиент
+const maxCachedKeyRateDates = 1024
+
+// cachedKeyRate — ставка на дату и время, до которого она не запрашивается повторно
+type cachedKeyRate struct {
+	rate    KeyRate
+	expires time.Time
+}
+
+// keyRateFetch — запрос к источнику за ставкой на дату, который ждут все, кому нужна эта дата
+type keyRateFetch struct {
+	done chan struct{}
+	rate KeyRate
+	err  error
+}
+
+// CachedKeyRates кеширует ставки источника на ttl по датам, не больше maxCachedKeyRateDates дат.
+// Если источник недоступен, отдается последнее полученное значение на эту дату или самое позднее
+// из известных до нее с признаком Stale. На одну дату к источнику идет один запрос, остальные ждут его,
+// а запросы на разные даты друг друга не блокируют.
+type CachedKeyRates struct {
+	next KeyRateProvider
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu       sync.Mutex
+	byDate   map[time.Time]cachedKeyRate
+	inflight map[time.Time]*keyRateFetch
+	known    []KeyRate
+}
+
+// NewCachedKeyRates оборачивает источник кешем с временем жизни ttl
+func NewCachedKeyRates(next KeyRateProvider, ttl time.Duration) *CachedKeyRates {
+	return &CachedKeyRates{
+		next:     next,
+		ttl:      ttl,
+		now:      time.Now,
+		byDate:   make(map[time.Time]cachedKeyRate),
+		inflight: make(map[time.Time]*keyRateFetch),
+	}
+}
+
+// KeyRate отдает ставку из кеша или источника, при ошибке источника — последнюю известную
+func (c *CachedKeyRates) KeyRate(ctx context.Context, date time.Time) (KeyRate, error) {
+	day := dateOnly(date)
+
+	c.mu.Lock()
+	if cached, ok := c.byDate[day]; ok && c.now().Before(cached.expires) {
+		c.mu.Unlock()
+		return cached.rate, nil
+	}
+	fetch, running := c.inflight[day]
+	if !running {
+		fetch = &keyRateFetch{done: make(chan struct{})}
+		c.inflight[day] = fetch
+	}
+	c.mu.Unlock()
+
+	// Источник вызывается без блокировки: медленный ответ на одну дату не держит остальные
+	if !running {
+		fetch.rate, fetch.err = c.next.KeyRate(ctx, day)
+		c.mu.Lock()
+		delete(c.inflight, day)
+		if fetch.err == nil {
+			c.store(day, fetch.rate)
+		}
+		c.mu.Unlock()
+		close(fetch.done)
+	}
+	select {
+	case <-fetch.done:
+		if fetch.err == nil {
+			return fetch.rate, nil
+		}
+		return c.fallback(day, fetch.err)
+	case <-ctx.Done():
+		return c.fallback(day, ctx.Err())
+	}
+}
+
+// store кладет ставку в кеш, при переполнении вытесняя истекшие даты, а без них — ближайшую к истечению
+func (c *CachedKeyRates) store(day time.Time, rate KeyRate) {
+	if _, ok := c.byDate[day]; !ok && len(c.byDate) >= maxCachedKeyRateDates {
+		now := c.now()
+		var oldest time.Time
+		for d, cached := range c.byDate {
+			if !now.Before(cached.expires) {
+				delete(c.byDate, d)
+			} else if oldest.IsZero() || cached.expires.Before(c.byDate[oldest].expires) {
+				oldest = d
+			}
+		}
+		if len(c.byDate) >= maxCachedKeyRateDates {
+			delete(c.byDate, oldest)
+		}
+	}
+	c.byDate[day] = cachedKeyRate{rate: rate, expires: c.now().Add(c.ttl)}
+	for _, k := range c.known {
+		if k.Date.Equal(rate.Date) {
+			return
+		}
+	}
+	c.known = append(c.known, rate)
+}
+
+// fallback отдает при ошибке источника последнее значение на дату или последнее известное до нее
+func (c *CachedKeyRates) fallback(day time.Time, err error) (KeyRate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.byDate[day]; ok {
+		cached.rate.Stale = true
+		return cached.rate, nil
+	}
+	if known, knownErr := effectiveKeyRate(c.known, day); knownErr == nil {
+		known.Stale = true
+		return known, nil
+	}
+	return KeyRate{}, err
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// CBRKeyRateURL — метод KeyRateXML веб-сервиса DailyInfo Банка России
+const CBRKeyRateURL = "https://www.cbr.ru/DailyInfoWebServ/DailyInfo.asmx/KeyRateXML"
+
+// cbrLookback — за сколько дней до нужной даты запрашиваются ставки: ставка
+// меняется реже, поэтому в окне всегда есть хотя бы одно решение совета директоров
+const cbrLookback = 366
+
+// CBRKeyRates получает ключевую ставку по HTTP в XML-формате Банка России:
+//
+//	<KeyRate><KR><DT>2024-07-29T00:00:00+03:00</DT><Rate>18.00</Rate></KR>...</KeyRate>
+//
+// Адрес задается в конфиге, в тестах и локально — заглушка с тем же форматом.
+type CBRKeyRates struct {
+	url    string
+	client *http.Client
+}
+
+// NewCBRKeyRates создает источник ставок по адресу endpoint, пустой адрес — сервис Банка России
+func NewCBRKeyRates(endpoint string, client *http.Client) *CBRKeyRates {
+	if endpoint == "" {
+		endpoint = CBRKeyRateURL
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &CBRKeyRates{url: endpoint, client: client}
+}
+
+// KeyRate запрашивает ставки за год до date и возвращает действующую на date
+func (c *CBRKeyRates) KeyRate(ctx context.Context, date time.Time) (KeyRate, error) {
+	day := dateOnly(date)
+	endpoint, err := url.Parse(c.url)
+	if err != nil {
+		return KeyRate{}, fmt.Errorf("cbr key rate: %v", err)
+	}
+	query := endpoint.Query()
+	query.Set("fromDate", day.AddDate(0, 0, -cbrLookback).Format(time.DateOnly))
+	query.Set("ToDate", day.Format(time.DateOnly))
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return KeyRate{}, fmt.Errorf("cbr key rate: %v", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return KeyRate{}, fmt.Errorf("cbr key rate: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return KeyRate{}, fmt.Errorf("cbr key rate: unexpected status %s", resp.Status)
+	}
+
+	rates, err := parseCBRKeyRates(resp.Body)
+	if err != nil {
+		return KeyRate{}, fmt.Errorf("cbr key rate: %v", err)
+	}
+	return effectiveKeyRate(rates, day)
+}
+
+// parseCBRKeyRates разбирает элементы KR на любой глубине: ответ сервиса
+// может быть обернут в SOAP-конверт или DataSet
+func parseCBRKeyRates(r io.Reader) ([]KeyRate, error) {
+	var rates []KeyRate
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return rates, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "KR" {
+			continue
+		}
+
+		var row struct {
+			DT   string `xml:"DT"`
+			Rate string `xml:"Rate"`
+		}
+		if err := dec.DecodeElement(&row, &start); err != nil {
+			return nil, err
+		}
+		date, err := time.Parse(time.RFC3339, row.DT)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q", row.DT)
+		}
+		rate, err := parseKeyRate(row.Rate)
+		if err != nil {
+			return nil, err
+		}
+		// Дата берется в часовом поясе ответа: полночь по Москве — это дата решения
+		y, m, d := date.Date()
+		rates = append(rates, KeyRate{Rate: rate, Date: time.Date(y, m, d, 0, 0, 0, 0, time.UTC)})
+	}
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// keyRateFile — формат файла ключевых ставок:
+//
+//	key_rates:
+//	  - date: 2024-07-29
+//	    rate: "18.00"
+type keyRateFile struct {
+	KeyRates []struct {
+		Date string `yaml:"date"`
+		Rate string `yaml:"rate"`
+	} `yaml:"key_rates"`
+}
+
+// FileKeyRates читает ключевые ставки из локального YAML-файла. Файл перечитывается
+// при каждом запросе, поэтому новое значение подхватывается без перезапуска.
+type FileKeyRates struct {
+	path string
+}
+
+// NewFileKeyRates создает источник ставок из файла path
+func NewFileKeyRates(path string) *FileKeyRates {
+	return &FileKeyRates{path: path}
+}
+
+// KeyRate возвращает ставку из файла с последней датой не позже date
+func (f *FileKeyRates) KeyRate(ctx context.Context, date time.Time) (KeyRate, error) {
+	rates, err := f.load()
+	if err != nil {
+		return KeyRate{}, err
+	}
+	return effectiveKeyRate(rates, dateOnly(date))
+}
+
+func (f *FileKeyRates) load() ([]KeyRate, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key rates file: %v", err)
+	}
+	var file keyRateFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error parsing key rates file: %v", err)
+	}
+
+	rates := make([]KeyRate, 0, len(file.KeyRates))
+	for i, r := range file.KeyRates {
+		date, err := time.Parse(time.DateOnly, r.Date)
+		if err != nil {
+			return nil, fmt.Errorf("key rates file: entry %d: invalid date %q", i, r.Date)
+		}
+		rate, err := parseKeyRate(r.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("key rates file: entry %d: %v", i, err)
+		}
+		rates = append(rates, KeyRate{Rate: rate, Date: date})
+	}
+	return rates, nil
+}
@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const cbrKeyRateXML = `<?xml version="1.0" encoding="utf-8"?>
+<KeyRate xmlns="">
+  <KR><DT>2024-07-29T00:00:00+03:00</DT><Rate>18.00</Rate></KR>
+  <KR><DT>2023-12-18T00:00:00+03:00</DT><Rate>16.00</Rate></KR>
+  <KR><DT>2023-10-30T00:00:00+03:00</DT><Rate>15.00</Rate></KR>
+</KeyRate>`
+
+func TestFileKeyRates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key_rates.yml")
+	if err := os.WriteFile(path, []byte(`key_rates:
+  - date: 2023-12-18
+    rate: "16.00"
+  - date: 2024-07-29
+    rate: "18,00"
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider := NewFileKeyRates(path)
+
+	tests := []struct {
+		name     string
+		date     time.Time
+		wantRate int64
+		wantDate time.Time
+		wantErr  bool
+	}{
+		{"Day of the decision", time.Date(2024, 7, 29, 15, 0, 0, 0, time.UTC), 1800, time.Date(2024, 7, 29, 0, 0, 0, 0, time.UTC), false},
+		{"Between decisions", time.Date(2024, 2, 18, 0, 0, 0, 0, time.UTC), 1600, time.Date(2023, 12, 18, 0, 0, 0, 0, time.UTC), false},
+		{"Before the first decision", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), 0, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := provider.KeyRate(context.Background(), tt.date)
+			if tt.wantErr {
+				if !errors.Is(err, ErrNoKeyRate) {
+					t.Errorf("Expected ErrNoKeyRate, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if int64(got.Rate) != tt.wantRate || !got.Date.Equal(tt.wantDate) {
+				t.Errorf("Expected %d from %v, got %d from %v", tt.wantRate, tt.wantDate, got.Rate, got.Date)
+			}
+		})
+	}
+
+	t.Run("Invalid rate", func(t *testing.T) {
+		broken := filepath.Join(t.TempDir(), "broken.yml")
+		if err := os.WriteFile(broken, []byte("key_rates:\n  - date: 2024-07-29\n    rate: \"18.005\"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewFileKeyRates(broken).KeyRate(context.Background(), time.Now()); err == nil {
+			t.Error("Expected error for a rate finer than a basis point")
+		}
+	})
+}
+
+func TestCBRKeyRates(t *testing.T) {
+	var query string
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprint(w, cbrKeyRateXML)
+	}))
+	defer stub.Close()
+
+	got, err := NewCBRKeyRates(stub.URL, stub.Client()).KeyRate(context.Background(), time.Date(2024, 2, 18, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Rate != 1600 || !got.Date.Equal(time.Date(2023, 12, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 1600 from 2023-12-18, got %d from %v", got.Rate, got.Date)
+	}
+	if want := "ToDate=2024-02-18&fromDate=2023-02-17"; query != want {
+		t.Errorf("Expected query %q, got %q", want, query)
+	}
+
+	t.Run("SOAP envelope", func(t *testing.T) {
+		rates, err := parseCBRKeyRates(strings.NewReader(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>
+<KeyRateXMLResponse><KeyRateXMLResult>` + cbrKeyRateXML[len(`<?xml version="1.0" encoding="utf-8"?>`):] + `</KeyRateXMLResult></KeyRateXMLResponse>
+</soap:Body></soap:Envelope>`))
+		if err != nil || len(rates) != 3 {
+			t.Errorf("Expected 3 rates, got %v, %v", rates, err)
+		}
+	})
+
+	t.Run("Server error", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "maintenance", http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+		if _, err := NewCBRKeyRates(failing.URL, failing.Client()).KeyRate(context.Background(), time.Now()); err == nil {
+			t.Error("Expected error for 503")
+		}
+	})
+}
+
+// keyRateStub отдает заданную ставку или ошибку и считает обращения
+type keyRateStub struct {
+	rate  KeyRate
+	err   error
+	calls int
+}
+
+func (s *keyRateStub) KeyRate(ctx context.Context, date time.Time) (KeyRate, error) {
+	s.calls++
+	return s.rate, s.err
+}
+
+func TestCachedKeyRates(t *testing.T) {
+	now := time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	decision := time.Date(2024, 7, 29, 0, 0, 0, 0, time.UTC)
+	stub := &keyRateStub{rate: KeyRate{Rate: 1800, Date: decision}}
+	cached := NewCachedKeyRates(stub, time.Hour)
+	cached.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if got, err := cached.KeyRate(ctx, now); err != nil || got.Rate != 1800 || got.Stale {
+			t.Fatalf("Expected fresh 1800, got %+v, %v", got, err)
+		}
+	}
+	if stub.calls != 1 {
+		t.Errorf("Expected 1 call within ttl, got %d", stub.calls)
+	}
+
+	// Источник недоступен: после ttl отдается прежнее значение с признаком Stale
+	stub.err = errors.New("connection refused")
+	now = now.Add(2 * time.Hour)
+	got, err := cached.KeyRate(ctx, now)
+	if err != nil || got.Rate != 1800 || !got.Stale {
+		t.Errorf("Expected stale 1800 for the cached date, got %+v, %v", got, err)
+	}
+	if stub.calls != 2 {
+		t.Errorf("Expected a retry after ttl, got %d calls", stub.calls)
+	}
+
+	// Для новой даты берется последнее известное значение не позже нее
+	got, err = cached.KeyRate(ctx, now.AddDate(0, 0, 10))
+	if err != nil || got.Rate != 1800 || !got.Stale {
+		t.Errorf("Expected last known good 1800, got %+v, %v", got, err)
+	}
+	if _, err := cached.KeyRate(ctx, decision.AddDate(0, 0, -1)); err == nil {
+		t.Error("Expected error before the first known decision")
+	}
+}
+
+// blockingKeyRates отвечает, только когда закрыт release, и считает обращения по датам
+type blockingKeyRates struct {
+	release chan struct{}
+	mu      sync.Mutex
+	calls   map[time.Time]int
+}
+
+func (b *blockingKeyRates) KeyRate(ctx context.Context, date time.Time) (KeyRate, error) {
+	b.mu.Lock()
+	b.calls[date]++
+	b.mu.Unlock()
+	select {
+	case <-b.release:
+		return KeyRate{Rate: 1600, Date: date}, nil
+	case <-ctx.Done():
+		return KeyRate{}, ctx.Err()
+	}
+}
+
+func TestCachedKeyRatesConcurrency(t *testing.T) {
+	source := &blockingKeyRates{release: make(chan struct{}), calls: make(map[time.Time]int)}
+	cached := NewCachedKeyRates(source, time.Hour)
+	day := time.Date(2024, 2, 18, 0, 0, 0, 0, time.UTC)
+
+	// Пока источник не ответил на одну дату, другая дата не ждет общей блокировки
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, err := cached.KeyRate(context.Background(), day); err != nil || got.Rate != 1600 {
+				t.Errorf("Expected 1600, got %+v, %v", got, err)
+			}
+		}()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := cached.KeyRate(ctx, day.AddDate(0, 0, 1)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the other date to time out on its own, got %v", err)
+	}
+	close(source.release)
+	wg.Wait()
+	if source.calls[day] != 1 {
+		t.Errorf("Expected one fetch for concurrent requests of a date, got %d", source.calls[day])
+	}
+
+	// Даты выдачи выбирает клиент, поэтому их число в кеше ограничено
+	for i := 0; i < maxCachedKeyRateDates+10; i++ {
+		if _, err := cached.KeyRate(context.Background(), day.AddDate(0, 0, i)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if len(cached.byDate) > maxCachedKeyRateDates {
+		t.Errorf("Expected at most %d cached dates, got %d", maxCachedKeyRateDates, len(cached.byDate))
+	}
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain — домен машиночитаемых причин ошибок в errdetails.ErrorInfo
+const ErrorDomain = "loan.sberInterview"
+
+// Машиночитаемые причины отказа по выбору программы и ее ограничениям
+const (
+	ReasonProgramNotSelected   = "PROGRAM_NOT_SELECTED"
+	ReasonProgramAmbiguous     = "PROGRAM_AMBIGUOUS"
+	ReasonProgramUnknown       = "PROGRAM_UNKNOWN"
+	ReasonProgramInactive      = "PROGRAM_INACTIVE"
+	ReasonInitialPaymentTooLow = "INITIAL_PAYMENT_TOO_LOW"
+	ReasonTermTooShort         = "TERM_TOO_SHORT"
+	ReasonTermTooLong          = "TERM_TOO_LONG"
+	ReasonLoanSumTooSmall      = "LOAN_SUM_TOO_SMALL"
+	ReasonLoanSumTooLarge      = "LOAN_SUM_TOO_LARGE"
+)
+
+// LimitViolations проверяет запрос на ограничения программы: долю первоначального взноса,
+// срок и сумму кредита. Возвращает все нарушения; сумма кредита относится к полю
+// initial_payment, так как при выбранном объекте она меняется взносом.
+func (p Program) LimitViolations(objectCost, initialPayment, months int64) []*errdetails.BadRequest_FieldViolation {
+	var res []*errdetails.BadRequest_FieldViolation
+	add := func(field, reason, format string, args ...interface{}) {
+		res = append(res, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Reason:      reason,
+			Description: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if float64(initialPayment)/float64(objectCost) < p.MinInitialPayment {
+		add("initial_payment", ReasonInitialPaymentTooLow, "the initial payment should be more")
+	}
+	if p.MinMonths > 0 && months < p.MinMonths {
+		add("months", ReasonTermTooShort, "the term should be at least %d months", p.MinMonths)
+	}
+	if p.MaxMonths > 0 && months > p.MaxMonths {
+		add("months", ReasonTermTooLong, "the term should be at most %d months", p.MaxMonths)
+	}
+
+	loanSum := objectCost - initialPayment
+	if p.MinLoanSum > 0 && loanSum < p.MinLoanSum {
+		add("initial_payment", ReasonLoanSumTooSmall, "the loan sum should be at least %d", p.MinLoanSum)
+	}
+	if p.MaxLoanSum > 0 && loanSum > p.MaxLoanSum {
+		add("initial_payment", ReasonLoanSumTooLarge, "the loan sum should be at most %d", p.MaxLoanSum)
+	}
+	return res
+}
+
+// reasonError возвращает InvalidArgument с машиночитаемой причиной в errdetails.ErrorInfo
+func reasonError(reason string, metadata map[string]string, format string, args ...interface{}) error {
+	st := status.New(codes.InvalidArgument, fmt.Sprintf(format, args...))
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   ErrorDomain,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
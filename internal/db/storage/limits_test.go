@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"testing"
+)
+
+func TestProgramLimitViolations(t *testing.T) {
+	program := Program{
+		Code:              MilitaryProgram,
+		AnnualRate:        900,
+		MinInitialPayment: 0.20,
+		MinMonths:         12,
+		MaxMonths:         300,
+		MinLoanSum:        300_000,
+		MaxLoanSum:        5_000_000,
+	}
+
+	type violation struct {
+		field, reason, msg string
+	}
+	tests := []struct {
+		name           string
+		objectCost     int64
+		initialPayment int64
+		months         int64
+		want           []violation
+	}{
+		{"Within limits", 5_000_000, 1_000_000, 240, nil},
+		{"Initial payment above share", 5_000_000, 1_500_000, 240, nil},
+		{"Initial payment below share", 3_000_000, 500_000, 240, []violation{
+			{"initial_payment", ReasonInitialPaymentTooLow, "the initial payment should be more"},
+		}},
+		{"Term too short", 5_000_000, 1_000_000, 6, []violation{
+			{"months", ReasonTermTooShort, "the term should be at least 12 months"},
+		}},
+		{"Term too long", 5_000_000, 1_000_000, 360, []violation{
+			{"months", ReasonTermTooLong, "the term should be at most 300 months"},
+		}},
+		{"Loan sum too small", 500_000, 300_000, 240, []violation{
+			{"initial_payment", ReasonLoanSumTooSmall, "the loan sum should be at least 300000"},
+		}},
+		{"Loan sum too large", 10_000_000, 2_000_000, 240, []violation{
+			{"initial_payment", ReasonLoanSumTooLarge, "the loan sum should be at most 5000000"},
+		}},
+		{"All violations at once", 10_000_000, 0, 360, []violation{
+			{"initial_payment", ReasonInitialPaymentTooLow, "the initial payment should be more"},
+			{"months", ReasonTermTooLong, "the term should be at most 300 months"},
+			{"initial_payment", ReasonLoanSumTooLarge, "the loan sum should be at most 5000000"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := program.LimitViolations(tt.objectCost, tt.initialPayment, tt.months)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d violations, got %v", len(tt.want), got)
+			}
+			for i, w := range tt.want {
+				if got[i].Field != w.field || got[i].Reason != w.reason || got[i].Description != w.msg {
+					t.Errorf("Violation %d: expected %+v, got %v", i, w, got[i])
+				}
+			}
+		})
+	}
+
+	t.Run("Zero limits are not checked", func(t *testing.T) {
+		unlimited := Program{Code: BaseProgram, AnnualRate: 1000}
+		if got := unlimited.LimitViolations(100_000_000, 0, 1200); len(got) != 0 {
+			t.Errorf("Unexpected violations: %v", got)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProgramStore — хранилище реестра программ, которые меняются через админский API
+type ProgramStore interface {
+	// Load возвращает все программы, включая выключенные
+	Load() ([]Program, error)
+	// Save заменяет программы целиком
+	Save(programs []Program) error
+}
+
+// programsDocument — формат YAML-файла программ, тот же, что у programs_file в конфиге
+type programsDocument struct {
+	Programs []Program `yaml:"programs"`
+}
+
+// FileProgramStore хранит программы в YAML-файле. Файл перезаписывается атомарно,
+// поэтому его может одновременно читать перезагрузка конфига.
+type FileProgramStore struct {
+	path string
+}
+
+// NewFileProgramStore создает хранилище программ в файле path
+func NewFileProgramStore(path string) *FileProgramStore {
+	return &FileProgramStore{path: path}
+}
+
+// Load читает программы из файла
+func (s *FileProgramStore) Load() ([]Program, error) {
+	file, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading programs file: %w", err)
+	}
+
+	var doc programsDocument
+	if err := yaml.Unmarshal(file, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing programs file: %v", err)
+	}
+	return doc.Programs, nil
+}
+
+// Save записывает программы во временный файл рядом и подменяет им прежний
+func (s *FileProgramStore) Save(programs []Program) error {
+	data, err := yaml.Marshal(programsDocument{Programs: programs})
+	if err != nil {
+		return fmt.Errorf("save programs: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("save programs: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save programs: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save programs: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save programs: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("save programs: %v", err)
+	}
+	return nil
+}
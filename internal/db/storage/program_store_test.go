@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileProgramStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "programs.yml")
+	store := NewFileProgramStore(path)
+
+	if _, err := store.Load(); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected not exist error for a missing file, got %v", err)
+	}
+
+	programs := append(DefaultPrograms(), Program{
+		Code:        "family",
+		Name:        "Семейная ипотека",
+		AnnualRate:  600,
+		MaxMonths:   360,
+		RatePeriods: []RatePeriod{{Months: 12, AnnualRate: 400}},
+		Inactive:    true,
+	})
+	if err := store.Save(programs); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, programs) {
+		t.Errorf("Expected %v, got %v", programs, got)
+	}
+
+	// Временные файлы не остаются рядом с файлом программ
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected only the programs file, got %d entries", len(entries))
+	}
+}
+
+func TestFileAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := OpenFileAuditLog(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	before := DefaultPrograms()[0]
+	after := before
+	after.AnnualRate = 750
+	at := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	entries := []AuditEntry{
+		{Time: at, Actor: "pm", Action: AuditCreate, Code: "family", After: &Program{Code: "family", AnnualRate: 600}},
+		{Time: at, Actor: "pm", Action: AuditSetRate, Code: before.Code, Before: &before, After: &after},
+	}
+	for _, e := range entries {
+		if err := audit.Record(e); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := audit.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Повторное открытие дописывает в конец
+	audit, err = OpenFileAuditLog(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	entries = append(entries, AuditEntry{Time: at, Actor: "ops", Action: AuditDeactivate, Code: before.Code, Before: &after, After: &after})
+	if err := audit.Record(entries[2]); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	audit.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != len(entries) {
+		t.Fatalf("Expected %d lines, got %d", len(entries), len(lines))
+	}
+	for i, line := range lines {
+		var got AuditEntry
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("Line %d: unexpected error: %v", i, err)
+		}
+		if !reflect.DeepEqual(got, entries[i]) {
+			t.Errorf("Line %d: expected %+v, got %+v", i, entries[i], got)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"github.com/Dorji/sberInterview/internal/money"
+)
+
+// Коды программ, на которые отображаются старые bool-поля LoanProgram
+const (
+	SalaryProgram   = "salary"
+	MilitaryProgram = "military"
+	BaseProgram     = "base"
+)
+
+// Program — запись реестра программ кредитования
+type Program struct {
+	Code              string            `yaml:"code"`                   // код для выбора программы в запросе
+	Name              string            `yaml:"name"`                   // отображаемое название
+	AnnualRate        money.BasisPoints `yaml:"rate_bps"`               // годовая ставка, 850 = 8.5%
+	MinInitialPayment float64           `yaml:"min_initial_payment"`    // минимальная доля первоначального взноса
+	MinMonths         int64             `yaml:"min_months,omitempty"`   // минимальный срок, 0 — без ограничения
+	MaxMonths         int64             `yaml:"max_months,omitempty"`   // максимальный срок, 0 — без ограничения
+	MinLoanSum        int64             `yaml:"min_loan_sum,omitempty"` // минимальная сумма кредита, 0 — без ограничения
+	MaxLoanSum        int64             `yaml:"max_loan_sum,omitempty"` // максимальная сумма кредита, 0 — без ограничения
+	RatePeriods       []RatePeriod      `yaml:"rate_periods,omitempty"` // ступенчатая ставка в начале срока, дальше — AnnualRate
+	RateType          string            `yaml:"rate_type,omitempty"`    // fixed (по умолчанию) или key_rate
+	Spread            money.BasisPoints `yaml:"spread_bps,omitempty"`   // надбавка к ключевой ставке для rate_type: key_rate
+	Inactive          bool              `yaml:"inactive,omitempty"`     // выключена: не выбирается в расчетах, но остается в реестре
+}
+
+// Типы ставки программы
+const (
+	RateFixed   = "fixed"    // постоянная ставка AnnualRate
+	RateKeyRate = "key_rate" // плавающая: ключевая ставка Банка России плюс Spread на дату выдачи
+)
+
+// RatePeriod — период ступенчатой ставки: Months месяцев по ставке AnnualRate.
+// Нулевой срок допустим только у последнего периода и означает ставку до конца кредита.
+type RatePeriod struct {
+	Months     int64             `yaml:"months"`
+	AnnualRate money.BasisPoints `yaml:"rate_bps"`
+}
+
+// DefaultPrograms возвращает программы из задания. Ими заполняется реестр, если программы
+// не заданы в конфиге, и пустое хранилище программ, которые дальше меняются через админский API.
+func DefaultPrograms() []Program {
+	return []Program{
+		{Code: SalaryProgram, Name: "Программа для корпоративных клиентов", AnnualRate: 800, MinInitialPayment: 0.20},
+		{Code: MilitaryProgram, Name: "Военная ипотека", AnnualRate: 900, MinInitialPayment: 0.20},
+		{Code: BaseProgram, Name: "Базовая программа", AnnualRate: 1000, MinInitialPayment: 0.20},
+	}
+}
+
+// ProgramRegistry — неизменяемый реестр программ с версиями таблицы ставок, безопасен для конкурентного чтения
+type ProgramRegistry struct {
+	programs []Program
+	byCode   map[string]Program
+	versions []RateVersion
+}
+
+// NewProgramRegistry проверяет записи и версии ставок и строит реестр
+func NewProgramRegistry(programs []Program, versions ...RateVersion) (*ProgramRegistry, error) {
+	if len(programs) == 0 {
+		return nil, fmt.Errorf("program registry: no programs")
+	}
+	reg := &ProgramRegistry{
+		programs: make([]Program, 0, len(programs)),
+		byCode:   make(map[string]Program, len(programs)),
+	}
+	for _, p := range programs {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := reg.byCode[p.Code]; ok {
+			return nil, fmt.Errorf("program registry: duplicate program %q", p.Code)
+		}
+		reg.byCode[p.Code] = p
+		reg.programs = append(reg.programs, p)
+	}
+	if len(reg.List()) == 0 {
+		return nil, fmt.Errorf("program registry: no active programs")
+	}
+	var err error
+	if reg.versions, err = sortRateVersions(versions, reg.byCode); err != nil {
+		return nil, err
+	}
+	return reg, nil
+}
+
+// DefaultProgramRegistry возвращает реестр из DefaultPrograms
+func DefaultProgramRegistry() *ProgramRegistry {
+	reg, err := NewProgramRegistry(DefaultPrograms())
+	if err != nil {
+		panic(err)
+	}
+	return reg
+}
+
+func (p Program) validate() error {
+	switch {
+	case p.Code == "":
+		return fmt.Errorf("program registry: empty program code")
+	case p.RateType != "" && p.RateType != RateFixed && p.RateType != RateKeyRate:
+		return fmt.Errorf("program registry: %s: unknown rate type %q", p.Code, p.RateType)
+	case !p.Floating() && p.AnnualRate <= 0:
+		return fmt.Errorf("program registry: %s: rate should be positive", p.Code)
+	case p.MinInitialPayment < 0 || p.MinInitialPayment >= 1:
+		return fmt.Errorf("program registry: %s: min initial payment should be in [0, 1)", p.Code)
+	case p.MinMonths < 0 || p.MaxMonths < 0 || (p.MaxMonths > 0 && p.MinMonths > p.MaxMonths):
+		return fmt.Errorf("program registry: %s: invalid months limits", p.Code)
+	case p.MinLoanSum < 0 || p.MaxLoanSum < 0 || (p.MaxLoanSum > 0 && p.MinLoanSum > p.MaxLoanSum):
+		return fmt.Errorf("program registry: %s: invalid loan sum limits", p.Code)
+	}
+	for i, period := range p.RatePeriods {
+		switch {
+		case period.AnnualRate <= 0:
+			return fmt.Errorf("program registry: %s: rate period %d: rate should be positive", p.Code, i)
+		case period.Months < 0 || (period.Months == 0 && i != len(p.RatePeriods)-1):
+			return fmt.Errorf("program registry: %s: rate period %d: months should be positive", p.Code, i)
+		}
+	}
+	return nil
+}
+
+// Floating сообщает, привязана ли ставка программы к ключевой ставке.
+// Ставку такой программы на дату выдачи подставляет в AnnualRate сервис расчета.
+func (p Program) Floating() bool {
+	return p.RateType == RateKeyRate
+}
+
+// InitialRate возвращает ставку первого месяца: первого периода ступенчатой ставки или годовую
+func (p Program) InitialRate() money.BasisPoints {
+	if len(p.RatePeriods) > 0 {
+		return p.RatePeriods[0].AnnualRate
+	}
+	return p.AnnualRate
+}
+
+// Get ищет программу по коду
+func (r *ProgramRegistry) Get(code string) (Program, bool) {
+	p, ok := r.byCode[code]
+	return p, ok
+}
+
+// List возвращает программы, доступные для расчета, в порядке объявления
+func (r *ProgramRegistry) List() []Program {
+	res := make([]Program, 0, len(r.programs))
+	for _, p := range r.programs {
+		if !p.Inactive {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// All возвращает все программы в порядке объявления, включая выключенные
+func (r *ProgramRegistry) All() []Program {
+	res := make([]Program, len(r.programs))
+	copy(res, r.programs)
+	return res
+}
+
+// Resolve выбирает программу по коду или по старым bool-полям запроса
+func (r *ProgramRegistry) Resolve(req *entities.LoanProgram) (Program, error) {
+	if req == nil {
+		return Program{}, reasonError(ReasonProgramNotSelected, nil, "choose program")
+	}
+
+	code := ProgramCode(req)
+	if code == "" {
+		if req.Code == "" && !req.Salary && !req.Military && !req.Base {
+			return Program{}, reasonError(ReasonProgramNotSelected, nil, "choose program")
+		}
+		return Program{}, reasonError(ReasonProgramAmbiguous, nil, "choose only 1 program")
+	}
+	p, ok := r.Get(code)
+	if !ok {
+		return Program{}, reasonError(ReasonProgramUnknown, map[string]string{"program": code}, "unknown program %q", code)
+	}
+	if p.Inactive {
+		return Program{}, reasonError(ReasonProgramInactive, map[string]string{"program": code}, "program %q is not available", code)
+	}
+	return p, nil
+}
+
+// ProgramCode возвращает код программы из запроса: явный код или код старого bool-поля.
+// Для неоднозначного или пустого выбора возвращается пустая строка.
+func ProgramCode(req *entities.LoanProgram) string {
+	if req == nil {
+		return ""
+	}
+	var codes []string
+	if req.Code != "" {
+		codes = append(codes, req.Code)
+	}
+	if req.Salary {
+		codes = append(codes, SalaryProgram)
+	}
+	if req.Military {
+		codes = append(codes, MilitaryProgram)
+	}
+	if req.Base {
+		codes = append(codes, BaseProgram)
+	}
+	if len(codes) == 0 {
+		return ""
+	}
+	for _, code := range codes[1:] {
+		if code != codes[0] {
+			return ""
+		}
+	}
+	return codes[0]
+}
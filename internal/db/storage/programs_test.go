@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+)
+
+func TestProgramRegistryResolve(t *testing.T) {
+	reg, err := NewProgramRegistry(append(DefaultPrograms(), Program{
+		Code:              "family",
+		Name:              "Семейная ипотека",
+		AnnualRate:        600,
+		MinInitialPayment: 0.20,
+	}, Program{
+		Code:       "closed",
+		Name:       "Закрытая программа",
+		AnnualRate: 700,
+		Inactive:   true,
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if list, all := reg.List(), reg.All(); len(list) != 4 || len(all) != 5 {
+		t.Errorf("Expected 4 active of 5 programs, got %d of %d", len(list), len(all))
+	}
+
+	tests := []struct {
+		name     string
+		program  *entities.LoanProgram
+		wantCode string
+		errMsg   string
+	}{
+		{"Code", &entities.LoanProgram{Code: "family"}, "family", ""},
+		{"Legacy bool", &entities.LoanProgram{Military: true}, MilitaryProgram, ""},
+		{"Code matches legacy bool", &entities.LoanProgram{Code: SalaryProgram, Salary: true}, SalaryProgram, ""},
+		{"Code conflicts with legacy bool", &entities.LoanProgram{Code: "family", Base: true}, "", "rpc error: code = InvalidArgument desc = choose only 1 program"},
+		{"Unknown code", &entities.LoanProgram{Code: "it"}, "", `rpc error: code = InvalidArgument desc = unknown program "it"`},
+		{"Inactive program", &entities.LoanProgram{Code: "closed"}, "", `rpc error: code = InvalidArgument desc = program "closed" is not available`},
+		{"Empty", &entities.LoanProgram{}, "", "rpc error: code = InvalidArgument desc = choose program"},
+		{"Nil", nil, "", "rpc error: code = InvalidArgument desc = choose program"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reg.Resolve(tt.program)
+			if tt.errMsg != "" {
+				if err == nil || err.Error() != tt.errMsg {
+					t.Errorf("Expected error '%s', got '%v'", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got.Code != tt.wantCode {
+				t.Errorf("Expected program %s, got %s", tt.wantCode, got.Code)
+			}
+		})
+	}
+}
+
+func TestNewProgramRegistryValidation(t *testing.T) {
+	valid := Program{Code: "base", AnnualRate: 1000, MinInitialPayment: 0.2}
+
+	tests := []struct {
+		name     string
+		programs []Program
+	}{
+		{"Empty registry", nil},
+		{"Empty code", []Program{{AnnualRate: 1000}}},
+		{"Zero rate", []Program{{Code: "base"}}},
+		{"Initial payment share above 1", []Program{{Code: "base", AnnualRate: 1000, MinInitialPayment: 1.5}}},
+		{"Months limits swapped", []Program{{Code: "base", AnnualRate: 1000, MinMonths: 240, MaxMonths: 12}}},
+		{"Loan sum limits swapped", []Program{{Code: "base", AnnualRate: 1000, MinLoanSum: 10, MaxLoanSum: 5}}},
+		{"Rate period without rate", []Program{{Code: "base", AnnualRate: 1000, RatePeriods: []RatePeriod{{Months: 24}}}}},
+		{"Open rate period is not last", []Program{{Code: "base", AnnualRate: 1000, RatePeriods: []RatePeriod{{AnnualRate: 600}, {Months: 12, AnnualRate: 800}}}}},
+		{"Unknown rate type", []Program{{Code: "base", AnnualRate: 1000, RateType: "libor"}}},
+		{"Duplicate code", []Program{valid, valid}},
+		{"No active programs", []Program{{Code: "base", AnnualRate: 1000, Inactive: true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewProgramRegistry(tt.programs); err == nil {
+				t.Error("Expected validation error, got nil")
+			}
+		})
+	}
+
+	if _, err := NewProgramRegistry([]Program{{Code: "floating", RateType: RateKeyRate, Spread: 200}}); err != nil {
+		t.Errorf("Floating program without a fixed rate: unexpected error %v", err)
+	}
+
+	reg, err := NewProgramRegistry([]Program{valid})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if list := reg.List(); len(list) != 1 || list[0].Code != "base" {
+		t.Errorf("Unexpected programs %v", list)
+	}
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/Dorji/sberInterview/internal/money"
+)
+
+// ProgramRate — ставка программы: все поля Program, от которых зависит процентная ставка
+type ProgramRate struct {
+	AnnualRate  money.BasisPoints `yaml:"rate_bps"`
+	RatePeriods []RatePeriod      `yaml:"rate_periods"`
+	RateType    string            `yaml:"rate_type"`
+	Spread      money.BasisPoints `yaml:"spread_bps"`
+}
+
+// RateVersion — версия таблицы ставок, действующая с EffectiveFrom включительно до EffectiveTo
+// не включительно. Пустой EffectiveTo заменяется началом следующей версии, у последней — без конца.
+// Версия задает ставки только записанных в Rates программ, их смена в реестре на версию не влияет.
+// Остальные программы в период версии считаются по ставке из реестра, как вне версий.
+type RateVersion struct {
+	ID            string                 `yaml:"id"`
+	EffectiveFrom time.Time              `yaml:"effective_from"`
+	EffectiveTo   time.Time              `yaml:"effective_to"`
+	Rates         map[string]ProgramRate `yaml:"rates"` // по коду программы
+}
+
+// RateChange — изменение ставки программы между версиями
+type RateChange struct {
+	Code     string
+	From, To ProgramRate
+}
+
+// Rate возвращает ставку программы
+func (p Program) Rate() ProgramRate {
+	return ProgramRate{AnnualRate: p.AnnualRate, RatePeriods: p.RatePeriods, RateType: p.RateType, Spread: p.Spread}
+}
+
+// WithRate возвращает программу с другой ставкой
+func (p Program) WithRate(r ProgramRate) Program {
+	p.AnnualRate, p.RatePeriods, p.RateType, p.Spread = r.AnnualRate, r.RatePeriods, r.RateType, r.Spread
+	return p
+}
+
+// Equal сравнивает ставки вместе с периодами, пустой тип ставки равен fixed
+func (r ProgramRate) Equal(other ProgramRate) bool {
+	return r.AnnualRate == other.AnnualRate && r.Spread == other.Spread &&
+		(r.RateType == RateKeyRate) == (other.RateType == RateKeyRate) &&
+		slices.Equal(r.RatePeriods, other.RatePeriods)
+}
+
+// sortRateVersions проверяет версии, упорядочивает их по началу действия
+// и проставляет пустые EffectiveTo. Версии не должны пересекаться.
+func sortRateVersions(versions []RateVersion, programs map[string]Program) ([]RateVersion, error) {
+	res := make([]RateVersion, len(versions))
+	copy(res, versions)
+	sort.SliceStable(res, func(i, j int) bool { return res[i].EffectiveFrom.Before(res[j].EffectiveFrom) })
+
+	ids := make(map[string]bool, len(res))
+	for i := range res {
+		v := &res[i]
+		if v.ID == "" {
+			return nil, fmt.Errorf("rate versions: empty version id")
+		}
+		if ids[v.ID] {
+			return nil, fmt.Errorf("rate versions: duplicate version %q", v.ID)
+		}
+		ids[v.ID] = true
+
+		if v.EffectiveTo.IsZero() && i+1 < len(res) {
+			v.EffectiveTo = res[i+1].EffectiveFrom
+		}
+		if !v.EffectiveTo.IsZero() && !v.EffectiveTo.After(v.EffectiveFrom) {
+			return nil, fmt.Errorf("rate versions: %s: effective_to should be after effective_from", v.ID)
+		}
+		if i+1 < len(res) && (v.EffectiveTo.IsZero() || v.EffectiveTo.After(res[i+1].EffectiveFrom)) {
+			return nil, fmt.Errorf("rate versions: %s overlaps %s", v.ID, res[i+1].ID)
+		}
+		for code, rate := range v.Rates {
+			p, ok := programs[code]
+			if !ok {
+				return nil, fmt.Errorf("rate versions: %s: unknown program %q", v.ID, code)
+			}
+			if err := p.WithRate(rate).validate(); err != nil {
+				return nil, fmt.Errorf("rate versions: %s: %v", v.ID, err)
+			}
+		}
+		v.Rates = maps.Clone(v.Rates)
+	}
+	return res, nil
+}
+
+// Versions возвращает версии таблицы ставок по возрастанию начала действия
+func (r *ProgramRegistry) Versions() []RateVersion {
+	res := make([]RateVersion, len(r.versions))
+	copy(res, r.versions)
+	return res
+}
+
+// Version ищет версию таблицы ставок по id
+func (r *ProgramRegistry) Version(id string) (RateVersion, bool) {
+	for _, v := range r.versions {
+		if v.ID == id {
+			return v, true
+		}
+	}
+	return RateVersion{}, false
+}
+
+// VersionAt возвращает версию, действующую в момент at
+func (r *ProgramRegistry) VersionAt(at time.Time) (RateVersion, bool) {
+	for _, v := range r.versions {
+		if !at.Before(v.EffectiveFrom) && (v.EffectiveTo.IsZero() || at.Before(v.EffectiveTo)) {
+			return v, true
+		}
+	}
+	return RateVersion{}, false
+}
+
+// ProgramAt возвращает программу со ставкой версии, действующей в момент at, и id этой версии.
+// Вне версий и в версии, где ставка программы не записана, программа считается по своей ставке
+// из реестра, а id пустой: расчет не ссылается на версию, которая его ставку не задавала.
+func (r *ProgramRegistry) ProgramAt(p Program, at time.Time) (Program, string) {
+	v, ok := r.VersionAt(at)
+	if !ok {
+		return p, ""
+	}
+	rate, ok := v.Rates[p.Code]
+	if !ok {
+		return p, ""
+	}
+	return p.WithRate(rate), v.ID
+}
+
+// VersionRates возвращает записанные в версии ставки программ по коду программы
+func (r *ProgramRegistry) VersionRates(v RateVersion) map[string]ProgramRate {
+	return maps.Clone(v.Rates)
+}
+
+// DiffVersions сравнивает ставки двух версий и возвращает изменившиеся программы в порядке реестра.
+// Программы, которых нет в одной из версий, не сравниваются.
+func (r *ProgramRegistry) DiffVersions(from, to RateVersion) []RateChange {
+	var res []RateChange
+	for _, p := range r.programs {
+		b, okFrom := from.Rates[p.Code]
+		a, okTo := to.Rates[p.Code]
+		if okFrom && okTo && !b.Equal(a) {
+			res = append(res, RateChange{Code: p.Code, From: b, To: a})
+		}
+	}
+	return res
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateVersions(t *testing.T) {
+	jan := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	jun := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	versions := []RateVersion{
+		// Порядок в конфиге не важен, версии сортируются по началу действия
+		{ID: "2024-06", EffectiveFrom: jun, Rates: map[string]ProgramRate{SalaryProgram: {AnnualRate: 750}}},
+		{ID: "2024-01", EffectiveFrom: jan, Rates: map[string]ProgramRate{SalaryProgram: {AnnualRate: 800}}},
+	}
+	reg, err := NewProgramRegistry(DefaultPrograms(), versions...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	list := reg.Versions()
+	if len(list) != 2 || list[0].ID != "2024-01" || !list[0].EffectiveTo.Equal(jun) || !list[1].EffectiveTo.IsZero() {
+		t.Fatalf("Unexpected versions %+v", list)
+	}
+
+	salary, _ := reg.Get(SalaryProgram)
+	base, _ := reg.Get(BaseProgram)
+	tests := []struct {
+		name        string
+		program     Program
+		at          time.Time
+		wantRate    int64
+		wantVersion string
+	}{
+		{"Before the first version", salary, jan.Add(-time.Nanosecond), int64(800), ""},
+		{"First day of a version", salary, jan, 800, "2024-01"},
+		{"Last moment of a version", salary, jun.Add(-time.Nanosecond), 800, "2024-01"},
+		{"Open last version", salary, jun.AddDate(5, 0, 0), 750, "2024-06"},
+		{"Program without a version rate", base, jun, int64(1000), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, version := reg.ProgramAt(tt.program, tt.at)
+			if int64(got.AnnualRate) != tt.wantRate || version != tt.wantVersion {
+				t.Errorf("Expected %d from %q, got %d from %q", tt.wantRate, tt.wantVersion, got.AnnualRate, version)
+			}
+		})
+	}
+
+	changes := reg.DiffVersions(list[0], list[1])
+	if len(changes) != 1 || changes[0].Code != SalaryProgram || changes[0].From.AnnualRate != 800 || changes[0].To.AnnualRate != 750 {
+		t.Errorf("Unexpected changes %+v", changes)
+	}
+	if changes := reg.DiffVersions(list[1], list[1]); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", changes)
+	}
+
+	// Версия хранит только записанные в ней ставки, и при сборке реестра заново они не меняются
+	if rates := reg.VersionRates(list[0]); len(rates) != 1 || rates[SalaryProgram].AnnualRate != 800 {
+		t.Errorf("Expected only the salary rate, got %+v", rates)
+	}
+	programs := DefaultPrograms()
+	for i := range programs {
+		if programs[i].Code == SalaryProgram {
+			programs[i].AnnualRate = 900
+		}
+	}
+	rebuilt, err := NewProgramRegistry(programs, versions...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	salary, _ = rebuilt.Get(SalaryProgram)
+	if got, _ := rebuilt.ProgramAt(salary, jan); got.AnnualRate != 800 {
+		t.Errorf("Expected 800 in 2024-01, got %d", got.AnnualRate)
+	}
+	if got, _ := rebuilt.ProgramAt(salary, jan.Add(-time.Nanosecond)); got.AnnualRate != 900 {
+		t.Errorf("Expected the registry rate 900 outside versions, got %d", got.AnnualRate)
+	}
+}
+
+func TestRateVersionsValidation(t *testing.T) {
+	jan := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	jun := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		versions []RateVersion
+	}{
+		{"Empty id", []RateVersion{{EffectiveFrom: jan}}},
+		{"Duplicate id", []RateVersion{{ID: "v", EffectiveFrom: jan}, {ID: "v", EffectiveFrom: jun}}},
+		{"Overlap", []RateVersion{{ID: "a", EffectiveFrom: jan, EffectiveTo: jun.AddDate(0, 1, 0)}, {ID: "b", EffectiveFrom: jun}}},
+		{"Same start", []RateVersion{{ID: "a", EffectiveFrom: jan}, {ID: "b", EffectiveFrom: jan}}},
+		{"End before start", []RateVersion{{ID: "a", EffectiveFrom: jun, EffectiveTo: jan}}},
+		{"Unknown program", []RateVersion{{ID: "a", EffectiveFrom: jan, Rates: map[string]ProgramRate{"it": {AnnualRate: 500}}}}},
+		{"Invalid rate", []RateVersion{{ID: "a", EffectiveFrom: jan, Rates: map[string]ProgramRate{BaseProgram: {}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewProgramRegistry(DefaultPrograms(), tt.versions...); err == nil {
+				t.Error("Expected validation error, got nil")
+			}
+		})
+	}
+
+	// Промо-версия с явным концом оставляет промежуток, в котором действуют ставки реестра
+	reg, err := NewProgramRegistry(DefaultPrograms(), RateVersion{ID: "promo", EffectiveFrom: jan, EffectiveTo: jan.AddDate(0, 1, 0)}, RateVersion{ID: "2024-06", EffectiveFrom: jun})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := reg.VersionAt(jan.AddDate(0, 2, 0)); ok {
+		t.Error("Expected no version between promo and 2024-06")
+	}
+}
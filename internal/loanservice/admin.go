@@ -0,0 +1,279 @@
+package loanservice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"github.com/Dorji/sberInterview/api/protos/services"
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+	"github.com/Dorji/sberInterview/internal/loanservice/interceptors"
+	"github.com/Dorji/sberInterview/internal/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Машиночитаемые причины ошибок в запросах админского API
+const (
+	ReasonProgramCodeInvalid = "PROGRAM_CODE_INVALID"
+	ReasonProgramNameEmpty   = "PROGRAM_NAME_EMPTY"
+	ReasonProgramInvalid     = "PROGRAM_INVALID"
+)
+
+// governingVersion возвращает версию ставок, действующую сейчас или позже, в которой записана ставка программы
+func governingVersion(programs *db.ProgramRegistry, code string, now time.Time) (db.RateVersion, bool) {
+	for _, v := range programs.Versions() {
+		if _, ok := v.Rates[code]; ok && (v.EffectiveTo.IsZero() || v.EffectiveTo.After(now)) {
+			return v, true
+		}
+	}
+	return db.RateVersion{}, false
+}
+
+// programCodePattern — допустимый код программы: он попадает в пути админских ручек
+var programCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// LoanAdminServer меняет реестр программ сервиса расчета: сохраняет программы в хранилище,
+// пишет каждое изменение в журнал и подменяет реестр без перезапуска
+type LoanAdminServer struct {
+	services.UnimplementedLoanAdminServiceServer
+
+	loans *LoanServiceServer
+	store db.ProgramStore
+	audit db.AuditLog
+
+	// mu — изменения выполняются по одному, чтобы не потерять соседнее. Та же блокировка
+	// у перезагрузки конфига: она не читает файл программ, пока изменение не записано или не откачено
+	mu sync.Locker
+}
+
+// NewLoanAdminService создает админский сервис поверх сервиса расчета, хранилища программ и журнала.
+// lock — блокировка, общая с перезагрузкой конфига, nil — своя.
+func NewLoanAdminService(loans *LoanServiceServer, store db.ProgramStore, audit db.AuditLog, lock sync.Locker) (*LoanAdminServer, error) {
+	if loans == nil || store == nil || audit == nil {
+		return nil, fmt.Errorf("NewLoanAdminService:nil dependency")
+	}
+	if lock == nil {
+		lock = &sync.Mutex{}
+	}
+	return &LoanAdminServer{loans: loans, store: store, audit: audit, mu: lock}, nil
+}
+
+// ListPrograms возвращает программы реестра, выключенные — по запросу
+func (a *LoanAdminServer) ListPrograms(ctx context.Context, req *entities.ListProgramsRequest) (*entities.ProgramList, error) {
+	registry := a.loans.rates.Load().programs
+	programs := registry.List()
+	if req.IncludeInactive {
+		programs = registry.All()
+	}
+	res := &entities.ProgramList{Programs: make([]*entities.Program, 0, len(programs))}
+	for _, p := range programs {
+		res.Programs = append(res.Programs, programProto(p))
+	}
+	return res, nil
+}
+
+// CreateProgram добавляет программу в конец реестра
+func (a *LoanAdminServer) CreateProgram(ctx context.Context, req *entities.Program) (*entities.Program, error) {
+	if err := validateProgram(req.Code, req); err != nil {
+		return nil, err
+	}
+	return a.change(ctx, db.AuditCreate, req.Code, func(before *db.Program) (db.Program, error) {
+		if before != nil {
+			return db.Program{}, status.Errorf(codes.AlreadyExists, "program %q already exists", req.Code)
+		}
+		return programFromProto(req), nil
+	})
+}
+
+// UpdateProgram заменяет программу целиком, код берется из пути запроса
+func (a *LoanAdminServer) UpdateProgram(ctx context.Context, req *entities.UpdateProgramRequest) (*entities.Program, error) {
+	if req.Program == nil {
+		var v violations
+		v.add("program", ReasonProgramInvalid, "program is required")
+		return nil, v.err()
+	}
+	if err := validateProgram(req.Code, req.Program); err != nil {
+		return nil, err
+	}
+	return a.change(ctx, db.AuditUpdate, req.Code, func(before *db.Program) (db.Program, error) {
+		if before == nil {
+			return db.Program{}, status.Errorf(codes.NotFound, "program %q not found", req.Code)
+		}
+		p := programFromProto(req.Program)
+		p.Code = req.Code
+		return p, nil
+	})
+}
+
+// SetProgramRate меняет только ставку программы. Если ставка программы записана в действующей
+// или будущей версии таблицы ставок, изменение отклоняется с FailedPrecondition: такую ставку
+// меняют новой версией в rate_versions. Ставки программ, не записанных в версиях, меняются здесь.
+func (a *LoanAdminServer) SetProgramRate(ctx context.Context, req *entities.ProgramRate) (*entities.Program, error) {
+	if err := validateProgram(req.Code, nil); err != nil {
+		return nil, err
+	}
+	return a.change(ctx, db.AuditSetRate, req.Code, func(before *db.Program) (db.Program, error) {
+		if before == nil {
+			return db.Program{}, status.Errorf(codes.NotFound, "program %q not found", req.Code)
+		}
+		return before.WithRate(programRateFromProto(req)), nil
+	})
+}
+
+// DeactivateProgram выключает программу: новые расчеты по ней не принимаются,
+// а сохраненные расчеты и версии ставок остаются как есть
+func (a *LoanAdminServer) DeactivateProgram(ctx context.Context, req *entities.ProgramCode) (*entities.Program, error) {
+	if err := validateProgram(req.Code, nil); err != nil {
+		return nil, err
+	}
+	return a.change(ctx, db.AuditDeactivate, req.Code, func(before *db.Program) (db.Program, error) {
+		if before == nil {
+			return db.Program{}, status.Errorf(codes.NotFound, "program %q not found", req.Code)
+		}
+		p := *before
+		p.Inactive = true
+		return p, nil
+	})
+}
+
+// change применяет изменение программы code: проверяет новый реестр, сохраняет его в хранилище,
+// пишет запись в журнал и подменяет реестр сервиса расчета. Без записи в журнале изменение
+// откатывается, повторное изменение без разницы ничего не пишет.
+func (a *LoanAdminServer) change(ctx context.Context, action, code string, mutate func(before *db.Program) (db.Program, error)) (*entities.Program, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	current := a.loans.rates.Load()
+	programs := current.programs.All()
+	i := slices.IndexFunc(programs, func(p db.Program) bool { return p.Code == code })
+	var before *db.Program
+	if i >= 0 {
+		p := programs[i]
+		before = &p
+	}
+	after, err := mutate(before)
+	if err != nil {
+		return nil, err
+	}
+	if before != nil && reflect.DeepEqual(*before, after) {
+		return programProto(after), nil
+	}
+	// Ставку, записанную в версии таблицы ставок, смена ставки в реестре не меняет:
+	// расчеты шли бы по-прежнему, поэтому такое изменение отклоняется
+	if before != nil && !before.Rate().Equal(after.Rate()) {
+		if v, ok := governingVersion(current.programs, code, a.loans.now()); ok {
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"rate of program %q is set by rate version %q, change it in rate_versions", code, v.ID)
+		}
+	}
+
+	next := slices.Clone(programs)
+	if i >= 0 {
+		next[i] = after
+	} else {
+		next = append(next, after)
+	}
+	registry, err := db.NewProgramRegistry(next, current.programs.Versions()...)
+	if err != nil {
+		var v violations
+		v.add("program", ReasonProgramInvalid, "%v", err)
+		return nil, v.err()
+	}
+
+	if err := a.store.Save(next); err != nil {
+		return nil, status.Errorf(codes.Internal, "save programs: %v", err)
+	}
+	entry := db.AuditEntry{
+		Time:   a.loans.now(),
+		Actor:  interceptors.Actor(ctx),
+		Action: action,
+		Code:   code,
+		Before: before,
+		After:  &after,
+	}
+	if err := a.audit.Record(entry); err != nil {
+		if rollbackErr := a.store.Save(programs); rollbackErr != nil {
+			log.Printf("Program %s: rollback after audit error failed: %v", code, rollbackErr)
+		}
+		return nil, status.Errorf(codes.Internal, "audit: %v", err)
+	}
+	if err := a.loans.Reload(registry, current.keyRates); err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	log.Printf("Program %s: %s by %s", code, action, entry.Actor)
+	return programProto(after), nil
+}
+
+// validateProgram проверяет код из пути и поля программы, которые не проверяет реестр
+func validateProgram(code string, p *entities.Program) error {
+	var v violations
+	if !programCodePattern.MatchString(code) {
+		v.add("code", ReasonProgramCodeInvalid, "program code should match %s", programCodePattern)
+	}
+	if p != nil && p.Name == "" {
+		v.add("name", ReasonProgramNameEmpty, "program name is required")
+	}
+	return v.err()
+}
+
+// programFromProto переводит программу из API в запись реестра, тип fixed хранится пустым
+func programFromProto(p *entities.Program) db.Program {
+	res := db.Program{
+		Code:              p.Code,
+		Name:              p.Name,
+		MinInitialPayment: p.MinInitialPayment,
+		MinMonths:         p.MinMonths,
+		MaxMonths:         p.MaxMonths,
+		MinLoanSum:        p.MinLoanSum,
+		MaxLoanSum:        p.MaxLoanSum,
+		Inactive:          p.Inactive,
+	}
+	return res.WithRate(programRateFromProto(&entities.ProgramRate{
+		RateBps:     p.RateBps,
+		RatePeriods: p.RatePeriods,
+		RateType:    p.RateType,
+		SpreadBps:   p.SpreadBps,
+	}))
+}
+
+// programRateFromProto переводит ставку из API в ставку программы
+func programRateFromProto(r *entities.ProgramRate) db.ProgramRate {
+	res := db.ProgramRate{
+		AnnualRate: money.BasisPoints(r.RateBps),
+		RateType:   r.RateType,
+		Spread:     money.BasisPoints(r.SpreadBps),
+	}
+	if res.RateType == db.RateFixed {
+		res.RateType = ""
+	}
+	for _, p := range r.RatePeriods {
+		res.RatePeriods = append(res.RatePeriods, db.RatePeriod{Months: p.Months, AnnualRate: money.BasisPoints(p.RateBps)})
+	}
+	return res
+}
+
+// programProto переводит запись реестра в программу API
+func programProto(p db.Program) *entities.Program {
+	rate := programRateProto(p.Code, p.Rate())
+	return &entities.Program{
+		Code:              p.Code,
+		Name:              p.Name,
+		RateBps:           rate.RateBps,
+		RatePeriods:       rate.RatePeriods,
+		RateType:          rate.RateType,
+		SpreadBps:         rate.SpreadBps,
+		MinInitialPayment: p.MinInitialPayment,
+		MinMonths:         p.MinMonths,
+		MaxMonths:         p.MaxMonths,
+		MinLoanSum:        p.MinLoanSum,
+		MaxLoanSum:        p.MaxLoanSum,
+		Inactive:          p.Inactive,
+	}
+}
@@ -0,0 +1,259 @@
+package loanservice
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+	"github.com/Dorji/sberInterview/internal/money"
+)
+
+// Машиночитаемые причины ошибок подбора кредита по бюджету
+const (
+	ReasonMonthlyPaymentNotPositive = "MONTHLY_PAYMENT_NOT_POSITIVE"
+	ReasonMonthlyPaymentTooLarge    = "MONTHLY_PAYMENT_TOO_LARGE"
+	ReasonLoanNotAffordable         = "LOAN_NOT_AFFORDABLE"
+)
+
+// Affordability подбирает наибольшую сумму кредита, платеж по которой укладывается в бюджет,
+// с учетом минимальной доли взноса и ограничений программы. Стоимость объекта — кредит плюс взнос.
+// Результат в кеш не сохраняется.
+func (ls *LoanServiceServer) Affordability(ctx context.Context, req *entities.AffordabilityRequest) (*entities.AffordabilityResult, error) {
+	ctx = ls.pin(ctx)
+	var v violations
+	if req.MonthlyPayment <= 0 {
+		v.add("monthly_payment", ReasonMonthlyPaymentNotPositive, "monthly payment should be positive")
+	} else if req.MonthlyPayment > maxAmount {
+		v.add("monthly_payment", ReasonMonthlyPaymentTooLarge, "monthly payment should be at most %d", int64(maxAmount))
+	}
+	if req.Months <= 0 {
+		v.add("months", ReasonMonthsNotPositive, "months should be positive")
+	} else if req.Months > maxMonths {
+		v.add("months", ReasonMonthsTooLarge, "months should be at most %d", maxMonths)
+	}
+	if req.InitialPayment < 0 {
+		v.add("initial_payment", ReasonInitialPaymentNegative, "initial payment should not be negative")
+	} else if req.InitialPayment > maxAmount {
+		v.add("initial_payment", ReasonInitialPaymentTooLarge, "initial payment should be at most %d", int64(maxAmount))
+	}
+	if req.PaymentDay < 0 || req.PaymentDay > 31 {
+		v.add("payment_day", ReasonPaymentDayOutOfRange, "payment day should be between 1 and 31")
+	}
+	program, _ := ls.resolveProgram(ctx, &v, req.Program)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	// Кредит подбирается по ставке на дату выдачи, расчет ниже берет ту же ставку из программы реестра
+	priced, _, err := ls.priceProgram(ctx, program, ls.issueDate(req.IssueDate))
+	if err != nil {
+		return nil, err
+	}
+	budget := money.FromRubles(req.MonthlyPayment)
+	loanSum, down, limit, err := ls.affordableLoan(budget, req, priced)
+	if err != nil {
+		return nil, err
+	}
+	loanReq := &entities.LoanRequest{
+		ObjectCost:     loanSum + down,
+		InitialPayment: down,
+		Months:         req.Months,
+		Program:        req.Program,
+		PaymentType:    req.PaymentType,
+		IssueDate:      req.IssueDate,
+		PaymentDay:     req.PaymentDay,
+	}
+	if loanSum <= 0 {
+		if limit == entities.AffordabilityLimit_INITIAL_PAYMENT {
+			v.add("initial_payment", db.ReasonInitialPaymentTooLow, "the initial payment should be more")
+		} else {
+			v.add("monthly_payment", ReasonLoanNotAffordable, "monthly payment is too small for this term")
+		}
+		return nil, v.err()
+	}
+	// Минимальные срок и сумма кредита программы проверяются на итоговом кредите
+	v = append(v, program.LimitViolations(loanReq.ObjectCost, loanReq.InitialPayment, loanReq.Months)...)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	// Платеж по кредиту в целых рублях не больше бюджета: округление до копейки не выходит за целый бюджет
+	res, _, err := ls.calculateProgram(ctx, loanReq, program)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.AffordabilityResult{
+		Params:      res.Params,
+		Program:     res.Program,
+		Aggregates:  res.Aggregates,
+		PaymentType: res.PaymentType,
+		LimitedBy:   limit,
+	}, nil
+}
+
+// affordableLoan возвращает сумму кредита и взнос в рублях и то, что ограничило кредит.
+// Без доступного взноса возвращается минимальный взнос программы для найденной суммы.
+func (ls *LoanServiceServer) affordableLoan(budget money.Money, req *entities.AffordabilityRequest, program db.Program) (int64, int64, entities.AffordabilityLimit, error) {
+	limit := entities.AffordabilityLimit_BUDGET
+
+	loanSum, err := ls.maxLoanSum(budget, programRates(program, req.Months), req.Months, req.PaymentType)
+	if err != nil {
+		return 0, 0, limit, err
+	}
+	if program.MaxLoanSum > 0 && loanSum >= program.MaxLoanSum {
+		loanSum, limit = program.MaxLoanSum, entities.AffordabilityLimit_MAX_LOAN_SUM
+	}
+
+	share := minInitialShare(program)
+	if req.InitialPayment == 0 {
+		down, err := minInitialPayment(loanSum, share)
+		return loanSum, down, limit, err
+	}
+	if byDown, ok := maxLoanByInitialPayment(req.InitialPayment, share); ok && loanSum > byDown {
+		loanSum, limit = byDown, entities.AffordabilityLimit_INITIAL_PAYMENT
+	}
+	return loanSum, req.InitialPayment, limit, nil
+}
+
+// maxLoanSum возвращает наибольшую сумму кредита в рублях, наибольший платеж по которой не больше бюджета
+func (ls *LoanServiceServer) maxLoanSum(budget money.Money, rates rateSchedule, months int64, paymentType entities.PaymentType) (int64, error) {
+	if len(rates) > 1 {
+		return ls.maxSteppedLoanSum(budget, rates, months, paymentType)
+	}
+	annualRate := rates[0].rate
+	// Доля суммы кредита в (первом) платеже
+	share := annuityFactor(annualRate, months)
+	if paymentType == entities.PaymentType_DIFFERENTIATED {
+		share = new(big.Rat).Add(big.NewRat(1, months), annualRate.Monthly())
+	}
+	loanSum, err := floorRubles(new(big.Rat).Quo(budget.Rat(), share))
+	if err != nil {
+		return 0, fmt.Errorf("maxLoanSum:%v", err)
+	}
+	// Платеж округляется до копейки, поэтому в бюджет могут уложиться еще несколько рублей кредита.
+	// Рубль кредита добавляет к платежу не меньше копейки за срок, так что запас — не больше срока в рублях.
+	// Первая ошибка расчета прерывает поиск и возвращается после него
+	var searchErr error
+	extra := sort.Search(int(months)+1, func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+		payment, err := ls.firstPayment(money.FromRubles(loanSum+int64(i)+1), annualRate, months, paymentType)
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return payment > budget
+	})
+	if searchErr != nil {
+		return 0, searchErr
+	}
+	return loanSum + int64(extra), nil
+}
+
+// maxSteppedLoanSum ищет бинарным поиском наибольший кредит при ступенчатой ставке: после льготного периода
+// платеж может вырасти, поэтому в бюджет должен укладываться наибольший платеж графика, а не первый.
+// Кредит не больше бюджета за весь срок: за срок выплачивается как минимум вся сумма.
+func (ls *LoanServiceServer) maxSteppedLoanSum(budget money.Money, rates rateSchedule, months int64, paymentType entities.PaymentType) (int64, error) {
+	hi := budget.Rubles(money.Floor) * months
+	// Первая ошибка расчета прерывает поиск и возвращается после него
+	var searchErr error
+	n := sort.Search(int(hi), func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+		payment, err := ls.maxPayment(money.FromRubles(int64(i)+1), rates, months, paymentType)
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return payment > budget
+	})
+	if searchErr != nil {
+		return 0, searchErr
+	}
+	return int64(n), nil
+}
+
+// maxPayment возвращает наибольший платеж графика кредита loanSum. При одной ставке это первый платеж,
+// при ступенчатой график строится целиком.
+func (ls *LoanServiceServer) maxPayment(loanSum money.Money, rates rateSchedule, months int64, paymentType entities.PaymentType) (money.Money, error) {
+	if len(rates) == 1 {
+		return ls.firstPayment(loanSum, rates[0].rate, months, paymentType)
+	}
+	// Даты платежей на суммы не влияют
+	rows, err := ls.buildPrepaymentSchedule(loanSum, rates, months, paymentType, paymentCalendar{day: 1}, nil)
+	if err != nil {
+		return 0, err
+	}
+	var res money.Money
+	for _, row := range rows {
+		res = max(res, row.payment)
+	}
+	return res, nil
+}
+
+// programRates возвращает ставки программы по периодам на срок months
+func programRates(program db.Program, months int64) rateSchedule {
+	return newRateSchedule(program.AnnualRate, program.RatePeriods, months)
+}
+
+// minInitialShare возвращает минимальную долю взноса программы точной дробью.
+// Долю берем из ее десятичной записи, как она задана в конфиге: 0.2 — ровно 1/5
+func minInitialShare(program db.Program) *big.Rat {
+	share, _ := new(big.Rat).SetString(strconv.FormatFloat(program.MinInitialPayment, 'g', -1, 64))
+	return share
+}
+
+// minInitialPayment возвращает наименьший взнос в рублях, при котором кредит loanSum укладывается в долю share:
+// down >= share * (loan + down), то есть down >= loan * share / (1 - share)
+func minInitialPayment(loanSum int64, share *big.Rat) (int64, error) {
+	down := new(big.Rat).Mul(money.FromRubles(loanSum).Rat(), share)
+	down.Quo(down, new(big.Rat).Sub(big.NewRat(1, 1), share))
+	kopecks, err := money.FromRat(down, money.Ceil)
+	if err != nil {
+		return 0, fmt.Errorf("minInitialPayment:%v", err)
+	}
+	return kopecks.Rubles(money.Ceil), nil
+}
+
+// maxLoanByInitialPayment возвращает наибольший кредит, для которого взнос down не меньше доли share:
+// loan <= down * (1 - share) / share. Без минимальной доли кредит взносом не ограничен,
+// как и при крошечной доле, когда предел не помещается в копейки.
+func maxLoanByInitialPayment(down int64, share *big.Rat) (int64, bool) {
+	if share.Sign() == 0 {
+		return 0, false
+	}
+	loan := new(big.Rat).Mul(money.FromRubles(down).Rat(), new(big.Rat).Sub(big.NewRat(1, 1), share))
+	rubles, err := floorRubles(loan.Quo(loan, share))
+	if err != nil {
+		return 0, false
+	}
+	return rubles, true
+}
+
+// firstPayment считает первый платеж так же, как график: аннуитет или часть долга плюс проценты
+func (ls *LoanServiceServer) firstPayment(loanSum money.Money, annualRate money.BasisPoints, months int64, paymentType entities.PaymentType) (money.Money, error) {
+	if paymentType != entities.PaymentType_DIFFERENTIATED {
+		return ls.calculateMonthlyPayment(loanSum, annualRate, months)
+	}
+	interest, err := loanSum.Mul(annualRate.Monthly(), money.HalfUp)
+	if err != nil {
+		return 0, fmt.Errorf("firstPayment:%v", err)
+	}
+	return loanSum/money.Money(months) + interest, nil
+}
+
+// floorRubles округляет сумму в копейках вниз до целых рублей
+func floorRubles(kopecks *big.Rat) (int64, error) {
+	m, err := money.FromRat(kopecks, money.Floor)
+	if err != nil {
+		return 0, err
+	}
+	return m.Rubles(money.Floor), nil
+}
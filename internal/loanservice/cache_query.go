@@ -0,0 +1,140 @@
+package loanservice
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+	"github.com/Dorji/sberInterview/internal/loanservice/storage"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// Машиночитаемые причины ошибок в параметрах списка расчетов
+const (
+	ReasonPageSizeOutOfRange = "PAGE_SIZE_OUT_OF_RANGE"
+	ReasonInvalidPageToken   = "INVALID_PAGE_TOKEN"
+	ReasonInvalidRange       = "INVALID_RANGE"
+	ReasonNotFound           = "CALCULATION_NOT_FOUND"
+	ReasonExpired            = "CALCULATION_EXPIRED"
+)
+
+// cacheQuery переводит CacheRequest в запрос к кешу, собирая все нарушения
+func cacheQuery(req *entities.CacheRequest) (storage.Query, error) {
+	var v violations
+	q := storage.Query{
+		MinLoanSum: req.MinLoanSum,
+		MaxLoanSum: req.MaxLoanSum,
+		MinMonths:  req.MinMonths,
+		MaxMonths:  req.MaxMonths,
+		Sort:       storage.SortField(req.Sort),
+		Desc:       req.Desc,
+		Limit:      int(req.PageSize),
+	}
+
+	if req.Program != nil {
+		q.ProgramCode = db.ProgramCode(req.Program)
+		if q.ProgramCode == "" && (req.Program.Salary || req.Program.Military || req.Program.Base) {
+			v.add("program", db.ReasonProgramAmbiguous, "choose only 1 program")
+		}
+	}
+	checkRange(&v, "min_loan_sum", "max_loan_sum", req.MinLoanSum, req.MaxLoanSum)
+	checkRange(&v, "min_months", "max_months", req.MinMonths, req.MaxMonths)
+	if req.CreatedFrom != nil {
+		q.CreatedFrom = req.CreatedFrom.AsTime()
+	}
+	if req.CreatedTo != nil {
+		q.CreatedTo = req.CreatedTo.AsTime()
+	}
+	if !q.CreatedFrom.IsZero() && !q.CreatedTo.IsZero() && !q.CreatedFrom.Before(q.CreatedTo) {
+		v.add("created_to", ReasonInvalidRange, "created_to should be after created_from")
+	}
+
+	switch {
+	case req.PageSize < 0 || req.PageSize > maxPageSize:
+		v.add("page_size", ReasonPageSizeOutOfRange, "page size should be between 1 and %d", maxPageSize)
+	case req.PageSize == 0:
+		q.Limit = defaultPageSize
+	}
+
+	if req.PageToken != "" {
+		cursor, err := decodePageToken(req.PageToken, req.Sort, req.Desc)
+		if err != nil {
+			v.add("page_token", ReasonInvalidPageToken, "%v", err)
+		}
+		q.After = cursor
+	}
+	return q, v.err()
+}
+
+func checkRange(v *violations, minField, maxField string, min, max int64) {
+	if min < 0 {
+		v.add(minField, ReasonInvalidRange, "%s should not be negative", minField)
+	}
+	if max < 0 {
+		v.add(maxField, ReasonInvalidRange, "%s should not be negative", maxField)
+	}
+	if min > 0 && max > 0 && min > max {
+		v.add(maxField, ReasonInvalidRange, "%s should not be less than %s", maxField, minField)
+	}
+}
+
+// Токен страницы хранит сортировку, чтобы его нельзя было применить к другому порядку
+func encodePageToken(c *storage.Cursor, sort entities.CacheSort, desc bool) string {
+	if c == nil {
+		return ""
+	}
+	raw := fmt.Sprintf("%d:%t:%d:%d", sort, desc, c.Key, c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePageToken(token string, sort entities.CacheSort, desc bool) (*storage.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("malformed page token")
+	}
+	var (
+		tokenSort entities.CacheSort
+		tokenDesc bool
+		c         storage.Cursor
+	)
+	if _, err := fmt.Sscanf(string(raw), "%d:%t:%d:%d", &tokenSort, &tokenDesc, &c.Key, &c.ID); err != nil {
+		return nil, fmt.Errorf("malformed page token")
+	}
+	if tokenSort != sort || tokenDesc != desc {
+		return nil, fmt.Errorf("page token does not match sort order")
+	}
+	return &c, nil
+}
+
+// cacheError переводит ошибку кеша в NotFound, отличая вытесненные расчеты причиной CALCULATION_EXPIRED
+func cacheError(id int64, err error) error {
+	var st *status.Status
+	switch {
+	case errors.Is(err, storage.ErrExpired):
+		st = status.Newf(codes.NotFound, "calculation %d expired", id)
+		st = withReason(st, ReasonExpired)
+	case errors.Is(err, storage.ErrNotFound):
+		st = status.Newf(codes.NotFound, "calculation %d not found", id)
+		st = withReason(st, ReasonNotFound)
+	default:
+		return status.Errorf(codes.Internal, "cache: %v", err)
+	}
+	return st.Err()
+}
+
+func withReason(st *status.Status, reason string) *status.Status {
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: db.ErrorDomain})
+	if err != nil {
+		return st
+	}
+	return detailed
+}
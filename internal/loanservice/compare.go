@@ -0,0 +1,79 @@
+package loanservice
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"github.com/Dorji/sberInterview/internal/money"
+	"google.golang.org/grpc/status"
+)
+
+// ReasonProgramRateUnavailable — ставку программы сейчас не определить: плавающая ставка без источника
+// ключевой ставки или источник недоступен
+const ReasonProgramRateUnavailable = "PROGRAM_RATE_UNAVAILABLE"
+
+// Compare считает кредит по каждой программе реестра. Программы, на ограничения которых
+// запрос не проходит или ставку которых не удалось определить, возвращаются отдельно
+// с нарушениями. Результаты в кеш не сохраняются.
+func (ls *LoanServiceServer) Compare(ctx context.Context, req *entities.LoanRequest) (*entities.CompareResult, error) {
+	ctx = ls.pin(ctx)
+	v, _ := validateParams(req)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	calendar, err := ls.newPaymentCalendar(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &entities.CompareResult{
+		Params:      loanParams(req, calendar),
+		PaymentType: req.PaymentType,
+	}
+	for _, program := range ls.snapshot(ctx).programs.List() {
+		ref := &entities.LoanProgram{Code: program.Code}
+		if limits := program.LimitViolations(req.ObjectCost, req.InitialPayment, req.Months); len(limits) > 0 {
+			ineligible := &entities.IneligibleProgram{Program: ref, Name: program.Name}
+			for _, l := range limits {
+				ineligible.Violations = append(ineligible.Violations, &entities.FieldViolation{
+					Field:   l.Field,
+					Reason:  l.Reason,
+					Message: l.Description,
+				})
+			}
+			res.Ineligible = append(res.Ineligible, ineligible)
+			continue
+		}
+
+		// Без ключевой ставки не считается только плавающая программа, остальные сравниваются
+		loan, err := ls.priceLoan(ctx, req, program)
+		if err != nil {
+			res.Ineligible = append(res.Ineligible, &entities.IneligibleProgram{
+				Program: ref,
+				Name:    program.Name,
+				Violations: []*entities.FieldViolation{{
+					Field:   "program",
+					Reason:  ReasonProgramRateUnavailable,
+					Message: status.Convert(err).Message(),
+				}},
+			})
+			continue
+		}
+		result, _, err := ls.calculatePriced(req, loan)
+		if err != nil {
+			return nil, err
+		}
+		res.Offers = append(res.Offers, &entities.ProgramOffer{
+			Program:    ref,
+			Name:       program.Name,
+			Aggregates: result.Aggregates,
+		})
+	}
+
+	// При равной переплате сохраняется порядок реестра
+	sort.SliceStable(res.Offers, func(i, j int) bool {
+		return money.FromProto(res.Offers[i].Aggregates.Exact.Overpayment) < money.FromProto(res.Offers[j].Aggregates.Exact.Overpayment)
+	})
+	return res, nil
+}
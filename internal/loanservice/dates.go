@@ -0,0 +1,63 @@
+package loanservice
+
+import (
+	"time"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// paymentCalendar считает даты платежей от даты выдачи кредита.
+// Платеж приходится на день day каждого месяца, в коротких месяцах
+// он переносится на последний день месяца.
+type paymentCalendar struct {
+	issue time.Time
+	day   int
+}
+
+// newPaymentCalendar берет дату выдачи и день платежа из запроса,
+// по умолчанию кредит выдается сегодня, а платеж совпадает с днем выдачи.
+func (ls *LoanServiceServer) newPaymentCalendar(req *entities.LoanRequest) (paymentCalendar, error) {
+	issue := ls.issueDate(req.IssueDate)
+	d := issue.Day()
+
+	day := int(req.PaymentDay)
+	switch {
+	case day == 0:
+		day = d
+	case day < 1 || day > 31:
+		return paymentCalendar{}, status.Errorf(codes.InvalidArgument, "payment day should be between 1 and 31")
+	}
+
+	return paymentCalendar{issue: issue, day: day}, nil
+}
+
+// issueDate возвращает дату выдачи без времени, по умолчанию сегодня
+func (ls *LoanServiceServer) issueDate(ts *timestamppb.Timestamp) time.Time {
+	issue := ls.now()
+	if ts != nil {
+		issue = ts.AsTime()
+	}
+	y, m, d := issue.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// date возвращает дату платежа с номером month (первый платеж через месяц после выдачи)
+func (c paymentCalendar) date(month int64) time.Time {
+	y, m, _ := c.issue.Date()
+	// time.Date сам нормализует переполнение месяца
+	first := time.Date(y, m+time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+
+	day := c.day
+	if last := daysIn(first.Year(), first.Month()); day > last {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+// daysIn возвращает количество дней в месяце с учетом високосных лет
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
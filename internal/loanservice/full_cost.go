@@ -0,0 +1,188 @@
+package loanservice
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"github.com/Dorji/sberInterview/internal/money"
+)
+
+// Машиночитаемые причины ошибок в расходах по кредиту
+const (
+	ReasonCostNegative    = "COST_NEGATIVE"
+	ReasonCostTooLarge    = "COST_TOO_LARGE"
+	ReasonCostsExceedLoan = "COSTS_EXCEED_LOAN"
+)
+
+const (
+	// basePeriodsPerYear — число базовых периодов в году (ЧБП) для ежемесячных платежей
+	basePeriodsPerYear = 12
+	// basePeriodDays — длительность месячного базового периода в днях
+	basePeriodDays = 365.0 / basePeriodsPerYear
+)
+
+// cashFlow — платеж заемщика (отрицательный) или выдача кредита (положительная) в дату date
+type cashFlow struct {
+	date   time.Time
+	amount money.Money
+}
+
+// validateCosts проверяет, что расходы по кредиту не отрицательны и не больше maxAmount, а удержанное
+// при выдаче — разовая комиссия и премия первого года — меньше кредита loanSum: иначе заемщик
+// ничего не получает и у уравнения ПСК нет корня. Без известной суммы кредита (0) она не сравнивается.
+func validateCosts(v *violations, costs *entities.LoanCosts, loanSum money.Money) {
+	if costs == nil {
+		return
+	}
+	amountsOK := true
+	for _, c := range []struct {
+		field  string
+		amount *entities.Money
+	}{
+		{"costs.one_off_fee", costs.OneOffFee},
+		{"costs.monthly_fee", costs.MonthlyFee},
+		{"costs.annual_insurance", costs.AnnualInsurance},
+	} {
+		if c.amount.GetRubles() > maxAmount {
+			amountsOK = false
+			v.add(c.field, ReasonCostTooLarge, "%s should be at most %d", c.field, int64(maxAmount))
+		} else if money.FromProto(c.amount) < 0 {
+			amountsOK = false
+			v.add(c.field, ReasonCostNegative, "%s should not be negative", c.field)
+		}
+	}
+	if costs.InsuranceRateBps < 0 {
+		amountsOK = false
+		v.add("costs.insurance_rate_bps", ReasonCostNegative, "costs.insurance_rate_bps should not be negative")
+	}
+	if !amountsOK || loanSum <= 0 {
+		return
+	}
+	premium, err := loanSum.Mul(money.BasisPoints(costs.InsuranceRateBps).Rat(), money.HalfUp)
+	withheld := money.FromProto(costs.OneOffFee) + money.FromProto(costs.AnnualInsurance) + premium
+	if err != nil || withheld >= loanSum {
+		v.add("costs", ReasonCostsExceedLoan, "one-off fee and first year insurance should be less than the loan sum")
+	}
+}
+
+// addFullCost дополняет агрегаты полной стоимостью кредита и разбивкой непроцентных расходов.
+// Расходы списываются так: разовая комиссия и премия первого года — при выдаче,
+// ежемесячная комиссия — с каждым плановым платежом, премия следующего года — с платежом,
+// которым заканчивается год кредита. Премия от остатка берется с долга на начало года.
+func addFullCost(aggregates *entities.LoanAggregates, costs *entities.LoanCosts, loanSum money.Money, rows []scheduleRow, calendar paymentCalendar) error {
+	oneOff := money.FromProto(costs.GetOneOffFee())
+	monthlyFee := money.FromProto(costs.GetMonthlyFee())
+	insuranceRate := money.BasisPoints(costs.GetInsuranceRateBps()).Rat()
+
+	premium := func(balance money.Money) (money.Money, error) {
+		byBalance, err := balance.Mul(insuranceRate, money.HalfUp)
+		return money.FromProto(costs.GetAnnualInsurance()) + byBalance, err
+	}
+
+	var breakdown struct{ fees, insurance money.Money }
+	first, err := premium(loanSum)
+	if err != nil {
+		return fmt.Errorf("addFullCost:%v", err)
+	}
+	breakdown.insurance += first
+	flows := make([]cashFlow, 0, len(rows)+1)
+	flows = append(flows, cashFlow{date: calendar.issue, amount: loanSum - oneOff - first})
+	for i, row := range rows {
+		amount := row.payment + row.prepayment + monthlyFee
+		breakdown.fees += monthlyFee
+		// Год кредита закончился, а долг еще не погашен — премия на следующий год
+		if (i+1)%12 == 0 && row.balance > 0 {
+			p, err := premium(row.balance)
+			if err != nil {
+				return fmt.Errorf("addFullCost:%v", err)
+			}
+			amount += p
+			breakdown.insurance += p
+		}
+		flows = append(flows, cashFlow{date: row.date, amount: -amount})
+	}
+
+	rate, err := fullCostRate(flows)
+	if err != nil {
+		return fmt.Errorf("addFullCost:%v", err)
+	}
+	total := oneOff + breakdown.fees + breakdown.insurance
+	aggregates.FullCostRate = rate
+	aggregates.FullCost = (money.FromProto(aggregates.GetExact().GetOverpayment()) + total).Proto()
+	aggregates.Costs = &entities.CostBreakdown{
+		OneOffFees:  oneOff.Proto(),
+		MonthlyFees: breakdown.fees.Proto(),
+		Insurance:   breakdown.insurance.Proto(),
+		Total:       total.Proto(),
+	}
+	return nil
+}
+
+// fullCostRate считает ПСК по методике Банка России (ст. 6 353-ФЗ): ПСК = i * ЧБП * 100, где i —
+// корень уравнения sum(ДП_k / ((1 + e_k*i) * (1 + i)^q_k)) = 0. q_k — число полных месяцев от выдачи
+// до платежа, e_k — остаток дней, деленный на длительность месяца. Возвращает ПСК в тысячных долях процента.
+func fullCostRate(flows []cashFlow) (int64, error) {
+	issue := flows[0].date
+	type term struct {
+		q, e   float64
+		amount float64
+	}
+	terms := make([]term, 0, len(flows))
+	for _, f := range flows {
+		q, rest := fullMonths(issue, f.date)
+		terms = append(terms, term{q: float64(q), e: float64(rest) / basePeriodDays, amount: float64(f.amount)})
+	}
+	npv := func(i float64) float64 {
+		var sum float64
+		for _, t := range terms {
+			sum += t.amount / ((1 + t.e*i) * math.Pow(1+i, t.q))
+		}
+		return sum
+	}
+
+	// Выдача положительна, платежи отрицательны, поэтому сумма растет вместе со ставкой.
+	// Корень ищем делением пополам: при нулевой ставке платежей больше выдачи, сверху границу удваиваем.
+	lo, hi := 0.0, 0.01
+	if npv(lo) >= 0 {
+		return 0, nil
+	}
+	for npv(hi) < 0 {
+		if hi *= 2; hi > 1e6 {
+			return 0, fmt.Errorf("fullCostRate:no root")
+		}
+	}
+	for n := 0; n < 100 && hi-lo > 1e-12; n++ {
+		mid := (lo + hi) / 2
+		if npv(mid) < 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	// Тысячные доли процента: i * ЧБП * 100 * 1000
+	return int64(math.Round((lo + hi) / 2 * basePeriodsPerYear * 100 * 1000)), nil
+}
+
+// fullMonths возвращает число полных месяцев от from до to и оставшиеся дни.
+// Месяц от 31-го числа заканчивается в последний день короткого месяца.
+func fullMonths(from, to time.Time) (int64, int) {
+	months := int64(to.Year()-from.Year())*12 + int64(to.Month()-from.Month())
+	anchor := addMonths(from, months)
+	if anchor.After(to) {
+		months--
+		anchor = addMonths(from, months)
+	}
+	return months, int(to.Sub(anchor).Hours() / 24)
+}
+
+// addMonths сдвигает дату на months месяцев, не выходя за последний день месяца
+func addMonths(t time.Time, months int64) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	day := t.Day()
+	if last := daysIn(first.Year(), first.Month()); day > last {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, time.UTC)
+}
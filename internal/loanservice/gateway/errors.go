@@ -0,0 +1,56 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// errorMarshaler пишет только заполненные поля, чтобы тело было как в спецификации: {"error": "..."}
+var errorMarshaler = protojson.MarshalOptions{UseProtoNames: true}
+
+// ErrorHandler отдает ошибки gRPC в формате {"error": "..."} с HTTP-кодом,
+// соответствующим коду gRPC (InvalidArgument и FailedPrecondition -> 400).
+// Нарушения из google.rpc.BadRequest отдаются массивом violations.
+func ErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	st := status.Convert(err)
+
+	body := &entities.Error{Error: st.Message()}
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if body.Reason == "" {
+				body.Reason = d.Reason
+			}
+		case *errdetails.BadRequest:
+			for _, v := range d.FieldViolations {
+				body.Violations = append(body.Violations, &entities.FieldViolation{
+					Field:   v.Field,
+					Reason:  v.Reason,
+					Message: v.Description,
+				})
+			}
+		}
+	}
+	// Без ErrorInfo причиной служит первое нарушение
+	if body.Reason == "" && len(body.Violations) > 0 {
+		body.Reason = body.Violations[0].Reason
+	}
+
+	buf, merr := errorMarshaler.Marshal(body)
+	if merr != nil {
+		http.Error(w, `{"error": "failed to marshal error message"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Del("Trailer")
+	w.Header().Del("Transfer-Encoding")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(runtime.HTTPStatusFromCode(st.Code()))
+	_, _ = w.Write(buf)
+}
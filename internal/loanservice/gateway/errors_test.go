@@ -0,0 +1,54 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorHandler(t *testing.T) {
+	withReason, err := status.New(codes.InvalidArgument, "the loan sum should be at most 3000000").
+		WithDetails(&errdetails.ErrorInfo{Reason: "LOAN_SUM_TOO_LARGE"})
+	assert.NoError(t, err)
+	withViolations, err := status.New(codes.InvalidArgument, "object cost should be positive").
+		WithDetails(&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "object_cost", Reason: "OBJECT_COST_NOT_POSITIVE", Description: "object cost should be positive"},
+			{Field: "program", Reason: "PROGRAM_NOT_SELECTED", Description: "choose program"},
+		}})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{"Invalid argument", status.Error(codes.InvalidArgument, "choose program"), http.StatusBadRequest, `{"error":"choose program"}`},
+		{"Failed precondition", status.Error(codes.FailedPrecondition, "empty cache"), http.StatusBadRequest, `{"error":"empty cache"}`},
+		{"Not found", status.Error(codes.NotFound, "not found"), http.StatusNotFound, `{"error":"not found"}`},
+		{"With reason", withReason.Err(), http.StatusBadRequest, `{"error":"the loan sum should be at most 3000000","reason":"LOAN_SUM_TOO_LARGE"}`},
+		{"With violations", withViolations.Err(), http.StatusBadRequest, `{"error":"object cost should be positive","reason":"OBJECT_COST_NOT_POSITIVE","violations":[` +
+			`{"field":"object_cost","reason":"OBJECT_COST_NOT_POSITIVE","message":"object cost should be positive"},` +
+			`{"field":"program","reason":"PROGRAM_NOT_SELECTED","message":"choose program"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/execute", nil)
+
+			ErrorHandler(context.Background(), runtime.NewServeMux(), NewMarshaler(), rec, req, tt.err)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// dateFields — поля google.protobuf.Timestamp, в которых хранится дата без времени.
+// Остальные Timestamp (время расчета, фильтры кеша) отдаются полным RFC 3339.
+var dateFields = map[protoreflect.FullName]bool{
+	"entities.LoanRequest.issue_date":           true,
+	"entities.AffordabilityRequest.issue_date":  true,
+	"entities.SolveRequest.issue_date":          true,
+	"entities.LoanParams.issue_date":            true,
+	"entities.LoanAggregates.last_payment_date": true,
+	"entities.KeyRateInfo.date":                 true,
+	"entities.ScheduleRow.payment_date":         true,
+	"entities.Prepayment.date":                  true,
+	"entities.RateVersion.effective_from":       true,
+	"entities.RateVersion.effective_to":         true,
+}
+
+// DateMarshaler отдает даты без времени в виде "YYYY-MM-DD", как в спецификации,
+// и принимает такие же даты во входящем JSON. Преобразуются только поля из dateFields.
+type DateMarshaler struct {
+	runtime.JSONPb
+}
+
+// NewMarshaler возвращает маршалер для gateway с настройками по умолчанию
+func NewMarshaler() runtime.Marshaler {
+	return &runtime.HTTPBodyMarshaler{
+		Marshaler: &DateMarshaler{
+			JSONPb: runtime.JSONPb{
+				MarshalOptions: protojson.MarshalOptions{
+					EmitUnpopulated: true,
+				},
+				UnmarshalOptions: protojson.UnmarshalOptions{
+					DiscardUnknown: true,
+				},
+			},
+		},
+	}
+}
+
+func (m *DateMarshaler) Marshal(v interface{}) ([]byte, error) {
+	data, err := m.JSONPb.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return data, nil
+	}
+	return rewriteDates(data, msg.ProtoReflect().Descriptor(), shortDate)
+}
+
+func (m *DateMarshaler) Unmarshal(data []byte, v interface{}) error {
+	if msg, ok := v.(proto.Message); ok {
+		var err error
+		if data, err = rewriteDates(data, msg.ProtoReflect().Descriptor(), fullDate); err != nil {
+			return err
+		}
+	}
+	return m.JSONPb.Unmarshal(data, v)
+}
+
+func (m *DateMarshaler) NewDecoder(r io.Reader) runtime.Decoder {
+	return runtime.DecoderFunc(func(v interface{}) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return m.Unmarshal(data, v)
+	})
+}
+
+func (m *DateMarshaler) NewEncoder(w io.Writer) runtime.Encoder {
+	return runtime.EncoderFunc(func(v interface{}) error {
+		data, err := m.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	})
+}
+
+// shortDate отбрасывает нулевое время у даты: "2024-02-18T00:00:00Z" -> "2024-02-18"
+func shortDate(s string) string {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil || !t.Equal(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)) {
+		return s
+	}
+	return t.Format(time.DateOnly)
+}
+
+// fullDate дополняет дату нулевым временем, чтобы ее принял protojson: "2024-02-18" -> "2024-02-18T00:00:00Z"
+func fullDate(s string) string {
+	if _, err := time.Parse(time.DateOnly, s); err != nil {
+		return s
+	}
+	return s + "T00:00:00Z"
+}
+
+// rewriteDates обходит JSON-объект сообщения md вместе с его дескриптором и применяет convert
+// к строковым значениям полей-дат. Порядок ключей и остальные значения не меняются.
+func rewriteDates(data []byte, md protoreflect.MessageDescriptor, convert func(string) string) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return data, nil
+	}
+	fields, err := objectFields(trimmed)
+	if err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := rewriteField(f.value, fieldByKey(md, f.key), convert)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// rewriteField преобразует значение поля fd: дату, вложенное сообщение или их список и словарь
+func rewriteField(value json.RawMessage, fd protoreflect.FieldDescriptor, convert func(string) string) (json.RawMessage, error) {
+	if fd == nil || fd.Kind() != protoreflect.MessageKind {
+		return value, nil
+	}
+	if fd.IsMap() {
+		fd = fd.MapValue()
+		if fd.Kind() != protoreflect.MessageKind {
+			return value, nil
+		}
+		entries, err := objectFields(value)
+		if err != nil || entries == nil {
+			return value, nil
+		}
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		for i, e := range entries {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(e.key)
+			buf.Write(key)
+			buf.WriteByte(':')
+			v, err := rewriteValue(e.value, fd, convert)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(v)
+		}
+		buf.WriteByte('}')
+		return buf.Bytes(), nil
+	}
+	if fd.IsList() {
+		var items []json.RawMessage
+		if err := json.Unmarshal(value, &items); err != nil || items == nil {
+			return value, nil
+		}
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for i, item := range items {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			v, err := rewriteValue(item, fd, convert)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(v)
+		}
+		buf.WriteByte(']')
+		return buf.Bytes(), nil
+	}
+	return rewriteValue(value, fd, convert)
+}
+
+// rewriteValue преобразует одно значение поля-сообщения fd
+func rewriteValue(value json.RawMessage, fd protoreflect.FieldDescriptor, convert func(string) string) (json.RawMessage, error) {
+	md := fd.Message()
+	if dateFields[fd.FullName()] {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return value, nil
+		}
+		return json.Marshal(convert(s))
+	}
+	// Прочие well-known types (Timestamp, Empty, обертки) сериализуются не объектами сообщений
+	if strings.HasPrefix(string(md.FullName()), "google.protobuf.") {
+		return value, nil
+	}
+	return rewriteDates(value, md, convert)
+}
+
+// fieldByKey ищет поле по имени в JSON: camelCase или имени из proto
+func fieldByKey(md protoreflect.MessageDescriptor, key string) protoreflect.FieldDescriptor {
+	if fd := md.Fields().ByJSONName(key); fd != nil {
+		return fd
+	}
+	return md.Fields().ByName(protoreflect.Name(key))
+}
+
+// jsonField — пара ключ-значение JSON-объекта
+type jsonField struct {
+	key   string
+	value json.RawMessage
+}
+
+// objectFields разбирает JSON-объект на поля с сохранением порядка. Для null возвращает nil.
+func objectFields(data []byte) ([]jsonField, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == nil {
+		return nil, nil
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return nil, fmt.Errorf("expected JSON object")
+	}
+	res := []jsonField{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		res = append(res, jsonField{key: key, value: value})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
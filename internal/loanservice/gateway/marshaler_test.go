@@ -0,0 +1,82 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDateMarshaler(t *testing.T) {
+	m := NewMarshaler()
+
+	t.Run("Marshal renders plain date", func(t *testing.T) {
+		data, err := m.Marshal(&entities.LoanAggregates{
+			LastPaymentDate: timestamppb.New(time.Date(2044, time.February, 18, 0, 0, 0, 0, time.UTC)),
+		})
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"lastPaymentDate":"2044-02-18"`)
+	})
+
+	t.Run("Marshal keeps timestamps with time", func(t *testing.T) {
+		data, err := m.Marshal(&entities.LoanAggregates{
+			LastPaymentDate: timestamppb.New(time.Date(2044, time.February, 18, 10, 30, 0, 0, time.UTC)),
+		})
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"2044-02-18T10:30:00Z"`)
+	})
+
+	t.Run("Decoder accepts plain date", func(t *testing.T) {
+		var req entities.LoanRequest
+		err := m.NewDecoder(strings.NewReader(`{"issue_date": "2024-02-18", "months": 240}`)).Decode(&req)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, time.February, 18, 0, 0, 0, 0, time.UTC), req.IssueDate.AsTime())
+		assert.Equal(t, int64(240), req.Months)
+	})
+
+	t.Run("Decoder accepts RFC 3339", func(t *testing.T) {
+		var req entities.LoanRequest
+		err := m.NewDecoder(strings.NewReader(`{"issue_date": "2024-02-18T00:00:00Z"}`)).Decode(&req)
+		assert.NoError(t, err)
+		assert.Equal(t, 2024, req.IssueDate.AsTime().Year())
+	})
+}
+
+func TestDateMarshalerFields(t *testing.T) {
+	m := NewMarshaler()
+	midnight := time.Date(2024, time.February, 18, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Only date fields are shortened", func(t *testing.T) {
+		data, err := m.Marshal(&entities.LoanResult{
+			CreatedAt:   timestamppb.New(midnight),
+			Params:      &entities.LoanParams{IssueDate: timestamppb.New(midnight)},
+			Schedule:    []*entities.ScheduleRow{{Month: 1, PaymentDate: timestamppb.New(midnight.AddDate(0, 1, 0))}},
+			RateVersion: "2024-02-18",
+		})
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"createdAt":"2024-02-18T00:00:00Z"`, "real timestamp at midnight keeps its time")
+		assert.Contains(t, string(data), `"issueDate":"2024-02-18"`)
+		assert.Contains(t, string(data), `"paymentDate":"2024-03-18"`)
+		assert.Contains(t, string(data), `"rateVersion":"2024-02-18"`)
+	})
+
+	t.Run("Decoder leaves other strings alone", func(t *testing.T) {
+		var req entities.ProgramRate
+		err := m.NewDecoder(strings.NewReader(`{"code": "2024-02-18", "rate_type": "fixed"}`)).Decode(&req)
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-02-18", req.Code)
+	})
+
+	t.Run("Decoder converts nested dates", func(t *testing.T) {
+		var req entities.PrepaymentRequest
+		err := m.NewDecoder(strings.NewReader(`{"request": {"issueDate": "2024-02-18"}, "prepayments": [{"date": "2024-05-18", "everyMonths": 1}]}`)).Decode(&req)
+		assert.NoError(t, err)
+		assert.Equal(t, midnight, req.GetRequest().IssueDate.AsTime())
+		if assert.Len(t, req.Prepayments, 1) {
+			assert.Equal(t, midnight.AddDate(0, 3, 0), req.Prepayments[0].Date.AsTime())
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// actorKey — ключ имени пользователя, прошедшего проверку токена
+type actorKey struct{}
+
+// TokenAuth пускает запросы с bearer-токеном из метаданных authorization.
+// Хранятся только SHA-256 токенов; gRPC Gateway передает сюда заголовок Authorization.
+type TokenAuth struct {
+	users map[[sha256.Size]byte]string
+}
+
+// NewTokenAuth создает проверку по токенам пользователей: имя -> SHA-256 токена в hex
+func NewTokenAuth(tokens map[string]string) (*TokenAuth, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("NewTokenAuth:no users")
+	}
+	res := &TokenAuth{users: make(map[[sha256.Size]byte]string, len(tokens))}
+	for name, hash := range tokens {
+		raw, err := hex.DecodeString(hash)
+		if err != nil || len(raw) != sha256.Size {
+			return nil, fmt.Errorf("NewTokenAuth:%s: token hash should be %d hex bytes", name, sha256.Size)
+		}
+		key := [sha256.Size]byte(raw)
+		if other, ok := res.users[key]; ok {
+			return nil, fmt.Errorf("NewTokenAuth:%s and %s share a token", other, name)
+		}
+		res.users[key] = name
+	}
+	return res, nil
+}
+
+// UnaryInterceptor отклоняет запросы без известного токена и кладет имя пользователя в контекст
+func (a *TokenAuth) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var token string
+	for _, v := range md.Get("authorization") {
+		if t, ok := strings.CutPrefix(v, "Bearer "); ok {
+			token = t
+			break
+		}
+	}
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+	name, ok := a.users[sha256.Sum256([]byte(token))]
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return handler(WithActor(ctx, name), req)
+}
+
+// WithActor запоминает в контексте имя пользователя, от которого выполняется запрос
+func WithActor(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, actorKey{}, name)
+}
+
+// Actor возвращает имя пользователя из контекста, пустое — запрос без проверки токена
+func Actor(ctx context.Context) string {
+	name, _ := ctx.Value(actorKey{}).(string)
+	return name
+}
@@ -0,0 +1,113 @@
+package loadconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+)
+
+// Diff построчно описывает изменения между конфигами для лога перезагрузки.
+// Секции, которые применяются только при запуске, помечаются "(restart required)".
+func Diff(old, new *Config) []string {
+	var res []string
+	for _, s := range []struct {
+		name     string
+		old, new any
+		restart  bool
+	}{
+		{"http", old.HTTP, new.HTTP, true},
+		{"grpc", old.GRPC, new.GRPC, true},
+		{"debug", old.Debug, new.Debug, true},
+		{"cache", old.Cache, new.Cache, true},
+		{"storage", old.Storage, new.Storage, true},
+		{"admin", old.Admin, new.Admin, true},
+		{"key_rate", old.KeyRate, new.KeyRate, false},
+	} {
+		if reflect.DeepEqual(s.old, s.new) {
+			continue
+		}
+		line := fmt.Sprintf("%s: %+v -> %+v", s.name, s.old, s.new)
+		if s.restart {
+			line += " (restart required)"
+		}
+		res = append(res, line)
+	}
+
+	res = append(res, diffPrograms(effectivePrograms(old), effectivePrograms(new))...)
+	return append(res, diffRateVersions(old.RateVersions, new.RateVersions)...)
+}
+
+// effectivePrograms возвращает программы, из которых строится реестр: без программ в конфиге — по умолчанию
+func effectivePrograms(c *Config) []db.Program {
+	if len(c.Programs) == 0 {
+		return db.DefaultPrograms()
+	}
+	return c.Programs
+}
+
+// diffPrograms сравнивает программы по коду и поля изменившихся программ по их ключам в YAML
+func diffPrograms(old, new []db.Program) []string {
+	var res []string
+	before := make(map[string]db.Program, len(old))
+	for _, p := range old {
+		before[p.Code] = p
+	}
+	seen := make(map[string]bool, len(new))
+	for _, p := range new {
+		seen[p.Code] = true
+		prev, ok := before[p.Code]
+		if !ok {
+			res = append(res, fmt.Sprintf("programs.%s: added", p.Code))
+			continue
+		}
+		res = append(res, diffFields("programs."+p.Code, prev, p)...)
+	}
+	for _, p := range old {
+		if !seen[p.Code] {
+			res = append(res, fmt.Sprintf("programs.%s: removed", p.Code))
+		}
+	}
+	return res
+}
+
+// diffRateVersions сравнивает версии таблицы ставок по id
+func diffRateVersions(old, new []db.RateVersion) []string {
+	var res []string
+	before := make(map[string]db.RateVersion, len(old))
+	for _, v := range old {
+		before[v.ID] = v
+	}
+	seen := make(map[string]bool, len(new))
+	for _, v := range new {
+		seen[v.ID] = true
+		prev, ok := before[v.ID]
+		switch {
+		case !ok:
+			res = append(res, fmt.Sprintf("rate_versions.%s: added", v.ID))
+		case !reflect.DeepEqual(prev, v):
+			res = append(res, diffFields("rate_versions."+v.ID, prev, v)...)
+		}
+	}
+	for _, v := range old {
+		if !seen[v.ID] {
+			res = append(res, fmt.Sprintf("rate_versions.%s: removed", v.ID))
+		}
+	}
+	return res
+}
+
+// diffFields сравнивает поля двух структур одного типа и подписывает их ключами YAML
+func diffFields(prefix string, old, new any) []string {
+	var res []string
+	a, b := reflect.ValueOf(old), reflect.ValueOf(new)
+	for i := 0; i < a.NumField(); i++ {
+		if reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			continue
+		}
+		name, _, _ := strings.Cut(a.Type().Field(i).Tag.Get("yaml"), ",")
+		res = append(res, fmt.Sprintf("%s.%s: %v -> %v", prefix, name, a.Field(i).Interface(), b.Field(i).Interface()))
+	}
+	return res
+}
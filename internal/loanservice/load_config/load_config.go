@@ -1,40 +1,278 @@
 package loadconfig
+
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net/http"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+	"github.com/Dorji/sberInterview/internal/loanservice/storage"
 )
 
 type HTTPConfig struct {
-    Port string `yaml:"port"`
+	Port string `yaml:"port"`
 }
 
 type GRPCConfig struct {
-    Port string `yaml:"port"`
+	Port string `yaml:"port"`
+}
+
+// DebugConfig — служебные ручки (/debug/vars со счетчиками кеша и хранилища) на отдельном адресе.
+// Пустой addr — ручки выключены; на публичном HTTP-порту они не обслуживаются.
+type DebugConfig struct {
+	Addr string `yaml:"addr"` // например 127.0.0.1:6060
+}
+
+// CacheConfig — ограничения кеша расчетов, нулевые значения не ограничивают
+type CacheConfig struct {
+	MaxEntries int           `yaml:"max_entries"`
+	MaxBytes   int64         `yaml:"max_bytes"`
+	TTL        time.Duration `yaml:"ttl"`
+	Eviction   string        `yaml:"eviction"` // lru или fifo
+	Memoize    bool          `yaml:"memoize"`  // отдавать сохраненный расчет на повторный одинаковый запрос
+
+	// Каталог для журнала и снимков кеша, пустой — кеш живет только в памяти
+	PersistDir       string        `yaml:"persist_dir"`
+	SnapshotInterval time.Duration `yaml:"snapshot_interval"`
+}
+
+// Хранилища расчетов
+const (
+	StorageMemory = "memory" // LoanCache в памяти, настраивается секцией cache
+	StorageFile   = "file"   // FileStore в одном файле на диске
+)
+
+// StorageConfig выбирает хранилище расчетов, пустой backend — кеш в памяти
+type StorageConfig struct {
+	Backend string `yaml:"backend"` // memory или file
+	Path    string `yaml:"path"`    // файл хранилища для backend: file
+}
+
+// Источники ключевой ставки
+const (
+	KeyRateFile = "file" // локальный YAML-файл ставок
+	KeyRateCBR  = "cbr"  // KeyRateXML в формате Банка России
+)
+
+// defaultKeyRateTTL — сколько значение ключевой ставки живет в кеше, если ttl не задан
+const defaultKeyRateTTL = time.Hour
+
+// defaultKeyRateTimeout — таймаут запроса к Банку России, если timeout не задан:
+// без него зависший сервис держал бы все расчеты по плавающей ставке
+const defaultKeyRateTimeout = 10 * time.Second
+
+// KeyRateConfig — источник ключевой ставки для программ с rate_type: key_rate, пустой source — без источника
+type KeyRateConfig struct {
+	Source  string        `yaml:"source"`  // file или cbr
+	Path    string        `yaml:"path"`    // файл ставок для source: file
+	URL     string        `yaml:"url"`     // адрес KeyRateXML для source: cbr, по умолчанию сервис Банка России
+	Timeout time.Duration `yaml:"timeout"` // таймаут запроса для source: cbr, по умолчанию defaultKeyRateTimeout
+	TTL     time.Duration `yaml:"ttl"`     // время жизни значения в кеше
+}
+
+// AdminConfig — админский API управления программами на отдельном порту, пустой port — API выключен.
+// Программы при включенном API хранятся в programs_file и меняются через API.
+type AdminConfig struct {
+	Port      string      `yaml:"port"`       // порт для gRPC и HTTP-ручек /admin/
+	AuditPath string      `yaml:"audit_path"` // журнал изменений программ, по JSON-строке на изменение
+	Users     []AdminUser `yaml:"users"`
+}
+
+// AdminUser — пользователь админского API. Хранится только хеш токена, сам токен передается
+// в заголовке Authorization: Bearer <token>
+type AdminUser struct {
+	Name        string `yaml:"name"`
+	TokenSHA256 string `yaml:"token_sha256"` // SHA-256 токена в hex
+}
+
+// String описывает секцию для лога без хешей токенов: только имена пользователей и их число
+func (c AdminConfig) String() string {
+	names := make([]string, 0, len(c.Users))
+	for _, u := range c.Users {
+		names = append(names, u.Name)
+	}
+	return fmt.Sprintf("{Port:%s AuditPath:%s Users:%v (%d)}", c.Port, c.AuditPath, names, len(names))
 }
 
 type Config struct {
-    HTTP HTTPConfig `yaml:"http"`
-    GRPC GRPCConfig `yaml:"grpc"`
+	HTTP    HTTPConfig    `yaml:"http"`
+	GRPC    GRPCConfig    `yaml:"grpc"`
+	Debug   DebugConfig   `yaml:"debug"`
+	Cache   CacheConfig   `yaml:"cache"`
+	Storage StorageConfig `yaml:"storage"`
+	KeyRate KeyRateConfig `yaml:"key_rate"`
+	Admin   AdminConfig   `yaml:"admin"`
+
+	// Реестр программ кредитования: списком прямо в конфиге или отдельным файлом.
+	// Файл программ имеет приоритет, без обоих используются программы по умолчанию.
+	Programs     []db.Program `yaml:"programs"`
+	ProgramsFile string       `yaml:"programs_file"`
+
+	// Версии таблицы ставок с датами действия: ставки программ на дату выдачи кредита
+	RateVersions []db.RateVersion `yaml:"rate_versions"`
 }
 
 func LoadConfig(path string) (*Config, error) {
-    config := &Config{
-        HTTP: HTTPConfig{Port: "8080"},
-        GRPC: GRPCConfig{Port: "50051"},
-    }
-
-    file, err := os.ReadFile(path)
-    if err != nil {
-        return config, fmt.Errorf("error reading config file: %v, using defaults", err)
-    }
-
-    err = yaml.Unmarshal(file, config)
-    if err != nil {
-        return config, fmt.Errorf("error parsing config file: %v, using defaults", err)
-    }
-
-    return config, nil
+	config := &Config{
+		HTTP: HTTPConfig{Port: "8080"},
+		GRPC: GRPCConfig{Port: "50051"},
+	}
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("error reading config file: %v, using defaults", err)
+	}
+
+	err = yaml.Unmarshal(file, config)
+	if err != nil {
+		return config, fmt.Errorf("error parsing config file: %v, using defaults", err)
+	}
+
+	if config.ProgramsFile != "" {
+		programs, err := LoadPrograms(config.ProgramsFile)
+		if err != nil {
+			return config, err
+		}
+		config.Programs = programs
+	}
+
+	return config, nil
+}
+
+// LoadPrograms читает реестр программ из отдельного YAML-файла
+func LoadPrograms(path string) ([]db.Program, error) {
+	return db.NewFileProgramStore(path).Load()
+}
+
+// ProgramRegistry строит реестр программ и версий ставок из конфига, без программ — из программ по умолчанию
+func (c *Config) ProgramRegistry() (*db.ProgramRegistry, error) {
+	programs := c.Programs
+	if len(programs) == 0 {
+		programs = db.DefaultPrograms()
+	}
+	return db.NewProgramRegistry(programs, c.RateVersions...)
+}
+
+// Validate проверяет все секции, которые разбираются при запуске сервиса
+func (c *Config) Validate() error {
+	if _, err := c.ProgramRegistry(); err != nil {
+		return err
+	}
+	if _, err := c.CacheOptions(); err != nil {
+		return err
+	}
+	if _, err := c.StorageBackend(); err != nil {
+		return err
+	}
+	if _, err := c.AdminTokens(); err != nil {
+		return err
+	}
+	_, err := c.KeyRateProvider()
+	return err
+}
+
+// AdminTokens проверяет настройки админского API и возвращает хеши токенов по именам пользователей.
+// Без порта API выключен и возвращается nil.
+func (c *Config) AdminTokens() (map[string]string, error) {
+	admin := c.Admin
+	if admin.Port == "" {
+		return nil, nil
+	}
+	switch {
+	case c.ProgramsFile == "":
+		return nil, fmt.Errorf("admin API requires programs_file to store programs")
+	case admin.AuditPath == "":
+		return nil, fmt.Errorf("admin audit_path is required")
+	case len(admin.Users) == 0:
+		return nil, fmt.Errorf("admin API requires at least one user")
+	}
+	res := make(map[string]string, len(admin.Users))
+	for _, u := range admin.Users {
+		if u.Name == "" {
+			return nil, fmt.Errorf("admin user name is required")
+		}
+		if _, ok := res[u.Name]; ok {
+			return nil, fmt.Errorf("duplicate admin user %q", u.Name)
+		}
+		if hash, err := hex.DecodeString(u.TokenSHA256); err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("admin user %q: token_sha256 should be a hex SHA-256", u.Name)
+		}
+		res[u.Name] = u.TokenSHA256
+	}
+	return res, nil
+}
+
+// KeyRateProvider создает источник ключевой ставки с кешем и откатом на последнее полученное значение.
+// Без источника возвращает nil: программы с плавающей ставкой тогда не считаются.
+func (c *Config) KeyRateProvider() (db.KeyRateProvider, error) {
+	kr := c.KeyRate
+	if kr.Timeout < 0 || kr.TTL < 0 {
+		return nil, fmt.Errorf("key rate timeout and ttl should not be negative")
+	}
+	ttl := kr.TTL
+	if ttl == 0 {
+		ttl = defaultKeyRateTTL
+	}
+	timeout := kr.Timeout
+	if timeout == 0 {
+		timeout = defaultKeyRateTimeout
+	}
+
+	var source db.KeyRateProvider
+	switch kr.Source {
+	case "":
+		return nil, nil
+	case KeyRateFile:
+		if kr.Path == "" {
+			return nil, fmt.Errorf("key rate path is required for file source")
+		}
+		source = db.NewFileKeyRates(kr.Path)
+	case KeyRateCBR:
+		source = db.NewCBRKeyRates(kr.URL, &http.Client{Timeout: timeout})
+	default:
+		return nil, fmt.Errorf("unknown key rate source %q", kr.Source)
+	}
+	return db.NewCachedKeyRates(source, ttl), nil
+}
+
+// CacheOptions переводит настройки кеша в опции LoanCache
+func (c *Config) CacheOptions() ([]storage.CacheOption, error) {
+	cache := c.Cache
+	if cache.MaxEntries < 0 || cache.MaxBytes < 0 || cache.TTL < 0 || cache.SnapshotInterval < 0 {
+		return nil, fmt.Errorf("cache limits should not be negative")
+	}
+
+	opts := []storage.CacheOption{
+		storage.WithMaxEntries(cache.MaxEntries),
+		storage.WithMaxBytes(cache.MaxBytes),
+		storage.WithTTL(cache.TTL),
+	}
+	switch policy := storage.EvictionPolicy(cache.Eviction); policy {
+	case "":
+	case storage.EvictLRU, storage.EvictFIFO:
+		opts = append(opts, storage.WithEvictionPolicy(policy))
+	default:
+		return nil, fmt.Errorf("unknown cache eviction policy %q", cache.Eviction)
+	}
+	return opts, nil
+}
+
+// StorageBackend проверяет настройки хранилища и возвращает выбранный backend
+func (c *Config) StorageBackend() (string, error) {
+	switch c.Storage.Backend {
+	case "", StorageMemory:
+		return StorageMemory, nil
+	case StorageFile:
+		if c.Storage.Path == "" {
+			return "", fmt.Errorf("storage path is required for file backend")
+		}
+		return StorageFile, nil
+	default:
+		return "", fmt.Errorf("unknown storage backend %q", c.Storage.Backend)
+	}
 }
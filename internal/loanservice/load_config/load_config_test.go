@@ -0,0 +1,371 @@
+package loadconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+	"github.com/Dorji/sberInterview/internal/loanservice/storage"
+	"github.com/Dorji/sberInterview/internal/money"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfigPrograms(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("Defaults without programs", func(t *testing.T) {
+		path := filepath.Join(dir, "empty.yml")
+		assert.NoError(t, os.WriteFile(path, []byte("http:\n  port: \"9090\"\n"), 0o600))
+
+		config, err := LoadConfig(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "9090", config.HTTP.Port)
+		assert.Equal(t, "50051", config.GRPC.Port)
+
+		reg, err := config.ProgramRegistry()
+		assert.NoError(t, err)
+		assert.Len(t, reg.List(), 3)
+	})
+
+	t.Run("Programs file overrides inline programs", func(t *testing.T) {
+		programs := filepath.Join(dir, "programs.yml")
+		assert.NoError(t, os.WriteFile(programs, []byte(`programs:
+  - code: family
+    name: "Семейная ипотека"
+    rate_bps: 1000
+    min_initial_payment: 0.2
+    max_months: 360
+    rate_periods:
+      - months: 24
+        rate_bps: 600
+`), 0o600))
+
+		path := filepath.Join(dir, "config.yml")
+		assert.NoError(t, os.WriteFile(path, []byte(`programs_file: "`+programs+`"
+programs:
+  - code: base
+    rate_bps: 1000
+`), 0o600))
+
+		config, err := LoadConfig(path)
+		assert.NoError(t, err)
+
+		reg, err := config.ProgramRegistry()
+		assert.NoError(t, err)
+		family, ok := reg.Get("family")
+		assert.True(t, ok)
+		assert.Equal(t, int64(360), family.MaxMonths)
+		assert.Equal(t, []db.RatePeriod{{Months: 24, AnnualRate: 600}}, family.RatePeriods)
+		_, ok = reg.Get("base")
+		assert.False(t, ok)
+	})
+
+	t.Run("Missing programs file", func(t *testing.T) {
+		path := filepath.Join(dir, "broken.yml")
+		assert.NoError(t, os.WriteFile(path, []byte(`programs_file: "`+filepath.Join(dir, "nope.yml")+`"`), 0o600))
+
+		_, err := LoadConfig(path)
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadConfigCache(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("Limits and policy", func(t *testing.T) {
+		path := filepath.Join(dir, "cache.yml")
+		assert.NoError(t, os.WriteFile(path, []byte(`cache:
+  max_entries: 2
+  max_bytes: 1048576
+  ttl: 24h
+  eviction: fifo
+  memoize: true
+`), 0o600))
+
+		config, err := LoadConfig(path)
+		assert.NoError(t, err)
+		assert.Equal(t, 24*time.Hour, config.Cache.TTL)
+		assert.Equal(t, int64(1<<20), config.Cache.MaxBytes)
+		assert.True(t, config.Cache.Memoize)
+
+		opts, err := config.CacheOptions()
+		assert.NoError(t, err)
+		cache := storage.NewLoanCache(opts...)
+		for i := 0; i < 3; i++ {
+			cache.Add(&entities.LoanResult{})
+		}
+		assert.Equal(t, 2, cache.Size())
+	})
+
+	t.Run("Unknown policy", func(t *testing.T) {
+		config := &Config{Cache: CacheConfig{Eviction: "random"}}
+		_, err := config.CacheOptions()
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadConfigStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.yml")
+	assert.NoError(t, os.WriteFile(path, []byte(`storage:
+  backend: file
+  path: "data/calculations.db"
+`), 0o600))
+
+	config, err := LoadConfig(path)
+	assert.NoError(t, err)
+	backend, err := config.StorageBackend()
+	assert.NoError(t, err)
+	assert.Equal(t, StorageFile, backend)
+	assert.Equal(t, "data/calculations.db", config.Storage.Path)
+
+	backend, err = (&Config{}).StorageBackend()
+	assert.NoError(t, err)
+	assert.Equal(t, StorageMemory, backend)
+
+	_, err = (&Config{Storage: StorageConfig{Backend: StorageFile}}).StorageBackend()
+	assert.Error(t, err)
+	_, err = (&Config{Storage: StorageConfig{Backend: "redis"}}).StorageBackend()
+	assert.Error(t, err)
+}
+
+func TestLoadConfigKeyRate(t *testing.T) {
+	dir := t.TempDir()
+	rates := filepath.Join(dir, "key_rates.yml")
+	assert.NoError(t, os.WriteFile(rates, []byte("key_rates:\n  - date: 2024-07-29\n    rate: \"18.00\"\n"), 0o600))
+	path := filepath.Join(dir, "key_rate.yml")
+	assert.NoError(t, os.WriteFile(path, []byte(`key_rate:
+  source: file
+  path: "`+rates+`"
+  ttl: 10m
+`), 0o600))
+
+	config, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, config.KeyRate.TTL)
+	provider, err := config.KeyRateProvider()
+	assert.NoError(t, err)
+	rate, err := provider.KeyRate(context.Background(), time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1800), int64(rate.Rate))
+
+	provider, err = (&Config{}).KeyRateProvider()
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+
+	provider, err = (&Config{KeyRate: KeyRateConfig{Source: KeyRateCBR, URL: "http://localhost:8090/KeyRateXML"}}).KeyRateProvider()
+	assert.NoError(t, err)
+	assert.NotNil(t, provider)
+
+	_, err = (&Config{KeyRate: KeyRateConfig{Source: KeyRateFile}}).KeyRateProvider()
+	assert.Error(t, err)
+	_, err = (&Config{KeyRate: KeyRateConfig{Source: "ecb"}}).KeyRateProvider()
+	assert.Error(t, err)
+}
+
+func TestLoadConfigRateVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.yml")
+	assert.NoError(t, os.WriteFile(path, []byte(`rate_versions:
+  - id: "2024-01"
+    effective_from: 2024-01-01
+    rates:
+      salary: {rate_bps: 800}
+  - id: "2024-06"
+    effective_from: 2024-06-01T00:00:00Z
+    rates:
+      salary:
+        rate_bps: 750
+        rate_periods:
+          - months: 12
+            rate_bps: 600
+`), 0o600))
+
+	config, err := LoadConfig(path)
+	assert.NoError(t, err)
+	reg, err := config.ProgramRegistry()
+	assert.NoError(t, err)
+	assert.Len(t, reg.List(), 3, "default programs with versions")
+
+	versions := reg.Versions()
+	if assert.Len(t, versions, 2) {
+		assert.Equal(t, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), versions[0].EffectiveFrom)
+		assert.Equal(t, versions[1].EffectiveFrom, versions[0].EffectiveTo)
+		assert.Equal(t, []db.RatePeriod{{Months: 12, AnnualRate: 600}}, versions[1].Rates["salary"].RatePeriods)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := &Config{HTTP: HTTPConfig{Port: "8080"}}
+	assert.Empty(t, Diff(old, &Config{HTTP: HTTPConfig{Port: "8080"}}))
+
+	programs := db.DefaultPrograms()
+	programs[0].AnnualRate = 750
+	programs = append(programs[:2], db.Program{Code: "family", Name: "Семейная", AnnualRate: 600})
+	new := &Config{
+		HTTP:         HTTPConfig{Port: "9090"},
+		KeyRate:      KeyRateConfig{Source: KeyRateCBR},
+		Programs:     programs,
+		RateVersions: []db.RateVersion{{ID: "2024-01"}},
+	}
+	assert.Equal(t, []string{
+		"http: {Port:8080} -> {Port:9090} (restart required)",
+		"key_rate: {Source: Path: URL: Timeout:0s TTL:0s} -> {Source:cbr Path: URL: Timeout:0s TTL:0s}",
+		fmt.Sprintf("programs.%s.rate_bps: %v -> 7.50", programs[0].Code, db.DefaultPrograms()[0].AnnualRate),
+		"programs.family: added",
+		fmt.Sprintf("programs.%s: removed", db.DefaultPrograms()[2].Code),
+		"rate_versions.2024-01: added",
+	}, Diff(old, new))
+
+	// Хеши токенов в лог не попадают
+	admin := &Config{HTTP: old.HTTP, Admin: AdminConfig{Port: "9091", Users: []AdminUser{{Name: "alice", TokenSHA256: "2bb80d537b1da3e3"}}}}
+	changes := Diff(old, admin)
+	assert.Equal(t, []string{"admin: {Port: AuditPath: Users:[] (0)} -> {Port:9091 AuditPath: Users:[alice] (1)} (restart required)"}, changes)
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	programs := filepath.Join(dir, "programs.yml")
+	path := filepath.Join(dir, "config.yml")
+	writePrograms := func(rate string) {
+		assert.NoError(t, os.WriteFile(programs, []byte(`programs:
+  - code: family
+    name: "Семейная ипотека"
+    rate_bps: `+rate+`
+    min_initial_payment: 0.2
+`), 0o600))
+	}
+	writePrograms("600")
+	assert.NoError(t, os.WriteFile(path, []byte(`programs_file: "`+programs+`"`), 0o600))
+
+	config, err := LoadConfig(path)
+	assert.NoError(t, err)
+	var applied []*Config
+	reloader := NewReloader(path, config, func(old, new *Config) error {
+		applied = append(applied, new)
+		return nil
+	})
+
+	changes, err := reloader.Reload()
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Empty(t, applied, "unchanged config is not applied")
+
+	writePrograms("550")
+	changes, err = reloader.Reload()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"programs.family.rate_bps: 6.00 -> 5.50"}, changes)
+	if assert.Len(t, applied, 1) {
+		assert.Equal(t, money.BasisPoints(550), applied[0].Programs[0].AnnualRate)
+	}
+	assert.Same(t, applied[0], reloader.Current())
+
+	// Невалидная ставка: остается прежний конфиг
+	writePrograms("-1")
+	_, err = reloader.Reload()
+	assert.Error(t, err)
+	assert.Len(t, applied, 1)
+	assert.Equal(t, money.BasisPoints(550), reloader.Current().Programs[0].AnnualRate)
+
+	// Ошибка применения тоже оставляет прежний конфиг
+	failing := NewReloader(path, reloader.Current(), func(old, new *Config) error { return errors.New("apply failed") })
+	writePrograms("500")
+	_, err = failing.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, money.BasisPoints(550), failing.Current().Programs[0].AnnualRate)
+
+	t.Run("shared lock", func(t *testing.T) {
+		// Пока файл программ пишет админский API, перезагрузка ждет и видит только итог
+		lock := reloader.Locker()
+		lock.Lock()
+		done := make(chan []string)
+		go func() {
+			changes, _ := reloader.Reload()
+			done <- changes
+		}()
+		writePrograms("999")
+		select {
+		case <-done:
+			t.Fatal("reload did not wait for the lock")
+		case <-time.After(50 * time.Millisecond):
+		}
+		writePrograms("500")
+		lock.Unlock()
+		assert.Equal(t, []string{"programs.family.rate_bps: 5.50 -> 5.00"}, <-done)
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		signals := make(chan os.Signal)
+		reloaded := make(chan *Config, 4)
+		watcher := NewReloader(path, reloader.Current(), func(old, new *Config) error {
+			reloaded <- new
+			return nil
+		})
+		go watcher.Watch(ctx, 10*time.Millisecond, signals)
+
+		// Изменение файла программ подхватывается без сигнала
+		writePrograms("450")
+		future := time.Now().Add(time.Hour)
+		assert.NoError(t, os.Chtimes(programs, future, future))
+		select {
+		case c := <-reloaded:
+			assert.Equal(t, money.BasisPoints(450), c.Programs[0].AnnualRate)
+		case <-time.After(5 * time.Second):
+			t.Fatal("file change was not picked up")
+		}
+
+		writePrograms("400")
+		signals <- syscall.SIGHUP
+		select {
+		case c := <-reloaded:
+			assert.Equal(t, money.BasisPoints(400), c.Programs[0].AnnualRate)
+		case <-time.After(5 * time.Second):
+			t.Fatal("SIGHUP did not reload the config")
+		}
+	})
+}
+
+func TestLoadConfigAdmin(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "admin.yml")
+	hash := "2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b" // sha256("secret")
+	assert.NoError(t, os.WriteFile(path, []byte(`programs_file: "`+filepath.Join(dir, "programs.yml")+`"
+admin:
+  port: "8081"
+  audit_path: "audit.log"
+  users:
+    - name: pm
+      token_sha256: "`+hash+`"
+`), 0o600))
+
+	config, err := LoadConfig(path)
+	assert.Error(t, err, "programs file does not exist yet")
+	tokens, err := config.AdminTokens()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"pm": hash}, tokens)
+
+	tokens, err = (&Config{}).AdminTokens()
+	assert.NoError(t, err)
+	assert.Nil(t, tokens, "admin API is disabled without a port")
+
+	invalid := []AdminConfig{
+		{Port: "8081", AuditPath: "audit.log"},
+		{Port: "8081", Users: []AdminUser{{Name: "pm", TokenSHA256: hash}}},
+		{Port: "8081", AuditPath: "audit.log", Users: []AdminUser{{Name: "pm", TokenSHA256: "secret"}}},
+		{Port: "8081", AuditPath: "audit.log", Users: []AdminUser{{Name: "pm", TokenSHA256: hash}, {Name: "pm", TokenSHA256: hash}}},
+	}
+	for _, admin := range invalid {
+		_, err := (&Config{Admin: admin, ProgramsFile: "programs.yml"}).AdminTokens()
+		assert.Error(t, err, "%+v", admin)
+	}
+	_, err = (&Config{Admin: config.Admin}).AdminTokens()
+	assert.Error(t, err, "programs_file is required")
+}
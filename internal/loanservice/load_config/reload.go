@@ -0,0 +1,140 @@
+package loadconfig
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reloader перечитывает конфиг без перезапуска: по сигналу или при изменении файла конфига
+// и файла программ. Новый конфиг проверяется целиком и передается в apply, при любой ошибке
+// остается прежний. Изменения пишутся в лог построчно.
+type Reloader struct {
+	path  string
+	apply func(old, new *Config) error
+
+	mu      sync.Mutex
+	current *Config
+	stamps  map[string]fileStamp
+}
+
+// fileStamp — время изменения и размер файла для обнаружения правок
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewReloader создает перезагрузчик конфига path, уже загруженного как current.
+// apply подменяет настройки сервиса и вызывается только для проверенного конфига.
+func NewReloader(path string, current *Config, apply func(old, new *Config) error) *Reloader {
+	r := &Reloader{path: path, apply: apply, current: current}
+	r.stamps = r.stat()
+	return r
+}
+
+// Current возвращает действующий конфиг
+func (r *Reloader) Current() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Locker возвращает блокировку перезагрузки. Ее держит тот, кто сам пишет файл программ
+// (админский API), чтобы перезагрузка не прочитала файл посреди изменения или перед откатом.
+func (r *Reloader) Locker() sync.Locker {
+	return &r.mu
+}
+
+// Reload читает и проверяет конфиг и применяет его, если он изменился.
+// Возвращает строки изменений, при ошибке прежний конфиг остается в силе.
+func (r *Reloader) Reload() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := LoadConfig(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("reload config: %v", err)
+	}
+	if err := next.Validate(); err != nil {
+		return nil, fmt.Errorf("reload config: %v", err)
+	}
+	changes := Diff(r.current, next)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	if err := r.apply(r.current, next); err != nil {
+		return nil, fmt.Errorf("reload config: %v", err)
+	}
+	r.current = next
+	return changes, nil
+}
+
+// Watch перезагружает конфиг по каждому сигналу из signals (SIGHUP) и при изменении файлов,
+// которые проверяются раз в interval. Работает до отмены ctx.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration, signals <-chan os.Signal) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-signals:
+			log.Printf("Config reload on %v", sig)
+			r.reloadAndLog()
+		case <-ticker.C:
+			if r.changed() {
+				log.Printf("Config files changed, reloading")
+				r.reloadAndLog()
+			}
+		}
+	}
+}
+
+func (r *Reloader) reloadAndLog() {
+	changes, err := r.Reload()
+	if err != nil {
+		log.Printf("Config reload failed, keeping the previous config: %v", err)
+		return
+	}
+	if len(changes) == 0 {
+		log.Printf("Config reloaded, no changes")
+		return
+	}
+	for _, c := range changes {
+		log.Printf("Config reloaded: %s", c)
+	}
+}
+
+// changed сравнивает файлы с последней проверкой. Отметки обновляются и при неудачной
+// перезагрузке, чтобы не перечитывать один и тот же битый файл на каждом тике.
+func (r *Reloader) changed() bool {
+	stamps := r.stat()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := len(stamps) != len(r.stamps)
+	for path, s := range stamps {
+		if prev, ok := r.stamps[path]; !ok || prev != s {
+			changed = true
+		}
+	}
+	r.stamps = stamps
+	return changed
+}
+
+// stat собирает отметки файла конфига и файла программ действующего конфига
+func (r *Reloader) stat() map[string]fileStamp {
+	paths := []string{r.path}
+	if programs := r.Current().ProgramsFile; programs != "" {
+		paths = append(paths, programs)
+	}
+	res := make(map[string]fileStamp, len(paths))
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			res[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return res
+}
@@ -4,77 +4,291 @@ import (
 	"context"
 	"fmt"
 	"math"
-
-	"net/http"
+	"math/big"
+	"sync/atomic"
+	"time"
 
 	"github.com/Dorji/sberInterview/api/protos/entities"
 	"github.com/Dorji/sberInterview/api/protos/services"
 	db "github.com/Dorji/sberInterview/internal/db/storage"
 	"github.com/Dorji/sberInterview/internal/loanservice/storage"
+	"github.com/Dorji/sberInterview/internal/money"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// maxMonths ограничивает срок кредита сотней лет
+const maxMonths = 100 * 12
+
 type LoanServiceServer struct {
 	services.UnimplementedLoanServiceServer
 
-	cache *storage.LoanCache
+	repo    storage.CalculationRepository
+	rates   atomic.Pointer[rateSnapshot] // реестр программ и ключевая ставка, см. Reload
+	now     func() time.Time
+	memoize bool
+}
+
+// Option настраивает LoanServiceServer при создании
+type Option func(*LoanServiceServer)
+
+// WithClock подменяет источник текущего времени, от которого считается дата выдачи по умолчанию
+func WithClock(now func() time.Time) Option {
+	return func(ls *LoanServiceServer) {
+		ls.now = now
+	}
+}
+
+// WithPrograms задает реестр программ кредитования вместо программ по умолчанию
+func WithPrograms(programs *db.ProgramRegistry) Option {
+	return func(ls *LoanServiceServer) {
+		s := *ls.rates.Load()
+		s.programs = programs
+		ls.rates.Store(&s)
+	}
+}
+
+// WithKeyRates задает источник ключевой ставки для программ с плавающей ставкой
+func WithKeyRates(keyRates db.KeyRateProvider) Option {
+	return func(ls *LoanServiceServer) {
+		s := *ls.rates.Load()
+		s.keyRates = keyRates
+		ls.rates.Store(&s)
+	}
 }
 
-func NewLoanService(cache *storage.LoanCache) (*LoanServiceServer, error) {
-	res := &LoanServiceServer{cache: cache}
+// WithMemoization включает дедупликацию одинаковых запросов: повторный Execute
+// отдает уже сохраненный расчет с его id, а не добавляет копию в кеш
+func WithMemoization(enabled bool) Option {
+	return func(ls *LoanServiceServer) {
+		ls.memoize = enabled
+	}
+}
+
+// NewLoanService создает сервис поверх хранилища расчетов: LoanCache в памяти, FileStore на диске или мок в тестах
+func NewLoanService(repo storage.CalculationRepository, opts ...Option) (*LoanServiceServer, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("NewLoanService:nil repository")
+	}
+	res := &LoanServiceServer{repo: repo, now: time.Now}
+	res.rates.Store(&rateSnapshot{programs: db.DefaultProgramRegistry()})
+	for _, opt := range opts {
+		opt(res)
+	}
 	return res, nil
 }
 
 func (ls *LoanServiceServer) Execute(ctx context.Context, req *entities.LoanRequest) (*entities.LoanResult, error) {
-	// Параметры из примера
-	if float64(req.InitialPayment)/float64(req.ObjectCost) < db.InitialPayment {
-		return nil, status.Errorf(http.StatusBadRequest, "the initial payment should be more")
+	ctx = ls.pin(ctx)
+	// Программа и ставка определяются один раз: и для ключа мемоизации, и для расчета
+	program, err := ls.validateRequest(ctx, req)
+	if err != nil {
+		return nil, err
 	}
-	loanSum := req.ObjectCost - req.InitialPayment // Сумма кредита
-	annualRate, err := db.GetAnnualRate(req.Program)
+	loan, err := ls.priceLoan(ctx, req, program)
 	if err != nil {
 		return nil, err
 	}
-	termMonths := req.Months // Срок
-	// Расчет платежа
-	monthlyPayment, err := ls.calculateMonthlyPayment(loanSum, annualRate, termMonths)
+	var key string
+	if ls.memoize {
+		key = requestKey(req, loan)
+		// Ошибка поиска не мешает посчитать заново, мемоизация — только оптимизация
+		if res, err := ls.repo.FindByKey(key); err == nil {
+			// График в кеше не хранится, для дифференцированных платежей и ступенчатой ставки он строится заново
+			if returnsSchedule(res) {
+				_, rows, err := ls.calculatePriced(req, loan)
+				if err != nil {
+					return nil, err
+				}
+				res.Schedule = scheduleProto(rows)
+			}
+			return res, nil
+		}
+	}
+
+	res, rows, err := ls.calculatePriced(req, loan)
 	if err != nil {
 		return nil, err
 	}
 
-	// Расчет переплаты
-	totalPayment := monthlyPayment * termMonths
-	overpayment := totalPayment - loanSum
+	res.CreatedAt = timestamppb.New(ls.now())
+	// В кеш график не пишем, чтобы не раздувать его: он добавляется уже после сохранения копии
+	if res.Id, err = ls.repo.Save(key, res); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save calculation: %v", err)
+	}
+
+	if returnsSchedule(res) {
+		res.Schedule = scheduleProto(rows)
+	}
+	return res, nil
+}
+
+// returnsSchedule сообщает, отдает ли Execute график: платеж меняется от месяца к месяцу
+// при дифференцированных платежах и ступенчатой ставке
+func returnsSchedule(res *entities.LoanResult) bool {
+	return res.PaymentType == entities.PaymentType_DIFFERENTIATED || len(res.GetAggregates().GetRatePeriods()) > 0
+}
+
+// calculate считает агрегаты и график кредита без сохранения в кеш
+func (ls *LoanServiceServer) calculate(ctx context.Context, req *entities.LoanRequest) (*entities.LoanResult, []scheduleRow, error) {
+	program, err := ls.validateRequest(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ls.calculateProgram(ctx, req, program)
+}
+
+// calculateProgram считает кредит по уже проверенным параметрам и программе.
+// Ставка берется из версии таблицы ставок на дату выдачи, плавающая — от ключевой ставки на эту дату.
+func (ls *LoanServiceServer) calculateProgram(ctx context.Context, req *entities.LoanRequest, program db.Program) (*entities.LoanResult, []scheduleRow, error) {
+	loan, err := ls.priceLoan(ctx, req, program)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ls.calculatePriced(req, loan)
+}
+
+// calculatePriced считает кредит по запросу, для которого уже определены календарь, программа и ставка
+func (ls *LoanServiceServer) calculatePriced(req *entities.LoanRequest, loan pricedLoan) (*entities.LoanResult, []scheduleRow, error) {
+	program, pricing, calendar := loan.program, loan.pricing, loan.calendar
+	loanSum, err := money.FromRublesChecked(req.ObjectCost - req.InitialPayment) // Сумма кредита
+	if err != nil {
+		return nil, nil, fmt.Errorf("calculate:%v", err)
+	}
+	rates := loanRates(req, program)
+	annualRate := rates[0].rate
+	termMonths := req.Months // Срок
+
+	var rows []scheduleRow
+	switch {
+	case len(rates) > 1:
+		// Ступенчатая ставка: график с пересчетом платежа в начале каждого периода
+		rows, err = ls.buildPrepaymentSchedule(loanSum, rates, termMonths, req.PaymentType, calendar, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+	case req.PaymentType == entities.PaymentType_DIFFERENTIATED:
+		rows, err = ls.buildDifferentiatedSchedule(loanSum, annualRate, termMonths, calendar)
+		if err != nil {
+			return nil, nil, err
+		}
+	default:
+		// Расчет платежа
+		monthlyPayment, err := ls.calculateMonthlyPayment(loanSum, annualRate, termMonths)
+		if err != nil {
+			return nil, nil, err
+		}
+		rows, err = ls.buildAnnuitySchedule(loanSum, annualRate, termMonths, monthlyPayment, calendar)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	res := &entities.LoanResult{
-		Params: &entities.LoanParams{
-			ObjectCost:     req.ObjectCost,
-			InitialPayment: req.InitialPayment,
-			Months:         req.Months,
-		},
-		Program: req.Program,
-		Aggregates: &entities.LoanAggregates{
-			Rate:            int64(annualRate * 100),
-			LoanSum:         loanSum,
-			MonthlyPayment:  monthlyPayment,
-			Overpayment:     overpayment,
-			LastPaymentDate: &timestamppb.Timestamp{},
+		Params:      loanParams(req, calendar),
+		Program:     req.Program,
+		Aggregates:  scheduleAggregates(loanSum, rates, rows),
+		PaymentType: req.PaymentType,
+	}
+	res.RateVersion = pricing.rateVersion
+	res.Aggregates.KeyRate = pricing.keyRate
+	if err := addFullCost(res.Aggregates, req.Costs, loanSum, rows, calendar); err != nil {
+		return nil, nil, err
+	}
+	return res, rows, nil
+}
+
+// scheduleAggregates сводит график в агрегаты: переплата — сумма процентов, рублевые поля округлены из точных сумм.
+// Ставкой агрегатов служит ставка первого платежа, для ступенчатой ставки добавляются итоги периодов.
+func scheduleAggregates(loanSum money.Money, rates rateSchedule, rows []scheduleRow) *entities.LoanAggregates {
+	annualRate := rates[0].rate
+	// Расчет переплаты
+	var overpayment, totalPayment money.Money
+	for _, row := range rows {
+		overpayment += row.interest
+		totalPayment += row.payment + row.prepayment
+	}
+	first, last := rows[0], rows[len(rows)-1]
+	return &entities.LoanAggregates{
+		Rate:            annualRate.Percent(),
+		RateBps:         int64(annualRate),
+		LoanSum:         loanSum.Rubles(money.HalfUp),
+		MonthlyPayment:  first.payment.Rubles(money.Ceil),
+		Overpayment:     overpayment.Rubles(money.HalfUp),
+		LastPaymentDate: timestamppb.New(last.date),
+		FirstPayment:    first.payment.Rubles(money.Ceil),
+		LastPayment:     last.payment.Rubles(money.Ceil),
+		Exact: &entities.ExactAmounts{
+			LoanSum:        loanSum.Proto(),
+			MonthlyPayment: first.payment.Proto(),
+			Overpayment:    overpayment.Proto(),
+			FirstPayment:   first.payment.Proto(),
+			LastPayment:    last.payment.Proto(),
+			TotalPayment:   totalPayment.Proto(),
 		},
+		RatePeriods: ratePeriodsProto(rates, rows),
+	}
+}
+
+// loanParams возвращает параметры кредита с датой выдачи и днем платежа после подстановки значений по умолчанию
+func loanParams(req *entities.LoanRequest, calendar paymentCalendar) *entities.LoanParams {
+	return &entities.LoanParams{
+		ObjectCost:     req.ObjectCost,
+		InitialPayment: req.InitialPayment,
+		Months:         req.Months,
+		IssueDate:      timestamppb.New(calendar.issue),
+		PaymentDay:     int32(calendar.day),
+		Costs:          req.Costs,
+	}
+}
+
+// Cache отдает страницу расчетов из кеша с фильтрами и сортировкой
+func (ls *LoanServiceServer) Cache(ctx context.Context, req *entities.CacheRequest) (*entities.CacheResult, error) {
+	if ls.repo.Size() == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "empty cache")
+	}
+	q, err := cacheQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	results, next, err := ls.repo.List(q)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list calculations: %v", err)
+	}
+	return &entities.CacheResult{
+		Results:       results,
+		NextPageToken: encodePageToken(next, req.Sort, req.Desc),
+	}, nil
+}
+
+// GetCalculation возвращает расчет из кеша по id
+func (ls *LoanServiceServer) GetCalculation(ctx context.Context, req *entities.CalculationID) (*entities.LoanResult, error) {
+	if err := validateID(req.Id); err != nil {
+		return nil, err
+	}
+	res, err := ls.repo.Get(req.Id)
+	if err != nil {
+		return nil, cacheError(req.Id, err)
 	}
-	ls.cache.Add(res)
 	return res, nil
 }
 
-func (ls *LoanServiceServer) Cache(context.Context, *emptypb.Empty) (*entities.CacheResult, error) {
-	all := ls.cache.GetAll()
-	if len(all.Results) == 0 {
-		return nil, status.Errorf(http.StatusBadRequest, "empty cache")
+// DeleteCalculation удаляет расчет из кеша по id
+func (ls *LoanServiceServer) DeleteCalculation(ctx context.Context, req *entities.CalculationID) (*emptypb.Empty, error) {
+	if err := validateID(req.Id); err != nil {
+		return nil, err
+	}
+	if err := ls.repo.Delete(req.Id); err != nil {
+		return nil, cacheError(req.Id, err)
 	}
-	return all, nil
+	return &emptypb.Empty{}, nil
 }
 
-func (ls *LoanServiceServer) calculateMonthlyPayment(loanSum int64, annualRate float64, months int64) (int64, error) {
+// calculateMonthlyPayment считает аннуитетный платеж в точных дробях
+// и округляет его до копейки по математическим правилам.
+func (ls *LoanServiceServer) calculateMonthlyPayment(loanSum money.Money, annualRate money.BasisPoints, months int64) (money.Money, error) {
 	// Проверка граничных условий
 	if loanSum <= 0 {
 		return 0, fmt.Errorf("calculateMonthlyPayment:Zero loan sum")
@@ -90,55 +304,46 @@ func (ls *LoanServiceServer) calculateMonthlyPayment(loanSum int64, annualRate f
 	if months == math.MaxInt64 {
 		return 0, fmt.Errorf("calculateMonthlyPayment:months is MaxInt64")
 	}
-	if annualRate < 0.00 {
+	if months > maxMonths {
+		return 0, fmt.Errorf("calculateMonthlyPayment:months too large")
+	}
+	if annualRate < 0 {
 		return 0, fmt.Errorf("calculateMonthlyPayment:rate less than 0.00")
 	}
 
-	if annualRate == 0.00 {
+	if annualRate == 0 {
 		return 0, fmt.Errorf("calculateMonthlyPayment:installment plan")
 	}
 
-	// Конвертируем годовую ставку в месячную
-	monthlyRate := annualRate / 12
-
-	// Защита от деления на ноль и переполнения
-	denominator := math.Pow(1+monthlyRate, float64(months)) - 1
-	if denominator <= 0 {
-		return 0, fmt.Errorf("calculateMonthlyPayment:denominator less than 0")
+	payment, err := loanSum.Mul(annuityFactor(annualRate, months), money.HalfUp)
+	if err != nil {
+		return 0, fmt.Errorf("calculateMonthlyPayment:%v", err)
 	}
-
-	// Рассчитываем платеж по формуле аннуитета
-	payment := float64(loanSum) * ((monthlyRate * math.Pow(1+monthlyRate, float64(months))) / denominator)
-
-	return ls.RoundNotNegative(payment)
+	return payment, nil
 }
 
-func (ls *LoanServiceServer) RoundNotNegative(num float64) (int64, error) {
+// annuityFactor возвращает долю суммы кредита в аннуитетном платеже: r * (1+r)^n / ((1+r)^n - 1)
+func annuityFactor(annualRate money.BasisPoints, months int64) *big.Rat {
+	// Конвертируем годовую ставку в месячную
+	monthlyRate := annualRate.Monthly()
 
-	if num < 0 {
-		return 0, fmt.Errorf("LoanServiceServer: num is negative")
-	}
+	growth := powRat(new(big.Rat).Add(big.NewRat(1, 1), monthlyRate), months)
+	denominator := new(big.Rat).Sub(growth, big.NewRat(1, 1))
+	factor := new(big.Rat).Mul(monthlyRate, growth)
+	return factor.Quo(factor, denominator)
+}
 
-	if math.IsNaN(num) {
-		return 0, fmt.Errorf("LoanServiceServer:NAN")
-	}
-	if math.IsInf(num, 0) {
-		if num > 0 {
-			return 0, fmt.Errorf("LoanServiceServer:round num overflow")
+// powRat возводит дробь в целую неотрицательную степень
+func powRat(x *big.Rat, n int64) *big.Rat {
+	res := big.NewRat(1, 1)
+	base := new(big.Rat).Set(x)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			res.Mul(res, base)
 		}
-		return 0, fmt.Errorf("LoanServiceServer:round num overflow")
-	}
-	if math.IsInf(num, -1) {
-		if num < 0 {
-			return 0, fmt.Errorf("LoanServiceServer: num is negative")
+		if n > 1 {
+			base.Mul(base, base)
 		}
-		return 0, fmt.Errorf("LoanServiceServer: num is negative")
 	}
-
-	// Проверка на переполнение int64
-	if num >= float64(math.MaxInt64) {
-		return 0, fmt.Errorf("LoanServiceServer:round num overflow")
-	}
-
-	return int64(math.Ceil(num)), nil
+	return res
 }
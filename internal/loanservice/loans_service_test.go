@@ -0,0 +1,1766 @@
+package loanservice_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+	"github.com/Dorji/sberInterview/internal/loanservice"
+	"github.com/Dorji/sberInterview/internal/loanservice/interceptors"
+	"github.com/Dorji/sberInterview/internal/loanservice/storage"
+	"github.com/Dorji/sberInterview/internal/money"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestLoanService_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     *entities.LoanRequest
+		wantErr     bool
+		expectedErr codes.Code
+	}{
+		{
+			name: "Valid request",
+			request: &entities.LoanRequest{
+				ObjectCost:     1_000_000,
+				InitialPayment: 200_000,
+				Months:         12,
+				Program:        &entities.LoanProgram{Base: true},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Initial payment too low",
+			request: &entities.LoanRequest{
+				ObjectCost:     1_000_000,
+				InitialPayment: 50_000, // Меньше минимального
+				Months:         12,
+				Program:        &entities.LoanProgram{Base: true},
+			},
+			wantErr:     true,
+			expectedErr: codes.InvalidArgument,
+		},
+		{
+			name: "Invalid program",
+			request: &entities.LoanRequest{
+				ObjectCost:     1_000_000,
+				InitialPayment: 200_000,
+				Months:         12,
+				Program:        &entities.LoanProgram{},
+			},
+			wantErr:     true,
+			expectedErr: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, err := loanservice.NewLoanService(storage.NewLoanCache())
+			assert.NoError(t, err)
+
+			resp, err := service.Execute(context.Background(), tt.request)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if st, ok := status.FromError(err); ok {
+					assert.Equal(t, tt.expectedErr, st.Code())
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, resp)
+				assert.Equal(t, tt.request.Program, resp.Program)
+				assert.Equal(t, tt.request.ObjectCost, resp.Params.ObjectCost)
+				assert.True(t, resp.Aggregates.MonthlyPayment > 0)
+			}
+		})
+	}
+}
+
+func TestLoanService_Cache(t *testing.T) {
+	t.Run("Empty cache", func(t *testing.T) {
+		service, err := loanservice.NewLoanService(storage.NewLoanCache())
+		assert.NoError(t, err)
+
+		_, err = service.Cache(context.Background(), &entities.CacheRequest{})
+		assert.Error(t, err)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("With items in cache", func(t *testing.T) {
+		cache := storage.NewLoanCache()
+		service, err := loanservice.NewLoanService(cache)
+		assert.NoError(t, err)
+
+		// Добавляем тестовые данные
+		_, err = service.Execute(context.Background(), &entities.LoanRequest{
+			ObjectCost:     1_000_000,
+			InitialPayment: 200_000,
+			Months:         12,
+			Program:        &entities.LoanProgram{Base: true},
+		})
+		assert.NoError(t, err)
+
+		resp, err := service.Cache(context.Background(), &entities.CacheRequest{})
+		assert.NoError(t, err)
+		assert.Len(t, resp.Results, 1)
+	})
+}
+
+func TestLoanService_CachePagination(t *testing.T) {
+	service, err := loanservice.NewLoanService(storage.NewLoanCache())
+	assert.NoError(t, err)
+
+	for _, months := range []int64{12, 60, 120, 240, 360} {
+		_, err := service.Execute(context.Background(), &entities.LoanRequest{
+			ObjectCost:     5_000_000,
+			InitialPayment: 1_000_000,
+			Months:         months,
+			Program:        &entities.LoanProgram{Salary: true},
+		})
+		assert.NoError(t, err)
+	}
+
+	// Самый большой платеж у самого короткого срока
+	req := &entities.CacheRequest{MinMonths: 60, Sort: entities.CacheSort_MONTHLY_PAYMENT, Desc: true, PageSize: 2}
+	var months []int64
+	for {
+		resp, err := service.Cache(context.Background(), req)
+		if !assert.NoError(t, err) {
+			return
+		}
+		for _, r := range resp.Results {
+			months = append(months, r.Params.Months)
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+	assert.Equal(t, []int64{60, 120, 240, 360}, months)
+
+	t.Run("Token from another sort order", func(t *testing.T) {
+		first, err := service.Cache(context.Background(), &entities.CacheRequest{PageSize: 1})
+		assert.NoError(t, err)
+		_, err = service.Cache(context.Background(), &entities.CacheRequest{
+			PageSize:  1,
+			Sort:      entities.CacheSort_MONTHLY_PAYMENT,
+			PageToken: first.NextPageToken,
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		_, err := service.Cache(context.Background(), &entities.CacheRequest{
+			MinLoanSum: 2_000_000,
+			MaxLoanSum: 1_000_000,
+			PageSize:   5000,
+			PageToken:  "not a token",
+		})
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		if assert.Len(t, st.Details(), 1) {
+			badRequest := st.Details()[0].(*errdetails.BadRequest)
+			assert.Len(t, badRequest.FieldViolations, 3)
+		}
+	})
+
+	t.Run("No matches is not an error", func(t *testing.T) {
+		resp, err := service.Cache(context.Background(), &entities.CacheRequest{Program: &entities.LoanProgram{Code: "family"}})
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Results)
+	})
+}
+
+func TestLoanService_GetCalculation(t *testing.T) {
+	service, err := loanservice.NewLoanService(storage.NewLoanCache())
+	assert.NoError(t, err)
+
+	req := &entities.LoanRequest{
+		ObjectCost:     1_000_000,
+		InitialPayment: 200_000,
+		Months:         12,
+		Program:        &entities.LoanProgram{Base: true},
+	}
+	first, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	second, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), first.Id)
+	assert.Equal(t, int64(1), second.Id)
+
+	got, err := service.GetCalculation(context.Background(), &entities.CalculationID{Id: second.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, second.Id, got.Id)
+	assert.Equal(t, second.Aggregates.MonthlyPayment, got.Aggregates.MonthlyPayment)
+
+	_, err = service.DeleteCalculation(context.Background(), &entities.CalculationID{Id: second.Id})
+	assert.NoError(t, err)
+
+	_, err = service.GetCalculation(context.Background(), &entities.CalculationID{Id: second.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = service.DeleteCalculation(context.Background(), &entities.CalculationID{Id: second.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = service.GetCalculation(context.Background(), &entities.CalculationID{Id: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	all, err := service.Cache(context.Background(), &entities.CacheRequest{})
+	assert.NoError(t, err)
+	if assert.Len(t, all.Results, 1) {
+		assert.Equal(t, first.Id, all.Results[0].Id)
+	}
+}
+
+func TestLoanService_GetCalculationExpired(t *testing.T) {
+	service, err := loanservice.NewLoanService(storage.NewLoanCache(storage.WithMaxEntries(1)))
+	assert.NoError(t, err)
+
+	req := &entities.LoanRequest{
+		ObjectCost:     1_000_000,
+		InitialPayment: 200_000,
+		Months:         12,
+		Program:        &entities.LoanProgram{Base: true},
+	}
+	first, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	_, err = service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+
+	reason := func(err error) string {
+		for _, detail := range status.Convert(err).Details() {
+			if info, ok := detail.(*errdetails.ErrorInfo); ok {
+				return info.Reason
+			}
+		}
+		return ""
+	}
+
+	_, err = service.GetCalculation(context.Background(), &entities.CalculationID{Id: first.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "calculation 0 expired", status.Convert(err).Message())
+	assert.Equal(t, loanservice.ReasonExpired, reason(err))
+
+	_, err = service.GetCalculation(context.Background(), &entities.CalculationID{Id: 10})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, loanservice.ReasonNotFound, reason(err))
+}
+
+func TestLoanService_ExecuteMemoization(t *testing.T) {
+	today := time.Date(2024, time.February, 18, 0, 0, 0, 0, time.UTC)
+	cache := storage.NewLoanCache()
+	service, err := loanservice.NewLoanService(cache,
+		loanservice.WithMemoization(true),
+		loanservice.WithClock(func() time.Time { return today }),
+	)
+	assert.NoError(t, err)
+
+	req := &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Salary: true},
+	}
+	first, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+
+	// Тот же запрос с программой по коду и явной датой выдачи — тот же расчет
+	same, err := service.Execute(context.Background(), &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Code: "salary"},
+		IssueDate:      timestamppb.New(today),
+		PaymentDay:     18,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, first.Id, same.Id)
+	assert.Equal(t, first.Aggregates.MonthlyPayment, same.Aggregates.MonthlyPayment)
+	assert.Equal(t, 1, cache.Size())
+
+	// Другая схема погашения — другой расчет, график отдается и при повторе
+	req.PaymentType = entities.PaymentType_DIFFERENTIATED
+	diff, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.Id, diff.Id)
+	again, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, diff.Id, again.Id)
+	assert.Len(t, again.Schedule, 240)
+
+	stats := cache.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, int64(2), stats.MemoHits)
+	assert.Equal(t, int64(2), stats.MemoMisses)
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		cache := storage.NewLoanCache()
+		service, err := loanservice.NewLoanService(cache)
+		assert.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			_, err := service.Execute(context.Background(), req)
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 2, cache.Size())
+	})
+}
+
+func TestLoanService_Schedule(t *testing.T) {
+	cache := storage.NewLoanCache()
+	service, err := loanservice.NewLoanService(cache)
+	assert.NoError(t, err)
+
+	req := &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Salary: true},
+	}
+
+	resp, err := service.Schedule(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Rows, 240)
+
+	var interest, total money.Money
+	for _, row := range resp.Rows {
+		interest += money.FromProto(row.Interest)
+		total += money.FromProto(row.Payment)
+	}
+	assert.Equal(t, money.FromProto(resp.Aggregates.Exact.Overpayment), interest)
+	// График в сумме дает ровно долг плюс проценты
+	assert.Equal(t, money.FromRubles(resp.Aggregates.LoanSum)+interest, total)
+
+	executed, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, executed.Aggregates.Overpayment, resp.Aggregates.Overpayment)
+
+	// График не попадает в кеш, туда пишет только Execute
+	assert.Equal(t, 1, cache.Size())
+}
+
+func TestLoanService_ExecuteDifferentiated(t *testing.T) {
+	cache := storage.NewLoanCache()
+	service, err := loanservice.NewLoanService(cache)
+	assert.NoError(t, err)
+
+	req := &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Salary: true},
+		PaymentType:    entities.PaymentType_DIFFERENTIATED,
+	}
+
+	resp, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.PaymentType_DIFFERENTIATED, resp.PaymentType)
+	assert.Len(t, resp.Schedule, 240)
+	assert.Greater(t, resp.Aggregates.FirstPayment, resp.Aggregates.LastPayment)
+
+	var interest money.Money
+	for _, row := range resp.Schedule {
+		interest += money.FromProto(row.Interest)
+	}
+	assert.Equal(t, money.FromProto(resp.Aggregates.Exact.Overpayment), interest)
+
+	// Дифференцированная схема дешевле аннуитета на тех же условиях
+	req.PaymentType = entities.PaymentType_ANNUITY
+	annuity, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Empty(t, annuity.Schedule)
+	assert.Less(t, resp.Aggregates.Overpayment, annuity.Aggregates.Overpayment)
+
+	cached := cache.GetAll().Results
+	assert.Len(t, cached, 2)
+	assert.Equal(t, entities.PaymentType_DIFFERENTIATED, cached[0].PaymentType)
+	assert.Empty(t, cached[0].Schedule)
+	assert.Equal(t, entities.PaymentType_ANNUITY, cached[1].PaymentType)
+}
+
+func TestLoanService_ExecuteLastPaymentDate(t *testing.T) {
+	today := time.Date(2024, time.February, 18, 12, 0, 0, 0, time.UTC)
+	service, err := loanservice.NewLoanService(storage.NewLoanCache(), loanservice.WithClock(func() time.Time { return today }))
+	assert.NoError(t, err)
+
+	resp, err := service.Execute(context.Background(), &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Salary: true},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2044, time.February, 18, 0, 0, 0, 0, time.UTC), resp.Aggregates.LastPaymentDate.AsTime())
+	assert.Equal(t, int32(18), resp.Params.PaymentDay)
+}
+
+func TestLoanService_ExecuteRateBasisPoints(t *testing.T) {
+	service, err := loanservice.NewLoanService(storage.NewLoanCache())
+	assert.NoError(t, err)
+
+	resp, err := service.Execute(context.Background(), &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Military: true},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), resp.Aggregates.Rate)
+	assert.Equal(t, int64(900), resp.Aggregates.RateBps)
+}
+
+func TestLoanService_ExecuteFullCost(t *testing.T) {
+	service, err := loanservice.NewLoanService(storage.NewLoanCache())
+	assert.NoError(t, err)
+
+	req := &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Salary: true},
+		IssueDate:      timestamppb.New(time.Date(2024, time.February, 18, 0, 0, 0, 0, time.UTC)),
+	}
+	plain, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.InDelta(t, plain.Aggregates.RateBps*10, plain.Aggregates.FullCostRate, 1, "without costs the full cost rate is the nominal rate")
+	assert.Equal(t, plain.Aggregates.Exact.Overpayment, plain.Aggregates.FullCost)
+
+	req.Costs = &entities.LoanCosts{
+		OneOffFee:        money.FromRubles(20_000).Proto(),
+		MonthlyFee:       money.FromRubles(500).Proto(),
+		InsuranceRateBps: 50,
+	}
+	resp, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, plain.Aggregates.Exact.Overpayment, resp.Aggregates.Exact.Overpayment, "costs do not change the schedule")
+	assert.Greater(t, resp.Aggregates.FullCostRate, plain.Aggregates.FullCostRate)
+	assert.Equal(t, req.Costs, resp.Params.Costs)
+
+	costs := resp.Aggregates.Costs
+	assert.Equal(t, money.FromRubles(20_000), money.FromProto(costs.OneOffFees))
+	assert.Equal(t, money.FromRubles(500*240), money.FromProto(costs.MonthlyFees))
+	// Первая премия — 0,5% от всей суммы кредита, дальше — от остатка на начало года
+	assert.Greater(t, money.FromProto(costs.Insurance), money.FromRubles(20_000))
+	assert.Equal(t, money.FromProto(costs.OneOffFees)+money.FromProto(costs.MonthlyFees)+money.FromProto(costs.Insurance), money.FromProto(costs.Total))
+	assert.Equal(t, money.FromProto(resp.Aggregates.Exact.Overpayment)+money.FromProto(costs.Total), money.FromProto(resp.Aggregates.FullCost))
+}
+
+func TestLoanService_ExecuteSteppedRate(t *testing.T) {
+	programs, err := db.NewProgramRegistry(append(db.DefaultPrograms(), db.Program{
+		Code:              "teaser",
+		Name:              "Льготная ставка на два года",
+		AnnualRate:        1000,
+		MinInitialPayment: 0.20,
+		RatePeriods:       []db.RatePeriod{{Months: 24, AnnualRate: 600}},
+	}))
+	assert.NoError(t, err)
+	service, err := loanservice.NewLoanService(storage.NewLoanCache(), loanservice.WithPrograms(programs))
+	assert.NoError(t, err)
+
+	req := &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Code: "teaser"},
+	}
+	resp, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(600), resp.Aggregates.RateBps)
+	if assert.Len(t, resp.Aggregates.RatePeriods, 2) {
+		teaser, after := resp.Aggregates.RatePeriods[0], resp.Aggregates.RatePeriods[1]
+		assert.Equal(t, []int64{1, 24, 600}, []int64{teaser.FromMonth, teaser.ToMonth, teaser.RateBps})
+		assert.Equal(t, []int64{25, 240, 1000}, []int64{after.FromMonth, after.ToMonth, after.RateBps})
+		assert.Greater(t, money.FromProto(after.Payment), money.FromProto(teaser.Payment))
+		assert.Equal(t, money.FromProto(resp.Aggregates.Exact.Overpayment), money.FromProto(teaser.Interest)+money.FromProto(after.Interest))
+	}
+	assert.Len(t, resp.Schedule, 240, "stepped rate returns the schedule")
+	// ПСК между льготной и основной ставкой
+	assert.Greater(t, resp.Aggregates.FullCostRate, int64(6_000))
+	assert.Less(t, resp.Aggregates.FullCostRate, int64(10_000))
+
+	fixed, err := service.Execute(context.Background(), &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Base: true},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, fixed.Aggregates.RatePeriods)
+	assert.Less(t, resp.Aggregates.Overpayment, fixed.Aggregates.Overpayment)
+
+	t.Run("Request periods replace program periods", func(t *testing.T) {
+		req := &entities.LoanRequest{
+			ObjectCost:     5_000_000,
+			InitialPayment: 1_000_000,
+			Months:         240,
+			Program:        &entities.LoanProgram{Code: "teaser"},
+			RatePeriods:    []*entities.RatePeriod{{Months: 12, RateBps: 300}, {Months: 12, RateBps: 500}},
+		}
+		resp, err := service.Schedule(context.Background(), req)
+		assert.NoError(t, err)
+		if assert.Len(t, resp.Aggregates.RatePeriods, 3) {
+			assert.Equal(t, int64(13), resp.Aggregates.RatePeriods[1].FromMonth)
+			assert.Equal(t, int64(1000), resp.Aggregates.RatePeriods[2].RateBps)
+		}
+		assert.Equal(t, resp.Rows[12].Payment, resp.Aggregates.RatePeriods[1].Payment)
+	})
+
+	t.Run("Prepayment keeps the rate periods", func(t *testing.T) {
+		prepaid, err := service.Prepay(context.Background(), &entities.PrepaymentRequest{
+			Loan: &entities.PrepaymentRequest_CalculationId{CalculationId: resp.Id},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, resp.Aggregates.Exact.Overpayment, prepaid.Aggregates.Exact.Overpayment)
+		assert.Len(t, prepaid.Aggregates.RatePeriods, 2)
+	})
+}
+
+// keyRateFunc — источник ключевой ставки из функции
+type keyRateFunc func(ctx context.Context, date time.Time) (db.KeyRate, error)
+
+func (f keyRateFunc) KeyRate(ctx context.Context, date time.Time) (db.KeyRate, error) {
+	return f(ctx, date)
+}
+
+func TestLoanService_ExecuteFloatingRate(t *testing.T) {
+	programs, err := db.NewProgramRegistry(append(db.DefaultPrograms(), db.Program{
+		Code:              "floating",
+		Name:              "Ключевая ставка + 2%",
+		RateType:          db.RateKeyRate,
+		Spread:            200,
+		MinInitialPayment: 0.20,
+	}))
+	assert.NoError(t, err)
+
+	keyRate := db.KeyRate{Rate: 1600, Date: time.Date(2023, time.December, 18, 0, 0, 0, 0, time.UTC)}
+	var keyRateErr error
+	var requested time.Time
+	var calls int
+	provider := keyRateFunc(func(ctx context.Context, date time.Time) (db.KeyRate, error) {
+		requested = date
+		calls++
+		return keyRate, keyRateErr
+	})
+	service, err := loanservice.NewLoanService(storage.NewLoanCache(),
+		loanservice.WithPrograms(programs),
+		loanservice.WithKeyRates(provider),
+		loanservice.WithMemoization(true),
+	)
+	assert.NoError(t, err)
+
+	req := &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Code: "floating"},
+		IssueDate:      timestamppb.New(time.Date(2024, time.February, 18, 0, 0, 0, 0, time.UTC)),
+	}
+	resp, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, time.February, 18, 0, 0, 0, 0, time.UTC), requested, "key rate on the issue date")
+	assert.Equal(t, 1, calls, "key and calculation share one key rate")
+	assert.Equal(t, int64(1800), resp.Aggregates.RateBps)
+	if assert.NotNil(t, resp.Aggregates.KeyRate) {
+		assert.Equal(t, int64(1600), resp.Aggregates.KeyRate.KeyRateBps)
+		assert.Equal(t, int64(200), resp.Aggregates.KeyRate.SpreadBps)
+		assert.Equal(t, keyRate.Date, resp.Aggregates.KeyRate.Date.AsTime())
+		assert.False(t, resp.Aggregates.KeyRate.Stale)
+	}
+
+	// Новая ключевая ставка не отдает старый расчет из мемоизации
+	keyRate = db.KeyRate{Rate: 1800, Date: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)}
+	updated, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.NotEqual(t, resp.Id, updated.Id)
+	assert.Equal(t, int64(2000), updated.Aggregates.RateBps)
+
+	// Ставка меняется между вызовами источника: ключ и расчет все равно видят одну
+	calls = 0
+	provider = keyRateFunc(func(ctx context.Context, date time.Time) (db.KeyRate, error) {
+		calls++
+		return db.KeyRate{Rate: keyRate.Rate + money.BasisPoints(calls*100), Date: keyRate.Date}, nil
+	})
+	flapping, err := loanservice.NewLoanService(storage.NewLoanCache(),
+		loanservice.WithPrograms(programs),
+		loanservice.WithKeyRates(provider),
+		loanservice.WithMemoization(true),
+	)
+	assert.NoError(t, err)
+	first, err := flapping.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2100), first.Aggregates.RateBps)
+	// Второй расчет идет по новой ставке, а не отдает из мемоизации расчет по ключу с другой
+	second, err := flapping.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.Id, second.Id)
+	assert.Equal(t, int64(2200), second.Aggregates.RateBps)
+	assert.Equal(t, 2, calls)
+
+	fixed, err := service.Execute(context.Background(), &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Base: true},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, fixed.Aggregates.KeyRate)
+
+	t.Run("Key rate source fails", func(t *testing.T) {
+		keyRateErr = errors.New("connection refused")
+		defer func() { keyRateErr = nil }()
+		_, err := service.Execute(context.Background(), req)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("No key rate source", func(t *testing.T) {
+		service, err := loanservice.NewLoanService(storage.NewLoanCache(), loanservice.WithPrograms(programs))
+		assert.NoError(t, err)
+		_, err = service.Execute(context.Background(), req)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestLoanService_RateVersions(t *testing.T) {
+	jan := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	jun := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	programs, err := db.NewProgramRegistry(db.DefaultPrograms(),
+		db.RateVersion{ID: "2024-01", EffectiveFrom: jan, Rates: map[string]db.ProgramRate{db.SalaryProgram: {AnnualRate: 800}}},
+		db.RateVersion{ID: "2024-06", EffectiveFrom: jun, Rates: map[string]db.ProgramRate{db.SalaryProgram: {AnnualRate: 750}}},
+	)
+	assert.NoError(t, err)
+	service, err := loanservice.NewLoanService(storage.NewLoanCache(), loanservice.WithPrograms(programs), loanservice.WithMemoization(true))
+	assert.NoError(t, err)
+
+	execute := func(issue time.Time) *entities.LoanResult {
+		resp, err := service.Execute(context.Background(), &entities.LoanRequest{
+			ObjectCost:     5_000_000,
+			InitialPayment: 1_000_000,
+			Months:         240,
+			Program:        &entities.LoanProgram{Salary: true},
+			IssueDate:      timestamppb.New(issue),
+		})
+		assert.NoError(t, err)
+		return resp
+	}
+	before, after := execute(jun.AddDate(0, 0, -1)), execute(jun)
+	assert.Equal(t, int64(800), before.Aggregates.RateBps)
+	assert.Equal(t, "2024-01", before.RateVersion)
+	assert.Equal(t, int64(750), after.Aggregates.RateBps)
+	assert.Equal(t, "2024-06", after.RateVersion)
+	assert.Empty(t, execute(jan.AddDate(0, 0, -1)).RateVersion, "no version before the first one")
+	base, err := service.Execute(context.Background(), &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Base: true},
+		IssueDate:      timestamppb.New(jun),
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, base.RateVersion, "the version does not set the base rate")
+
+	// Версия хранится вместе с расчетом в кеше
+	cached, err := service.GetCalculation(context.Background(), &entities.CalculationID{Id: before.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-01", cached.RateVersion)
+
+	list, err := service.RateVersions(context.Background(), &emptypb.Empty{})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Versions, 2) {
+		first := list.Versions[0]
+		assert.Equal(t, "2024-01", first.Id)
+		assert.Equal(t, jun, first.EffectiveTo.AsTime())
+		assert.Nil(t, list.Versions[1].EffectiveTo)
+		if assert.Len(t, first.Rates, 1) {
+			assert.Equal(t, db.SalaryProgram, first.Rates[0].Code)
+			assert.Equal(t, int64(800), first.Rates[0].RateBps)
+		}
+	}
+
+	diff, err := service.DiffRateVersions(context.Background(), &entities.RateVersionDiffRequest{From: "2024-01", To: "2024-06"})
+	assert.NoError(t, err)
+	if assert.Len(t, diff.Changes, 1) {
+		assert.Equal(t, db.SalaryProgram, diff.Changes[0].Code)
+		assert.Equal(t, int64(800), diff.Changes[0].From.RateBps)
+		assert.Equal(t, int64(750), diff.Changes[0].To.RateBps)
+	}
+
+	_, err = service.DiffRateVersions(context.Background(), &entities.RateVersionDiffRequest{From: "2024-01", To: "2025-01"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = service.DiffRateVersions(context.Background(), &entities.RateVersionDiffRequest{From: "2024-01"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestLoanService_Reload(t *testing.T) {
+	floating := func(spread money.BasisPoints) *db.ProgramRegistry {
+		programs, err := db.NewProgramRegistry(append(db.DefaultPrograms(), db.Program{
+			Code:              "floating",
+			Name:              "Ключевая ставка + спред",
+			RateType:          db.RateKeyRate,
+			Spread:            spread,
+			MinInitialPayment: 0.20,
+		}))
+		assert.NoError(t, err)
+		return programs
+	}
+
+	var service *loanservice.LoanServiceServer
+	reloads := 0
+	provider := keyRateFunc(func(ctx context.Context, date time.Time) (db.KeyRate, error) {
+		// Перезагрузка посреди расчета не меняет ставку уже начатого запроса
+		if reloads == 0 {
+			reloads++
+			assert.NoError(t, service.Reload(floating(500), nil))
+		}
+		return db.KeyRate{Rate: 1600, Date: date}, nil
+	})
+	service, err := loanservice.NewLoanService(storage.NewLoanCache(),
+		loanservice.WithPrograms(floating(200)),
+		loanservice.WithKeyRates(provider),
+	)
+	assert.NoError(t, err)
+
+	req := &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Code: "floating"},
+	}
+	resp, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1800), resp.Aggregates.RateBps, "in-flight request keeps the old snapshot")
+
+	// Новый снимок без источника ключевой ставки: плавающая программа недоступна
+	_, err = service.Execute(context.Background(), req)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	assert.NoError(t, service.Reload(floating(500), provider))
+	resp, err = service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2100), resp.Aggregates.RateBps)
+
+	assert.Error(t, service.Reload(nil, provider))
+}
+
+func TestLoanService_ExecuteProgramCode(t *testing.T) {
+	programs, err := db.NewProgramRegistry(append(db.DefaultPrograms(), db.Program{
+		Code:              "family",
+		Name:              "Семейная ипотека",
+		AnnualRate:        600,
+		MinInitialPayment: 0.30,
+	}))
+	assert.NoError(t, err)
+
+	cache := storage.NewLoanCache()
+	service, err := loanservice.NewLoanService(cache, loanservice.WithPrograms(programs))
+	assert.NoError(t, err)
+
+	resp, err := service.Execute(context.Background(), &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_500_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Code: "family"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(600), resp.Aggregates.RateBps)
+	assert.Len(t, cache.GetByProgram(&entities.LoanProgram{Code: "family"}).Results, 1)
+
+	// Минимальный взнос берется из программы
+	_, err = service.Execute(context.Background(), &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Code: "family"},
+	})
+	assert.Error(t, err)
+}
+
+func TestLoanService_ExecuteProgramLimits(t *testing.T) {
+	programs, err := db.NewProgramRegistry([]db.Program{{
+		Code:              db.MilitaryProgram,
+		AnnualRate:        900,
+		MinInitialPayment: 0.20,
+		MaxLoanSum:        3_000_000,
+	}})
+	assert.NoError(t, err)
+
+	service, err := loanservice.NewLoanService(storage.NewLoanCache(), loanservice.WithPrograms(programs))
+	assert.NoError(t, err)
+
+	_, err = service.Execute(context.Background(), &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Military: true},
+	})
+	assert.Error(t, err)
+
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, "the loan sum should be at most 3000000", st.Message())
+	if assert.Len(t, st.Details(), 1) {
+		badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+		assert.True(t, ok)
+		if assert.Len(t, badRequest.FieldViolations, 1) {
+			assert.Equal(t, "initial_payment", badRequest.FieldViolations[0].Field)
+			assert.Equal(t, db.ReasonLoanSumTooLarge, badRequest.FieldViolations[0].Reason)
+		}
+	}
+}
+
+func TestLoanService_ExecuteValidation(t *testing.T) {
+	service, err := loanservice.NewLoanService(storage.NewLoanCache())
+	assert.NoError(t, err)
+
+	type violation struct {
+		field, reason string
+	}
+	tests := []struct {
+		name    string
+		request *entities.LoanRequest
+		wantMsg string
+		want    []violation
+	}{
+		{
+			name: "Every field is invalid",
+			request: &entities.LoanRequest{
+				ObjectCost:     -1,
+				InitialPayment: -1,
+				Months:         0,
+				PaymentDay:     32,
+			},
+			wantMsg: "object cost should be positive",
+			want: []violation{
+				{"object_cost", loanservice.ReasonObjectCostNotPositive},
+				{"initial_payment", loanservice.ReasonInitialPaymentNegative},
+				{"months", loanservice.ReasonMonthsNotPositive},
+				{"payment_day", loanservice.ReasonPaymentDayOutOfRange},
+				{"program", db.ReasonProgramNotSelected},
+			},
+		},
+		{
+			name: "Initial payment above object cost",
+			request: &entities.LoanRequest{
+				ObjectCost:     1_000_000,
+				InitialPayment: 2_000_000,
+				Months:         1201,
+				Program:        &entities.LoanProgram{Salary: true, Base: true},
+			},
+			wantMsg: "initial payment should be less than object cost",
+			want: []violation{
+				{"initial_payment", loanservice.ReasonInitialPaymentTooLarge},
+				{"months", loanservice.ReasonMonthsTooLarge},
+				{"program", db.ReasonProgramAmbiguous},
+			},
+		},
+		{
+			name: "Object cost above the maximum",
+			request: &entities.LoanRequest{
+				ObjectCost:     1 << 60,
+				InitialPayment: 1_000_000,
+				Months:         240,
+				Program:        &entities.LoanProgram{Base: true},
+			},
+			wantMsg: "object cost should be at most 1000000000000",
+			want: []violation{
+				{"object_cost", loanservice.ReasonObjectCostTooLarge},
+			},
+		},
+		{
+			name: "Program limits are collected too",
+			request: &entities.LoanRequest{
+				ObjectCost:     1_000_000,
+				InitialPayment: 100_000,
+				Months:         12,
+				PaymentDay:     40,
+				Program:        &entities.LoanProgram{Base: true},
+			},
+			wantMsg: "payment day should be between 1 and 31",
+			want: []violation{
+				{"payment_day", loanservice.ReasonPaymentDayOutOfRange},
+				{"initial_payment", db.ReasonInitialPaymentTooLow},
+			},
+		},
+		{
+			name: "Negative costs",
+			request: &entities.LoanRequest{
+				ObjectCost:     1_000_000,
+				InitialPayment: 200_000,
+				Months:         12,
+				Program:        &entities.LoanProgram{Base: true},
+				Costs: &entities.LoanCosts{
+					OneOffFee:        money.FromRubles(-1).Proto(),
+					InsuranceRateBps: -10,
+				},
+			},
+			wantMsg: "costs.one_off_fee should not be negative",
+			want: []violation{
+				{"costs.one_off_fee", loanservice.ReasonCostNegative},
+				{"costs.insurance_rate_bps", loanservice.ReasonCostNegative},
+			},
+		},
+		{
+			name: "Fees above the loan",
+			request: &entities.LoanRequest{
+				ObjectCost:     1_000_000,
+				InitialPayment: 200_000,
+				Months:         12,
+				Program:        &entities.LoanProgram{Base: true},
+				Costs:          &entities.LoanCosts{OneOffFee: money.FromRubles(900_000).Proto()},
+			},
+			wantMsg: "one-off fee and first year insurance should be less than the loan sum",
+			want: []violation{
+				{"costs", loanservice.ReasonCostsExceedLoan},
+			},
+		},
+		{
+			name: "Costs above the maximum",
+			request: &entities.LoanRequest{
+				ObjectCost:     1_000_000,
+				InitialPayment: 200_000,
+				Months:         12,
+				Program:        &entities.LoanProgram{Base: true},
+				Costs:          &entities.LoanCosts{MonthlyFee: &entities.Money{Rubles: 1 << 60}},
+			},
+			wantMsg: "costs.monthly_fee should be at most 1000000000000",
+			want: []violation{
+				{"costs.monthly_fee", loanservice.ReasonCostTooLarge},
+			},
+		},
+		{
+			name: "Invalid rate periods",
+			request: &entities.LoanRequest{
+				ObjectCost:     1_000_000,
+				InitialPayment: 200_000,
+				Months:         12,
+				Program:        &entities.LoanProgram{Base: true},
+				RatePeriods:    []*entities.RatePeriod{{Months: 0, RateBps: 600}, {Months: 6, RateBps: 0}},
+			},
+			wantMsg: "rate period months should be positive, only the last period may be open",
+			want: []violation{
+				{"rate_periods[0].months", loanservice.ReasonRatePeriodMonthsNotPositive},
+				{"rate_periods[1].rate_bps", loanservice.ReasonRatePeriodRateNotPositive},
+			},
+		},
+		{
+			name: "Only program is missing",
+			request: &entities.LoanRequest{
+				ObjectCost:     1_000_000,
+				InitialPayment: 200_000,
+				Months:         12,
+			},
+			wantMsg: "choose program",
+			want: []violation{
+				{"program", db.ReasonProgramNotSelected},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Execute(context.Background(), tt.request)
+			st, _ := status.FromError(err)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+			assert.Equal(t, tt.wantMsg, st.Message())
+
+			if !assert.Len(t, st.Details(), 1) {
+				return
+			}
+			badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+			if !assert.True(t, ok) || !assert.Len(t, badRequest.FieldViolations, len(tt.want)) {
+				return
+			}
+			for i, w := range tt.want {
+				assert.Equal(t, w.field, badRequest.FieldViolations[i].Field)
+				assert.Equal(t, w.reason, badRequest.FieldViolations[i].Reason)
+				assert.NotEmpty(t, badRequest.FieldViolations[i].Description)
+			}
+		})
+	}
+}
+
+func TestLoanService_Compare(t *testing.T) {
+	programs, err := db.NewProgramRegistry(append(db.DefaultPrograms(), db.Program{
+		Code:              "family",
+		Name:              "Семейная ипотека",
+		AnnualRate:        600,
+		MinInitialPayment: 0.30,
+		MaxLoanSum:        3_000_000,
+	}))
+	assert.NoError(t, err)
+
+	cache := storage.NewLoanCache()
+	service, err := loanservice.NewLoanService(cache, loanservice.WithPrograms(programs))
+	assert.NoError(t, err)
+
+	req := &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+	}
+	resp, err := service.Compare(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(240), resp.Params.Months)
+
+	var offered []string
+	for _, offer := range resp.Offers {
+		offered = append(offered, offer.Program.Code)
+	}
+	assert.Equal(t, []string{db.SalaryProgram, db.MilitaryProgram, db.BaseProgram}, offered)
+	assert.Less(t, resp.Offers[0].Aggregates.Overpayment, resp.Offers[1].Aggregates.Overpayment)
+
+	// Тот же расчет, что и у Execute по выбранной программе
+	req.Program = &entities.LoanProgram{Military: true}
+	executed, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, executed.Aggregates.Exact.Overpayment.Rubles, resp.Offers[1].Aggregates.Exact.Overpayment.Rubles)
+	assert.Equal(t, 1, cache.Size(), "compare does not write to the cache")
+
+	if assert.Len(t, resp.Ineligible, 1) {
+		family := resp.Ineligible[0]
+		assert.Equal(t, "family", family.Program.Code)
+		if assert.Len(t, family.Violations, 2) {
+			assert.Equal(t, db.ReasonInitialPaymentTooLow, family.Violations[0].Reason)
+			assert.Equal(t, db.ReasonLoanSumTooLarge, family.Violations[1].Reason)
+		}
+	}
+
+	t.Run("Invalid params", func(t *testing.T) {
+		_, err := service.Compare(context.Background(), &entities.LoanRequest{ObjectCost: 1_000_000, Months: 0})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+	t.Run("Floating program without key rate", func(t *testing.T) {
+		programs, err := db.NewProgramRegistry(append(db.DefaultPrograms(), db.Program{
+			Code:              "floating",
+			Name:              "Ключевая ставка + 2%",
+			RateType:          db.RateKeyRate,
+			Spread:            200,
+			MinInitialPayment: 0.20,
+		}))
+		assert.NoError(t, err)
+		for name, opts := range map[string][]loanservice.Option{
+			"no source": {loanservice.WithPrograms(programs)},
+			"source fails": {loanservice.WithPrograms(programs), loanservice.WithKeyRates(keyRateFunc(func(ctx context.Context, date time.Time) (db.KeyRate, error) {
+				return db.KeyRate{}, errors.New("connection refused")
+			}))},
+		} {
+			service, err := loanservice.NewLoanService(storage.NewLoanCache(), opts...)
+			assert.NoError(t, err)
+			resp, err := service.Compare(context.Background(), &entities.LoanRequest{ObjectCost: 5_000_000, InitialPayment: 1_000_000, Months: 240})
+			assert.NoError(t, err, name)
+			assert.Len(t, resp.Offers, 3, name)
+			if assert.Len(t, resp.Ineligible, 1, name) {
+				assert.Equal(t, "floating", resp.Ineligible[0].Program.Code)
+				assert.Equal(t, loanservice.ReasonProgramRateUnavailable, resp.Ineligible[0].Violations[0].Reason)
+			}
+		}
+	})
+}
+
+func TestLoanService_Prepay(t *testing.T) {
+	cache := storage.NewLoanCache()
+	service, err := loanservice.NewLoanService(cache)
+	assert.NoError(t, err)
+
+	req := &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Salary: true},
+		IssueDate:      timestamppb.New(time.Date(2024, time.February, 18, 0, 0, 0, 0, time.UTC)),
+	}
+	executed, err := service.Execute(context.Background(), req)
+	assert.NoError(t, err)
+
+	// Ежегодно по 100 000 в дату платежа, первый раз через год
+	yearly := &entities.Prepayment{
+		Date:        timestamppb.New(time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC)),
+		Amount:      money.FromRubles(100_000).Proto(),
+		EveryMonths: 12,
+	}
+
+	t.Run("Reduce term by calculation id", func(t *testing.T) {
+		resp, err := service.Prepay(context.Background(), &entities.PrepaymentRequest{
+			Loan:        &entities.PrepaymentRequest_CalculationId{CalculationId: executed.Id},
+			Prepayments: []*entities.Prepayment{yearly},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, executed.Aggregates, resp.Original)
+		assert.Greater(t, resp.MonthsSaved, int64(0))
+		assert.Len(t, resp.Rows, 240-int(resp.MonthsSaved))
+		assert.Equal(t, executed.Aggregates.MonthlyPayment, resp.Aggregates.MonthlyPayment)
+		assert.True(t, resp.Aggregates.LastPaymentDate.AsTime().Before(executed.Aggregates.LastPaymentDate.AsTime()))
+		assert.Greater(t, money.FromProto(resp.InterestSaved), money.Money(0))
+		assert.Equal(t, money.FromProto(resp.Original.Exact.Overpayment)-money.FromProto(resp.Aggregates.Exact.Overpayment), money.FromProto(resp.InterestSaved))
+
+		// Первый досрочный платеж — с платежом 18 февраля 2025
+		assert.Nil(t, resp.Rows[10].Prepayment)
+		assert.Equal(t, int64(100_000), resp.Rows[11].Prepayment.GetRubles())
+		assert.Equal(t, int64(100_000), resp.Rows[23].Prepayment.GetRubles())
+	})
+
+	t.Run("Reduce payment by params", func(t *testing.T) {
+		once := &entities.Prepayment{
+			Date:   yearly.Date,
+			Amount: money.FromRubles(1_000_000).Proto(),
+			Mode:   entities.PrepaymentMode_REDUCE_PAYMENT,
+		}
+		resp, err := service.Prepay(context.Background(), &entities.PrepaymentRequest{
+			Loan:        &entities.PrepaymentRequest_Request{Request: req},
+			Prepayments: []*entities.Prepayment{once},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), resp.MonthsSaved)
+		assert.Len(t, resp.Rows, 240)
+		assert.Equal(t, executed.Aggregates.LastPaymentDate, resp.Aggregates.LastPaymentDate)
+		assert.Less(t, money.FromProto(resp.Rows[12].Payment), money.FromProto(resp.Rows[10].Payment))
+		assert.Greater(t, money.FromProto(resp.InterestSaved), money.Money(0))
+		assert.Equal(t, 1, cache.Size(), "prepayment does not write to the cache")
+	})
+
+	t.Run("Invalid prepayments", func(t *testing.T) {
+		_, err := service.Prepay(context.Background(), &entities.PrepaymentRequest{
+			Loan: &entities.PrepaymentRequest_CalculationId{CalculationId: executed.Id},
+			Prepayments: []*entities.Prepayment{{
+				Date:        timestamppb.New(time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)),
+				EveryMonths: -1,
+			}},
+		})
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		if assert.Len(t, st.Details(), 1) {
+			badRequest := st.Details()[0].(*errdetails.BadRequest)
+			var fields []string
+			for _, v := range badRequest.FieldViolations {
+				fields = append(fields, v.Field)
+			}
+			assert.Equal(t, []string{"prepayments[0].amount", "prepayments[0].date", "prepayments[0].every_months"}, fields)
+		}
+	})
+
+	t.Run("Missing loan", func(t *testing.T) {
+		_, err := service.Prepay(context.Background(), &entities.PrepaymentRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = service.Prepay(context.Background(), &entities.PrepaymentRequest{
+			Loan: &entities.PrepaymentRequest_CalculationId{CalculationId: 42},
+		})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Calculation without loan sum", func(t *testing.T) {
+		id := cache.Add(&entities.LoanResult{
+			Params:     &entities.LoanParams{Months: 12, IssueDate: req.IssueDate},
+			Aggregates: &entities.LoanAggregates{RateBps: 800},
+		})
+		_, err := service.Prepay(context.Background(), &entities.PrepaymentRequest{
+			Loan: &entities.PrepaymentRequest_CalculationId{CalculationId: id},
+			Prepayments: []*entities.Prepayment{{
+				Date:   timestamppb.New(time.Date(2024, time.March, 18, 0, 0, 0, 0, time.UTC)),
+				Amount: &entities.Money{Rubles: 100_000},
+			}},
+		})
+		assert.Equal(t, map[string]string{"loan": loanservice.ReasonLoanInvalid}, fieldReasons(err))
+	})
+
+	t.Run("Schedule cannot be rebuilt", func(t *testing.T) {
+		id := cache.Add(&entities.LoanResult{
+			Params: &entities.LoanParams{Months: 12, IssueDate: req.IssueDate},
+			Aggregates: &entities.LoanAggregates{RateBps: 800, Exact: &entities.ExactAmounts{
+				LoanSum: &entities.Money{Rubles: math.MaxInt64 / 100, Kopecks: math.MaxInt64 % 100},
+			}},
+		})
+		_, err := service.Prepay(context.Background(), &entities.PrepaymentRequest{
+			Loan: &entities.PrepaymentRequest_CalculationId{CalculationId: id},
+		})
+		assert.Equal(t, map[string]string{"prepayments": loanservice.ReasonPrepaymentNotApplicable}, fieldReasons(err))
+	})
+}
+
+func TestLoanService_Affordability(t *testing.T) {
+	programs, err := db.NewProgramRegistry(append(db.DefaultPrograms(), db.Program{
+		Code:              "family",
+		AnnualRate:        600,
+		MinInitialPayment: 0.30,
+		MaxLoanSum:        3_000_000,
+	}))
+	assert.NoError(t, err)
+	service, err := loanservice.NewLoanService(storage.NewLoanCache(), loanservice.WithPrograms(programs))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		request     *entities.AffordabilityRequest
+		wantLimit   entities.AffordabilityLimit
+		wantLoanSum int64
+	}{
+		{
+			name: "Budget with minimal down payment",
+			request: &entities.AffordabilityRequest{
+				MonthlyPayment: 33_458,
+				Months:         240,
+				Program:        &entities.LoanProgram{Salary: true},
+			},
+			wantLimit: entities.AffordabilityLimit_BUDGET,
+		},
+		{
+			name: "Down payment share",
+			request: &entities.AffordabilityRequest{
+				MonthlyPayment: 100_000,
+				Months:         240,
+				Program:        &entities.LoanProgram{Base: true},
+				InitialPayment: 1_000_000,
+			},
+			wantLimit:   entities.AffordabilityLimit_INITIAL_PAYMENT,
+			wantLoanSum: 4_000_000,
+		},
+		{
+			name: "Program max loan sum",
+			request: &entities.AffordabilityRequest{
+				MonthlyPayment: 100_000,
+				Months:         240,
+				Program:        &entities.LoanProgram{Code: "family"},
+			},
+			wantLimit:   entities.AffordabilityLimit_MAX_LOAN_SUM,
+			wantLoanSum: 3_000_000,
+		},
+		{
+			name: "Differentiated first payment",
+			request: &entities.AffordabilityRequest{
+				MonthlyPayment: 50_000,
+				Months:         120,
+				Program:        &entities.LoanProgram{Military: true},
+				PaymentType:    entities.PaymentType_DIFFERENTIATED,
+			},
+			wantLimit: entities.AffordabilityLimit_BUDGET,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := service.Affordability(context.Background(), tt.request)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.wantLimit, resp.LimitedBy)
+			if tt.wantLoanSum > 0 {
+				assert.Equal(t, tt.wantLoanSum, resp.Aggregates.LoanSum)
+			}
+			assert.Equal(t, resp.Params.ObjectCost-resp.Params.InitialPayment, resp.Aggregates.LoanSum)
+
+			// Платеж укладывается в бюджет, а кредит на рубль больше — уже нет
+			budget := money.FromRubles(tt.request.MonthlyPayment)
+			assert.LessOrEqual(t, money.FromProto(resp.Aggregates.Exact.FirstPayment), budget)
+			if tt.wantLimit == entities.AffordabilityLimit_BUDGET {
+				// Взнос тоже на рубль больше, чтобы остаться в доле программы
+				bigger, err := service.Schedule(context.Background(), &entities.LoanRequest{
+					ObjectCost:     resp.Params.ObjectCost + 2,
+					InitialPayment: resp.Params.InitialPayment + 1,
+					Months:         tt.request.Months,
+					Program:        tt.request.Program,
+					PaymentType:    tt.request.PaymentType,
+				})
+				if assert.NoError(t, err) {
+					assert.Greater(t, money.FromProto(bigger.Aggregates.Exact.FirstPayment), budget)
+				}
+			}
+
+			// Взнос проходит ограничения программы, тот же кредит считается через Execute
+			executed, err := service.Execute(context.Background(), &entities.LoanRequest{
+				ObjectCost:     resp.Params.ObjectCost,
+				InitialPayment: resp.Params.InitialPayment,
+				Months:         tt.request.Months,
+				Program:        tt.request.Program,
+				PaymentType:    tt.request.PaymentType,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, executed.Aggregates.Exact.Overpayment, resp.Aggregates.Exact.Overpayment)
+		})
+	}
+
+	t.Run("Budget too small", func(t *testing.T) {
+		_, err := service.Affordability(context.Background(), &entities.AffordabilityRequest{
+			MonthlyPayment: 1,
+			Months:         1,
+			Program:        &entities.LoanProgram{Base: true},
+			InitialPayment: 1,
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = service.Affordability(context.Background(), &entities.AffordabilityRequest{Months: 12})
+		st, _ := status.FromError(err)
+		assert.Equal(t, "monthly payment should be positive", st.Message())
+	})
+	t.Run("Amounts above the maximum", func(t *testing.T) {
+		_, err := service.Affordability(context.Background(), &entities.AffordabilityRequest{
+			MonthlyPayment: 1 << 55,
+			Months:         12,
+			Program:        &entities.LoanProgram{Base: true},
+			InitialPayment: 1 << 60,
+		})
+		assert.Equal(t, map[string]string{
+			"monthly_payment": loanservice.ReasonMonthlyPaymentTooLarge,
+			"initial_payment": loanservice.ReasonInitialPaymentTooLarge,
+		}, fieldReasons(err))
+
+		// Наибольший бюджет считается без переполнения
+		resp, err := service.Affordability(context.Background(), &entities.AffordabilityRequest{
+			MonthlyPayment: 1_000_000_000_000,
+			Months:         12,
+			Program:        &entities.LoanProgram{Base: true},
+		})
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, money.FromProto(resp.Aggregates.Exact.MonthlyPayment), money.FromRubles(1_000_000_000_000))
+	})
+}
+
+// steppedService — сервис с программой 2% на первый год и 12% после него
+func steppedService(t *testing.T) *loanservice.LoanServiceServer {
+	programs, err := db.NewProgramRegistry(append(db.DefaultPrograms(), db.Program{
+		Code:              "promo",
+		AnnualRate:        1200,
+		RatePeriods:       []db.RatePeriod{{Months: 12, AnnualRate: 200}},
+		MinInitialPayment: 0.20,
+	}))
+	assert.NoError(t, err)
+	service, err := loanservice.NewLoanService(storage.NewLoanCache(), loanservice.WithPrograms(programs))
+	assert.NoError(t, err)
+	return service
+}
+
+// maxSchedulePayment возвращает наибольший платеж графика кредита
+func maxSchedulePayment(t *testing.T, service *loanservice.LoanServiceServer, req *entities.LoanRequest) money.Money {
+	schedule, err := service.Schedule(context.Background(), req)
+	assert.NoError(t, err)
+	var res money.Money
+	for _, row := range schedule.GetRows() {
+		res = max(res, money.FromProto(row.Payment))
+	}
+	return res
+}
+
+func TestLoanService_AffordabilitySteppedRate(t *testing.T) {
+	service := steppedService(t)
+	budget := money.FromRubles(50_000)
+
+	// Бюджет ограничивает платеж после льготного года, а не первый платеж
+	resp, err := service.Affordability(context.Background(), &entities.AffordabilityRequest{
+		MonthlyPayment: 50_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Code: "promo"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, entities.AffordabilityLimit_BUDGET, resp.LimitedBy)
+	assert.Less(t, money.FromProto(resp.Aggregates.Exact.MonthlyPayment), budget)
+	payment := maxSchedulePayment(t, service, &entities.LoanRequest{
+		ObjectCost:     resp.Params.ObjectCost,
+		InitialPayment: resp.Params.InitialPayment,
+		Months:         240,
+		Program:        &entities.LoanProgram{Code: "promo"},
+	})
+	assert.LessOrEqual(t, payment, budget)
+	assert.Greater(t, payment, budget-money.FromRubles(1), "largest affordable loan")
+}
+
+func TestLoanService_Solve(t *testing.T) {
+	service, err := loanservice.NewLoanService(storage.NewLoanCache())
+	assert.NoError(t, err)
+
+	// Исходный кредит, по которому проверяется каждое неизвестное
+	base := &entities.LoanRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        &entities.LoanProgram{Salary: true},
+	}
+	executed, err := service.Execute(context.Background(), base)
+	assert.NoError(t, err)
+	budget := executed.Aggregates.MonthlyPayment
+
+	tests := []struct {
+		name     string
+		solveFor entities.SolveFor
+		check    func(t *testing.T, res *entities.SolveResult)
+	}{
+		{"Monthly payment", entities.SolveFor_SOLVE_MONTHLY_PAYMENT, func(t *testing.T, res *entities.SolveResult) {
+			assert.Equal(t, executed.Aggregates.Exact.MonthlyPayment, res.Aggregates.Exact.MonthlyPayment)
+		}},
+		{"Months", entities.SolveFor_SOLVE_MONTHS, func(t *testing.T, res *entities.SolveResult) {
+			assert.Equal(t, int64(240), res.Params.Months)
+		}},
+		{"Initial payment", entities.SolveFor_SOLVE_INITIAL_PAYMENT, func(t *testing.T, res *entities.SolveResult) {
+			// Бюджет округлен вверх до рубля, поэтому взнос может быть чуть меньше исходного
+			assert.LessOrEqual(t, res.Params.InitialPayment, int64(1_000_000))
+			assert.Greater(t, res.Params.InitialPayment, int64(999_000))
+		}},
+		{"Object cost", entities.SolveFor_SOLVE_OBJECT_COST, func(t *testing.T, res *entities.SolveResult) {
+			assert.GreaterOrEqual(t, res.Params.ObjectCost, int64(5_000_000))
+			assert.Less(t, res.Params.ObjectCost, int64(5_001_000))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := service.Solve(context.Background(), &entities.SolveRequest{
+				SolveFor:       tt.solveFor,
+				ObjectCost:     base.ObjectCost,
+				InitialPayment: base.InitialPayment,
+				Months:         base.Months,
+				MonthlyPayment: budget,
+				Program:        base.Program,
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.solveFor, res.SolveFor)
+			assert.LessOrEqual(t, res.Aggregates.MonthlyPayment, budget)
+			tt.check(t, res)
+		})
+	}
+
+	t.Run("Payment below interest", func(t *testing.T) {
+		_, err := service.Solve(context.Background(), &entities.SolveRequest{
+			SolveFor:       entities.SolveFor_SOLVE_MONTHS,
+			ObjectCost:     5_000_000,
+			InitialPayment: 1_000_000,
+			MonthlyPayment: 26_000, // проценты за месяц — 26 666.67
+			Program:        &entities.LoanProgram{Salary: true},
+		})
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		if assert.Len(t, st.Details(), 1) {
+			badRequest := st.Details()[0].(*errdetails.BadRequest)
+			assert.Equal(t, loanservice.ReasonPaymentBelowInterest, badRequest.FieldViolations[0].Reason)
+		}
+	})
+
+	t.Run("No term within the limit", func(t *testing.T) {
+		programs, err := db.NewProgramRegistry([]db.Program{{
+			Code:              db.SalaryProgram,
+			AnnualRate:        800,
+			MinInitialPayment: 0.20,
+			MaxMonths:         360,
+		}})
+		assert.NoError(t, err)
+		service, err := loanservice.NewLoanService(storage.NewLoanCache(), loanservice.WithPrograms(programs))
+		assert.NoError(t, err)
+
+		_, err = service.Solve(context.Background(), &entities.SolveRequest{
+			SolveFor:       entities.SolveFor_SOLVE_MONTHS,
+			ObjectCost:     5_000_000,
+			InitialPayment: 1_000_000,
+			MonthlyPayment: 26_700,
+			Program:        &entities.LoanProgram{Salary: true},
+		})
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		if assert.Len(t, st.Details(), 1) {
+			badRequest := st.Details()[0].(*errdetails.BadRequest)
+			assert.Equal(t, loanservice.ReasonNoSolution, badRequest.FieldViolations[0].Reason)
+		}
+	})
+
+	t.Run("Amounts above the maximum", func(t *testing.T) {
+		_, err := service.Solve(context.Background(), &entities.SolveRequest{
+			SolveFor:       entities.SolveFor_SOLVE_MONTHS,
+			ObjectCost:     1 << 60,
+			InitialPayment: 1 << 59,
+			MonthlyPayment: 1 << 55,
+			Program:        &entities.LoanProgram{Salary: true},
+		})
+		assert.Equal(t, map[string]string{
+			"object_cost":     loanservice.ReasonObjectCostTooLarge,
+			"initial_payment": loanservice.ReasonInitialPaymentTooLarge,
+			"monthly_payment": loanservice.ReasonMonthlyPaymentTooLarge,
+		}, fieldReasons(err))
+	})
+
+	t.Run("Known fields are validated", func(t *testing.T) {
+		_, err := service.Solve(context.Background(), &entities.SolveRequest{
+			SolveFor: entities.SolveFor_SOLVE_OBJECT_COST,
+			Months:   240,
+			Program:  &entities.LoanProgram{Salary: true},
+		})
+		st, _ := status.FromError(err)
+		assert.Equal(t, "monthly payment should be positive", st.Message())
+	})
+}
+
+func TestLoanService_SolveSteppedRate(t *testing.T) {
+	service := steppedService(t)
+	budget := money.FromRubles(50_000)
+	solve := func(req *entities.SolveRequest) *entities.LoanRequest {
+		req.MonthlyPayment, req.Program = 50_000, &entities.LoanProgram{Code: "promo"}
+		res, err := service.Solve(context.Background(), req)
+		assert.NoError(t, err)
+		return &entities.LoanRequest{
+			ObjectCost:     res.Params.ObjectCost,
+			InitialPayment: res.Params.InitialPayment,
+			Months:         res.Params.Months,
+			Program:        req.Program,
+		}
+	}
+
+	t.Run("Months", func(t *testing.T) {
+		loan := solve(&entities.SolveRequest{SolveFor: entities.SolveFor_SOLVE_MONTHS, ObjectCost: 5_000_000, InitialPayment: 1_000_000})
+		assert.LessOrEqual(t, maxSchedulePayment(t, service, loan), budget)
+		// На месяц короче платеж после льготного года выходит за бюджет
+		loan.Months--
+		assert.Greater(t, maxSchedulePayment(t, service, loan), budget)
+	})
+
+	t.Run("Initial payment", func(t *testing.T) {
+		loan := solve(&entities.SolveRequest{SolveFor: entities.SolveFor_SOLVE_INITIAL_PAYMENT, ObjectCost: 6_000_000, Months: 240})
+		payment := maxSchedulePayment(t, service, loan)
+		assert.LessOrEqual(t, payment, budget)
+		assert.Greater(t, payment, budget-money.FromRubles(1))
+	})
+
+	t.Run("Object cost", func(t *testing.T) {
+		loan := solve(&entities.SolveRequest{SolveFor: entities.SolveFor_SOLVE_OBJECT_COST, InitialPayment: 1_500_000, Months: 240})
+		payment := maxSchedulePayment(t, service, loan)
+		assert.LessOrEqual(t, payment, budget)
+		assert.Greater(t, payment, budget-money.FromRubles(1))
+	})
+}
+
+// failingRepository — хранилище, у которого не работает запись
+type failingRepository struct {
+	storage.CalculationRepository
+}
+
+func (failingRepository) Save(string, *entities.LoanResult) (int64, error) {
+	return 0, errors.New("disk is full")
+}
+
+func TestLoanService_Repository(t *testing.T) {
+	req := &entities.LoanRequest{
+		ObjectCost:     1_000_000,
+		InitialPayment: 200_000,
+		Months:         12,
+		Program:        &entities.LoanProgram{Base: true},
+	}
+
+	t.Run("File store", func(t *testing.T) {
+		store, err := storage.OpenFileStore(filepath.Join(t.TempDir(), "calculations.db"))
+		assert.NoError(t, err)
+		defer store.Close()
+		service, err := loanservice.NewLoanService(store)
+		assert.NoError(t, err)
+
+		resp, err := service.Execute(context.Background(), req)
+		assert.NoError(t, err)
+		got, err := service.GetCalculation(context.Background(), &entities.CalculationID{Id: resp.Id})
+		assert.NoError(t, err)
+		assert.Equal(t, resp.Aggregates.MonthlyPayment, got.Aggregates.MonthlyPayment)
+	})
+
+	t.Run("Save error", func(t *testing.T) {
+		service, err := loanservice.NewLoanService(failingRepository{})
+		assert.NoError(t, err)
+		_, err = service.Execute(context.Background(), req)
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("Nil repository", func(t *testing.T) {
+		_, err := loanservice.NewLoanService(nil)
+		assert.Error(t, err)
+	})
+}
+
+// memoryPrograms — хранилище программ в памяти
+type memoryPrograms struct {
+	programs []db.Program
+}
+
+func (s *memoryPrograms) Load() ([]db.Program, error) { return s.programs, nil }
+
+func (s *memoryPrograms) Save(programs []db.Program) error {
+	s.programs = programs
+	return nil
+}
+
+// memoryAudit — журнал изменений в памяти, с ошибкой записи при заданном err
+type memoryAudit struct {
+	entries []db.AuditEntry
+	err     error
+}
+
+func (a *memoryAudit) Record(entry db.AuditEntry) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.entries = append(a.entries, entry)
+	return nil
+}
+
+// fieldReasons возвращает причины нарушений InvalidArgument по полям
+func fieldReasons(err error) map[string]string {
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument || len(st.Details()) != 1 {
+		return nil
+	}
+	res := make(map[string]string)
+	for _, v := range st.Details()[0].(*errdetails.BadRequest).FieldViolations {
+		res[v.Field] = v.Reason
+	}
+	return res
+}
+
+func TestLoanAdminService(t *testing.T) {
+	service, err := loanservice.NewLoanService(storage.NewLoanCache())
+	assert.NoError(t, err)
+	store := &memoryPrograms{programs: db.DefaultPrograms()}
+	audit := &memoryAudit{}
+	admin, err := loanservice.NewLoanAdminService(service, store, audit, nil)
+	assert.NoError(t, err)
+
+	ctx := interceptors.WithActor(context.Background(), "pm")
+	execute := func() (*entities.LoanResult, error) {
+		return service.Execute(context.Background(), &entities.LoanRequest{
+			ObjectCost:     5_000_000,
+			InitialPayment: 1_000_000,
+			Months:         240,
+			Program:        &entities.LoanProgram{Code: "family"},
+		})
+	}
+	_, err = execute()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	created, err := admin.CreateProgram(ctx, &entities.Program{Code: "family", Name: "Семейная ипотека", RateBps: 600, MinInitialPayment: 0.2})
+	assert.NoError(t, err)
+	assert.Equal(t, db.RateFixed, created.RateType)
+	resp, err := execute()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(600), resp.Aggregates.RateBps)
+	assert.Len(t, store.programs, 4)
+
+	_, err = admin.CreateProgram(ctx, &entities.Program{Code: "family", Name: "Дубль", RateBps: 600})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = admin.CreateProgram(ctx, &entities.Program{Code: "Family!", RateBps: 600})
+	assert.Equal(t, map[string]string{
+		"code": loanservice.ReasonProgramCodeInvalid,
+		"name": loanservice.ReasonProgramNameEmpty,
+	}, fieldReasons(err))
+
+	// Ставка меняется без остальных полей, повтор без разницы в журнал не пишется
+	for range 2 {
+		updated, err := admin.SetProgramRate(ctx, &entities.ProgramRate{Code: "family", RateBps: 550})
+		assert.NoError(t, err)
+		assert.Equal(t, "Семейная ипотека", updated.Name)
+	}
+	resp, err = execute()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(550), resp.Aggregates.RateBps)
+
+	_, err = admin.SetProgramRate(ctx, &entities.ProgramRate{Code: "family", RateBps: -5})
+	assert.Equal(t, map[string]string{"program": loanservice.ReasonProgramInvalid}, fieldReasons(err))
+	_, err = admin.UpdateProgram(ctx, &entities.UpdateProgramRequest{Code: "it", Program: &entities.Program{Name: "ИТ", RateBps: 500}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = admin.UpdateProgram(ctx, &entities.UpdateProgramRequest{Code: "family"})
+	assert.Equal(t, map[string]string{"program": loanservice.ReasonProgramInvalid}, fieldReasons(err))
+
+	_, err = admin.DeactivateProgram(ctx, &entities.ProgramCode{Code: "family"})
+	assert.NoError(t, err)
+	_, err = execute()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	list, err := admin.ListPrograms(ctx, &entities.ListProgramsRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, list.Programs, 3)
+	list, err = admin.ListPrograms(ctx, &entities.ListProgramsRequest{IncludeInactive: true})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Programs, 4) {
+		assert.True(t, list.Programs[3].Inactive)
+	}
+
+	// Замена целиком включает программу обратно
+	_, err = admin.UpdateProgram(ctx, &entities.UpdateProgramRequest{Code: "family", Program: &entities.Program{Name: "Семейная", RateBps: 600, MinInitialPayment: 0.3}})
+	assert.NoError(t, err)
+	_, err = execute()
+	assert.Equal(t, map[string]string{"initial_payment": db.ReasonInitialPaymentTooLow}, fieldReasons(err))
+
+	actions := make([]string, 0, len(audit.entries))
+	for _, e := range audit.entries {
+		assert.Equal(t, "pm", e.Actor)
+		assert.Equal(t, "family", e.Code)
+		actions = append(actions, e.Action)
+	}
+	assert.Equal(t, []string{db.AuditCreate, db.AuditSetRate, db.AuditDeactivate, db.AuditUpdate}, actions)
+	assert.Nil(t, audit.entries[0].Before)
+	assert.Equal(t, money.BasisPoints(600), audit.entries[1].Before.AnnualRate)
+	assert.Equal(t, money.BasisPoints(550), audit.entries[1].After.AnnualRate)
+
+	// Без записи в журнале изменение не применяется и откатывается в хранилище
+	audit.err = errors.New("disk full")
+	_, err = admin.SetProgramRate(ctx, &entities.ProgramRate{Code: "family", RateBps: 500})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, money.BasisPoints(600), store.programs[3].AnnualRate)
+	_, err = execute()
+	assert.Equal(t, map[string]string{"initial_payment": db.ReasonInitialPaymentTooLow}, fieldReasons(err))
+}
+
+func TestLoanAdminServiceRateVersions(t *testing.T) {
+	jan := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	jun := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	programs, err := db.NewProgramRegistry(db.DefaultPrograms(),
+		db.RateVersion{ID: "v1", EffectiveFrom: jan, EffectiveTo: jun, Rates: map[string]db.ProgramRate{db.SalaryProgram: {AnnualRate: 700}, db.MilitaryProgram: {AnnualRate: 850}}},
+		db.RateVersion{ID: "v2", EffectiveFrom: jun, Rates: map[string]db.ProgramRate{db.SalaryProgram: {AnnualRate: 750}}},
+	)
+	assert.NoError(t, err)
+	service, err := loanservice.NewLoanService(storage.NewLoanCache(), loanservice.WithPrograms(programs))
+	assert.NoError(t, err)
+	store := &memoryPrograms{programs: programs.All()}
+	audit := &memoryAudit{}
+	admin, err := loanservice.NewLoanAdminService(service, store, audit, nil)
+	assert.NoError(t, err)
+
+	ctx := interceptors.WithActor(context.Background(), "pm")
+	execute := func(code string) *entities.LoanResult {
+		resp, err := service.Execute(context.Background(), &entities.LoanRequest{
+			ObjectCost:     5_000_000,
+			InitialPayment: 1_000_000,
+			Months:         240,
+			Program:        &entities.LoanProgram{Code: code},
+		})
+		assert.NoError(t, err)
+		return resp
+	}
+
+	// Открытая версия задает ставку: смена ставки в реестре на расчеты не повлияла бы
+	_, err = admin.SetProgramRate(ctx, &entities.ProgramRate{Code: db.SalaryProgram, RateBps: 500})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = admin.UpdateProgram(ctx, &entities.UpdateProgramRequest{Code: db.SalaryProgram, Program: &entities.Program{Name: "Зарплатная", RateBps: 500, MinInitialPayment: 0.2}})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	resp := execute(db.SalaryProgram)
+	assert.Equal(t, int64(750), resp.Aggregates.RateBps)
+	assert.Equal(t, "v2", resp.RateVersion)
+	assert.Empty(t, audit.entries)
+	assert.Equal(t, money.BasisPoints(800), store.programs[0].AnnualRate)
+
+	// Остальные поля без смены ставки меняются
+	_, err = admin.UpdateProgram(ctx, &entities.UpdateProgramRequest{Code: db.SalaryProgram, Program: &entities.Program{Name: "Зарплатная", RateBps: 800, MinInitialPayment: 0.2}})
+	assert.NoError(t, err)
+
+	// Программы, не записанные в действующей версии, ей не управляются: их ставка меняется в реестре,
+	// а закрытая версия, где она записана, остается прежней
+	for _, code := range []string{db.BaseProgram, db.MilitaryProgram} {
+		_, err = admin.SetProgramRate(ctx, &entities.ProgramRate{Code: code, RateBps: 880})
+		assert.NoError(t, err)
+		resp := execute(code)
+		assert.Equal(t, int64(880), resp.Aggregates.RateBps)
+		assert.Empty(t, resp.RateVersion)
+	}
+	v1, _ := service.RateVersions(ctx, &emptypb.Empty{})
+	assert.Equal(t, int64(850), v1.Versions[0].Rates[1].RateBps)
+
+	// Программа, созданная после версий, в них не записана, и ее ставка меняется
+	_, err = admin.CreateProgram(ctx, &entities.Program{Code: "family", Name: "Семейная ипотека", RateBps: 600, MinInitialPayment: 0.2})
+	assert.NoError(t, err)
+	_, err = admin.SetProgramRate(ctx, &entities.ProgramRate{Code: "family", RateBps: 550})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(550), execute("family").Aggregates.RateBps)
+}
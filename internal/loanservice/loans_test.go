@@ -1,12 +1,16 @@
 package loanservice
 
 import (
-	// "fmt"
-	"fmt"
 	"testing"
 
 	"errors"
-	"math"
+	"reflect"
+	"time"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+	"github.com/Dorji/sberInterview/internal/money"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	// storage "github.com/Dorji/sberInterview/internal/loanservice/storage"
 )
 
@@ -15,33 +19,33 @@ func TestCalculateMonthlyPayment(t *testing.T) {
 
 	tests := []struct {
 		name        string
-		loanSum     int64
-		annualRate  float64
+		loanSum     money.Money
+		annualRate  money.BasisPoints
 		months      int64
-		wantPayment int64
+		wantPayment money.Money
 		wantErr     error
 	}{
 		// Корректные случаи
 		{
 			"Standard case 4M loan",
-			4_000_000,
-			0.08,
+			money.FromRubles(4_000_000),
+			800,
 			240,
-			33_458,
+			3_345_760,
 			nil,
 		},
 		{
 			"Short term 1M loan",
-			1_000_000,
-			0.12,
+			money.FromRubles(1_000_000),
+			1200,
 			12,
-			88_849,
+			8_884_879,
 			nil,
 		},
 		{
 			"No interest",
-			1200000,
-			0.00,
+			money.FromRubles(1_200_000),
+			0,
 			120,
 			0,
 			errors.New("calculateMonthlyPayment:installment plan"),
@@ -52,47 +56,47 @@ func TestCalculateMonthlyPayment(t *testing.T) {
 		{
 			"Zero loan sum",
 			0,
-			0.08,
+			800,
 			240,
 			0,
 			errors.New("calculateMonthlyPayment:Zero loan sum"),
 		},
 		{
 			"Negative loan sum",
-			-1,
-			0.08,
+			money.FromRubles(-1),
+			800,
 			240,
 			0,
 			errors.New("calculateMonthlyPayment:Zero loan sum"),
 		},
 		{
 			"Zero months",
-			1_000_000,
-			0.08,
+			money.FromRubles(1_000_000),
+			800,
 			0,
 			0,
 			errors.New("calculateMonthlyPayment:Zero months"),
 		},
 		{
 			"Negative months",
-			1_000_000,
-			0.08,
+			money.FromRubles(1_000_000),
+			800,
 			-1,
 			0,
 			errors.New("calculateMonthlyPayment:Zero months"),
 		},
 		{
 			"Negative rate",
-			1_000_000,
-			-0.08,
+			money.FromRubles(1_000_000),
+			-800,
 			12,
 			0,
 			errors.New("calculateMonthlyPayment:rate less than 0.00"),
 		},
 		{
 			"Invalid denominator",
-			1_000_000,
-			-1.0, // Приведет к отрицательному знаменателю
+			money.FromRubles(1_000_000),
+			-10000, // Приведет к отрицательному знаменателю
 			12,
 			0,
 			errors.New("calculateMonthlyPayment:rate less than 0.00"),
@@ -100,19 +104,35 @@ func TestCalculateMonthlyPayment(t *testing.T) {
 		
 		{
 			"Invalid denominator",
-			1_000_000,
-			-1.0, // Приведет к отрицательному знаменателю
+			money.FromRubles(1_000_000),
+			-10000, // Приведет к отрицательному знаменателю
 			12,
 			0,
 			errors.New("calculateMonthlyPayment:rate less than 0.00"),
 		},
 		{
-			"denominator from very small rate",
-			1_000_000,
-			0.000000000000001, 
+			"Minimal rate is computed exactly",
+			money.FromRubles(1_000_000),
+			1, // 0.01%
 			12,
+			8_333_785,
+			nil,
+		},
+		{
+			"Fractional rate",
+			money.FromRubles(4_000_000),
+			850, // 8.5%
+			240,
+			3_471_293,
+			nil,
+		},
+		{
+			"Too long term",
+			money.FromRubles(1_000_000),
+			800,
+			1201,
 			0,
-			errors.New("calculateMonthlyPayment:denominator less than 0"),
+			errors.New("calculateMonthlyPayment:months too large"),
 		},
 	}
 
@@ -141,11 +161,11 @@ func TestCalculateMonthlyPaymentEdgeCases(t *testing.T) {
 	ls := &LoanServiceServer{}
 
 	t.Run("Single month payment", func(t *testing.T) {
-		got, err := ls.calculateMonthlyPayment(1000, 0.12, 1)
+		got, err := ls.calculateMonthlyPayment(money.FromRubles(1000), 1200, 1)
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
 		}
-		expected := int64(math.Ceil(1000 * (1 + 0.12/12)))
+		expected := money.FromRubles(1010)
 		if got != expected {
 			t.Errorf("Expected %v, got %v", expected, got)
 		}
@@ -153,204 +173,396 @@ func TestCalculateMonthlyPaymentEdgeCases(t *testing.T) {
 }
 
 
-func TestRoundNotNegative(t *testing.T) {
+func TestBuildAnnuitySchedule(t *testing.T) {
 	ls := &LoanServiceServer{}
 
 	tests := []struct {
-		name    string
-		num     float64
-		want    int64
-		wantErr bool
-		errMsg  string
+		name       string
+		loanSum    money.Money
+		annualRate money.BasisPoints
+		months     int64
 	}{
-		{
-			name:    "Positive number",
-			num:     123.45,
-			want:    124,
-			wantErr: false,
-		},
-		{
-			name:    "Negative number",
-			num:     -123.45,
-			want:    0,
-			wantErr: true,
-			errMsg:  "LoanServiceServer: num is negative",
-		},
-		{
-			name:    "Negative number 2",
-			num:     -0.45,
-			want:    0,
-			wantErr: true,
-			errMsg:  "LoanServiceServer: num is negative",
-		},
-		{
-			name:    "NaN",
-			num:     math.NaN(),
-			want:    0,
-			wantErr: true,
-			errMsg:  "LoanServiceServer:NAN",
-		},
-		{
-			name:    "Positive infinity",
-			num:     math.Inf(1),
-			want:    0,
-			wantErr: true,
-			errMsg:  "LoanServiceServer:round num overflow",
-		},
-		{
-			name:    "Negative infinity",
-			num:     math.Inf(-1),
-			want:    0,
-			wantErr: true,
-			errMsg:  "LoanServiceServer: num is negative",
-		},
-		{
-			name:    "Max int64",
-			num:     float64(math.MaxInt64),
-			want:    0,
-			wantErr: true,
-			errMsg:  "LoanServiceServer:round num overflow",
-		},
-		{
-			name:    "Overflow int64",
-			num:     float64(math.MaxInt64) + 1,
-			want:    0,
-			wantErr: true,
-			errMsg:  "LoanServiceServer:round num overflow",
-		},
-		{
-			name:    "Zero",
-			num:     0.0,
-			want:    0,
-			wantErr: false,
-		},
+		{"Standard case 4M loan", money.FromRubles(4_000_000), 800, 240},
+		{"Short term 1M loan", money.FromRubles(1_000_000), 1200, 12},
+		{"Single month", money.FromRubles(1000), 1200, 1},
+		{"Odd kopecks", 123_456_789, 950, 37},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ls.RoundNotNegative(tt.num)
-			if (err != nil) != tt.wantErr {
-				fmt.Println(tt.num)
-				t.Errorf("RoundNotNegative() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			payment, err := ls.calculateMonthlyPayment(tt.loanSum, tt.annualRate, tt.months)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
 			}
-			if err != nil && tt.wantErr && err.Error() != tt.errMsg {
-				t.Errorf("RoundNotNegative() error message = %v, want %v", err.Error(), tt.errMsg)
+			calendar := paymentCalendar{issue: time.Date(2024, time.February, 18, 0, 0, 0, 0, time.UTC), day: 18}
+			rows, err := ls.buildAnnuitySchedule(tt.loanSum, tt.annualRate, tt.months, payment, calendar)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
 			}
-			if got != tt.want {
-				fmt.Println(tt.num)
-				t.Errorf("RoundNotNegative() = %v, want %v", got, tt.want)
+
+			if int64(len(rows)) != tt.months {
+				t.Fatalf("Expected %d rows, got %d", tt.months, len(rows))
+			}
+
+			var interest, principal, total money.Money
+			for i, row := range rows {
+				if row.payment != row.interest+row.principal {
+					t.Errorf("Month %d: payment %v != interest %v + principal %v",
+						row.month, row.payment, row.interest, row.principal)
+				}
+				// Все платежи кроме последнего равны аннуитету
+				if i < len(rows)-1 && row.payment != payment {
+					t.Errorf("Month %d: payment %v, want %v", row.month, row.payment, payment)
+				}
+				interest += row.interest
+				principal += row.principal
+				total += row.payment
+			}
+
+			if principal != tt.loanSum {
+				t.Errorf("Expected principal total %v, got %v", tt.loanSum, principal)
+			}
+			if total != tt.loanSum+interest {
+				t.Errorf("Expected total %v, got %v", tt.loanSum+interest, total)
+			}
+			// Корректировка последнего платежа не превышает нескольких копеек на каждый месяц
+			if diff := rows[len(rows)-1].payment - payment; diff > money.Money(tt.months) || diff < -money.Money(tt.months) {
+				t.Errorf("Last payment correction %v is too large", diff)
+			}
+			if last := rows[len(rows)-1]; last.balance != 0 {
+				t.Errorf("Expected zero balance after last payment, got %v", last.balance)
 			}
 		})
 	}
 }
 
-package loanservice_test
+func TestBuildDifferentiatedSchedule(t *testing.T) {
+	ls := &LoanServiceServer{}
+	calendar := paymentCalendar{issue: time.Date(2024, time.February, 18, 0, 0, 0, 0, time.UTC), day: 18}
 
-import (
-	"context"
-	"testing"
+	t.Run("Declining payments", func(t *testing.T) {
+		rows, err := ls.buildDifferentiatedSchedule(money.FromRubles(1_000_000), 1200, 12, calendar)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(rows) != 12 {
+			t.Fatalf("Expected 12 rows, got %d", len(rows))
+		}
 
-	"github.com/Dorji/sberInterview/api/protos/entities"
-	"github.com/Dorji/sberInterview/api/protos/services"
-	"github.com/Dorji/sberInterview/internal/loanservice"
-	"github.com/Dorji/sberInterview/internal/loanservice/storage"
-	"github.com/stretchr/testify/assert"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/emptypb"
-)
+		// Первый платеж: 83 333.33 основного долга + 1% от 1 000 000
+		if rows[0].payment != 9_333_333 {
+			t.Errorf("Expected first payment 93333.33, got %v", rows[0].payment)
+		}
+
+		var principal money.Money
+		for i, row := range rows {
+			if i > 0 && row.payment > rows[i-1].payment {
+				t.Errorf("Month %d: payment %v grows after %v", row.month, row.payment, rows[i-1].payment)
+			}
+			principal += row.principal
+		}
+		if principal != money.FromRubles(1_000_000) {
+			t.Errorf("Expected principal total 1000000.00, got %v", principal)
+		}
+		if last := rows[len(rows)-1]; last.balance != 0 {
+			t.Errorf("Expected zero balance after last payment, got %v", last.balance)
+		}
+	})
+
+	errTests := []struct {
+		name       string
+		loanSum    money.Money
+		annualRate money.BasisPoints
+		months     int64
+		wantErr    string
+	}{
+		{"Zero loan sum", 0, 800, 12, "buildDifferentiatedSchedule:Zero loan sum"},
+		{"Zero months", money.FromRubles(1_000_000), 800, 0, "buildDifferentiatedSchedule:Zero months"},
+		{"Too long term", money.FromRubles(1_000_000), 800, 1201, "buildDifferentiatedSchedule:months too large"},
+		{"Zero rate", money.FromRubles(1_000_000), 0, 12, "buildDifferentiatedSchedule:rate less than 0.00"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ls.buildDifferentiatedSchedule(tt.loanSum, tt.annualRate, tt.months, calendar)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("buildDifferentiatedSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildPrepaymentSchedule(t *testing.T) {
+	ls := &LoanServiceServer{}
+	calendar := paymentCalendar{issue: time.Date(2024, time.February, 18, 0, 0, 0, 0, time.UTC), day: 18}
+	loanSum := money.FromRubles(4_000_000)
+
+	t.Run("No prepayments match the plain schedule", func(t *testing.T) {
+		payment, _ := ls.calculateMonthlyPayment(loanSum, 800, 240)
+		want, _ := ls.buildAnnuitySchedule(loanSum, 800, 240, payment, calendar)
+		got, err := ls.buildPrepaymentSchedule(loanSum, fixedRate(800), 240, entities.PaymentType_ANNUITY, calendar, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(got) != len(want) || got[len(got)-1] != want[len(want)-1] {
+			t.Errorf("Expected the plain annuity schedule")
+		}
+	})
 
-func TestLoanService_Execute(t *testing.T) {
 	tests := []struct {
 		name        string
-		request     *entities.LoanRequest
-		wantErr     bool
-		expectedErr codes.Code
+		paymentType entities.PaymentType
+		mode        entities.PrepaymentMode
+		shorter     bool // срок сокращается
 	}{
-		{
-			name: "Valid request",
-			request: &entities.LoanRequest{
-				ObjectCost:     1_000_000,
-				InitialPayment: 200_000,
-				Months:        12,
-				Program:       "standard",
-			},
-			wantErr: false,
-		},
-		{
-			name: "Initial payment too low",
-			request: &entities.LoanRequest{
-				ObjectCost:     1_000_000,
-				InitialPayment: 50_000, // Меньше минимального
-				Months:        12,
-				Program:       "standard",
-			},
-			wantErr:     true,
-			expectedErr: codes.InvalidArgument,
-		},
-		{
-			name: "Invalid program",
-			request: &entities.LoanRequest{
-				ObjectCost:     1_000_000,
-				InitialPayment: 200_000,
-				Months:        12,
-				Program:       "invalid_program",
-			},
-			wantErr:     true,
-			expectedErr: codes.Internal,
-		},
+		{"Annuity, reduce term", entities.PaymentType_ANNUITY, entities.PrepaymentMode_REDUCE_TERM, true},
+		{"Annuity, reduce payment", entities.PaymentType_ANNUITY, entities.PrepaymentMode_REDUCE_PAYMENT, false},
+		{"Differentiated, reduce term", entities.PaymentType_DIFFERENTIATED, entities.PrepaymentMode_REDUCE_TERM, true},
+		{"Differentiated, reduce payment", entities.PaymentType_DIFFERENTIATED, entities.PrepaymentMode_REDUCE_PAYMENT, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extra := money.FromRubles(500_000)
+			plan := map[int64][]prepaymentEvent{12: {{amount: extra, mode: tt.mode}}}
+			rows, err := ls.buildPrepaymentSchedule(loanSum, fixedRate(800), 240, tt.paymentType, calendar, plan)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var principal money.Money
+			for _, row := range rows {
+				principal += row.principal + row.prepayment
+			}
+			if principal != loanSum {
+				t.Errorf("Expected principal total %v, got %v", loanSum, principal)
+			}
+			if rows[11].prepayment != extra {
+				t.Errorf("Expected prepayment %v in month 12, got %v", extra, rows[11].prepayment)
+			}
+			if last := rows[len(rows)-1]; last.balance != 0 {
+				t.Errorf("Expected zero balance after last payment, got %v", last.balance)
+			}
+			if shorter := len(rows) < 240; shorter != tt.shorter {
+				t.Errorf("Expected shorter term %v, got %d months", tt.shorter, len(rows))
+			}
+			if !tt.shorter && rows[12].payment >= rows[10].payment {
+				t.Errorf("Expected lower payment after prepayment: %v >= %v", rows[12].payment, rows[10].payment)
+			}
+		})
 	}
+}
 
+func TestNewRateSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		periods []db.RatePeriod
+		months  int64
+		want    rateSchedule
+	}{
+		{"No periods", nil, 240, rateSchedule{{1, 1000}}},
+		{"Teaser rate", []db.RatePeriod{{Months: 24, AnnualRate: 600}}, 240, rateSchedule{{1, 600}, {25, 1000}}},
+		{"Steps", []db.RatePeriod{{Months: 12, AnnualRate: 500}, {Months: 12, AnnualRate: 700}}, 240, rateSchedule{{1, 500}, {13, 700}, {25, 1000}}},
+		{"Open last period", []db.RatePeriod{{Months: 12, AnnualRate: 500}, {AnnualRate: 700}}, 240, rateSchedule{{1, 500}, {13, 700}}},
+		{"Periods longer than term", []db.RatePeriod{{Months: 24, AnnualRate: 600}}, 12, rateSchedule{{1, 600}}},
+		{"Equal neighbours merge", []db.RatePeriod{{Months: 12, AnnualRate: 1000}}, 240, rateSchedule{{1, 1000}}},
+	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service, err := loanservice.NewLoanService(storage.NewLoanCache())
-			assert.NoError(t, err)
+			got := newRateSchedule(1000, tt.periods, tt.months)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSteppedRateSchedule(t *testing.T) {
+	ls := &LoanServiceServer{}
+	calendar := paymentCalendar{issue: time.Date(2024, time.February, 18, 0, 0, 0, 0, time.UTC), day: 18}
+	loanSum := money.FromRubles(4_000_000)
+	rates := rateSchedule{{1, 600}, {25, 1000}}
 
-			resp, err := service.Execute(context.Background(), tt.request)
+	t.Run("Annuity is re-amortized at the rate change", func(t *testing.T) {
+		rows, err := ls.buildPrepaymentSchedule(loanSum, rates, 240, entities.PaymentType_ANNUITY, calendar, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(rows) != 240 || rows[len(rows)-1].balance != 0 {
+			t.Fatalf("Expected 240 payments ending with zero balance")
+		}
 
-			if tt.wantErr {
-				assert.Error(t, err)
-				if st, ok := status.FromError(err); ok {
-					assert.Equal(t, tt.expectedErr, st.Code())
-				}
-			} else {
-				assert.NoError(t, err)
-				assert.NotNil(t, resp)
-				assert.Equal(t, tt.request.Program, resp.Program)
-				assert.Equal(t, tt.request.ObjectCost, resp.Params.ObjectCost)
-				assert.True(t, resp.Aggregates.MonthlyPayment > 0)
+		teaser, _ := ls.calculateMonthlyPayment(loanSum, 600, 240)
+		if rows[0].payment != teaser || rows[23].payment != teaser {
+			t.Errorf("Expected teaser payment %v, got %v and %v", teaser, rows[0].payment, rows[23].payment)
+		}
+		after, _ := ls.calculateMonthlyPayment(rows[23].balance, 1000, 216)
+		if rows[24].payment != after || rows[100].payment != after {
+			t.Errorf("Expected payment %v after the rate change, got %v", after, rows[24].payment)
+		}
+		if want, _ := rows[23].balance.Mul(money.BasisPoints(1000).Monthly(), money.HalfUp); rows[24].interest != want {
+			t.Errorf("Expected interest %v at the new rate, got %v", want, rows[24].interest)
+		}
+
+		summary := ratePeriodsProto(rates, rows)
+		if len(summary) != 2 {
+			t.Fatalf("Expected 2 rate periods, got %d", len(summary))
+		}
+		if summary[0].FromMonth != 1 || summary[0].ToMonth != 24 || summary[1].FromMonth != 25 || summary[1].ToMonth != 240 {
+			t.Errorf("Unexpected period bounds: %v", summary)
+		}
+		if money.FromProto(summary[1].Payment) != after {
+			t.Errorf("Expected period payment %v, got %v", after, money.FromProto(summary[1].Payment))
+		}
+		var interest money.Money
+		for _, row := range rows {
+			interest += row.interest
+		}
+		if got := money.FromProto(summary[0].Interest) + money.FromProto(summary[1].Interest); got != interest {
+			t.Errorf("Expected period interest to sum up to %v, got %v", interest, got)
+		}
+	})
+
+	t.Run("Differentiated keeps the principal part", func(t *testing.T) {
+		rows, err := ls.buildPrepaymentSchedule(loanSum, rates, 240, entities.PaymentType_DIFFERENTIATED, calendar, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if rows[23].principal != rows[24].principal {
+			t.Errorf("Expected equal principal parts, got %v and %v", rows[23].principal, rows[24].principal)
+		}
+		if rows[24].payment <= rows[23].payment {
+			t.Errorf("Expected higher payment at the higher rate: %v <= %v", rows[24].payment, rows[23].payment)
+		}
+	})
+
+	t.Run("Fixed rate has no periods", func(t *testing.T) {
+		if got := ratePeriodsProto(fixedRate(800), []scheduleRow{{month: 1}}); got != nil {
+			t.Errorf("Expected no periods, got %v", got)
+		}
+	})
+}
+
+func TestFullCostRate(t *testing.T) {
+	ls := &LoanServiceServer{}
+	calendar := paymentCalendar{issue: time.Date(2024, time.February, 18, 0, 0, 0, 0, time.UTC), day: 18}
+	loanSum := money.FromRubles(1_000_000)
+	payment, _ := ls.calculateMonthlyPayment(loanSum, 1200, 12)
+	rows, _ := ls.buildAnnuitySchedule(loanSum, 1200, 12, payment, calendar)
+
+	tests := []struct {
+		name  string
+		costs *entities.LoanCosts
+		want  int64
+		total money.Money
+	}{
+		// Без расходов и со сдвигом дат ровно на месяц ПСК равна номинальной ставке
+		{"No costs", nil, 12_000, 0},
+		{"One-off fee", &entities.LoanCosts{OneOffFee: money.FromRubles(10_000).Proto()}, 13_913, money.FromRubles(10_000)},
+		{"Monthly fee", &entities.LoanCosts{MonthlyFee: money.FromRubles(1_000).Proto()}, 14_131, money.FromRubles(12_000)},
+		{"Insurance on balance", &entities.LoanCosts{InsuranceRateBps: 100}, 13_913, money.FromRubles(10_000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregates := scheduleAggregates(loanSum, fixedRate(1200), rows)
+			if err := addFullCost(aggregates, tt.costs, loanSum, rows, calendar); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if aggregates.FullCostRate != tt.want {
+				t.Errorf("Expected full cost rate %d, got %d", tt.want, aggregates.FullCostRate)
+			}
+			if got := money.FromProto(aggregates.Costs.Total); got != tt.total {
+				t.Errorf("Expected costs %v, got %v", tt.total, got)
+			}
+			if got, want := money.FromProto(aggregates.FullCost), money.FromProto(aggregates.Exact.Overpayment)+tt.total; got != want {
+				t.Errorf("Expected full cost %v, got %v", want, got)
 			}
 		})
 	}
 }
 
-func TestLoanService_Cache(t *testing.T) {
-	t.Run("Empty cache", func(t *testing.T) {
-		service, err := loanservice.NewLoanService(storage.NewLoanCache())
-		assert.NoError(t, err)
+func TestFullMonths(t *testing.T) {
+	tests := []struct {
+		from, to   time.Time
+		wantMonths int64
+		wantDays   int
+	}{
+		{time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), 1, 0},
+		{time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), 1, 24},
+		{time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), 12, 0},
+	}
+	for _, tt := range tests {
+		months, days := fullMonths(tt.from, tt.to)
+		if months != tt.wantMonths || days != tt.wantDays {
+			t.Errorf("fullMonths(%v, %v) = %d, %d; want %d, %d", tt.from, tt.to, months, days, tt.wantMonths, tt.wantDays)
+		}
+	}
+}
+
+func TestPaymentCalendar(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
 
-		_, err = service.Cache(context.Background(), &emptypb.Empty{})
-		assert.Error(t, err)
-		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	tests := []struct {
+		name  string
+		issue time.Time
+		day   int
+		month int64
+		want  time.Time
+	}{
+		{"Spec example", date(2024, time.February, 18), 18, 240, date(2044, time.February, 18)},
+		{"First payment", date(2024, time.February, 18), 18, 1, date(2024, time.March, 18)},
+		{"Year rollover", date(2024, time.November, 5), 5, 3, date(2025, time.February, 5)},
+		{"Clamp to short month", date(2024, time.January, 31), 31, 3, date(2024, time.April, 30)},
+		{"Clamp to leap February", date(2024, time.January, 31), 31, 1, date(2024, time.February, 29)},
+		{"Clamp to common February", date(2025, time.January, 31), 31, 1, date(2025, time.February, 28)},
+		{"Day restored after short month", date(2024, time.January, 31), 31, 2, date(2024, time.March, 31)},
+		{"Custom payment day", date(2024, time.February, 18), 5, 1, date(2024, time.March, 5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := paymentCalendar{issue: tt.issue, day: tt.day}.date(tt.month)
+			if !got.Equal(tt.want) {
+				t.Errorf("date(%d) = %v, want %v", tt.month, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPaymentCalendar(t *testing.T) {
+	today := time.Date(2024, time.February, 18, 15, 30, 0, 0, time.UTC)
+	ls := &LoanServiceServer{now: func() time.Time { return today }}
+
+	t.Run("Defaults to today", func(t *testing.T) {
+		calendar, err := ls.newPaymentCalendar(&entities.LoanRequest{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if want := time.Date(2024, time.February, 18, 0, 0, 0, 0, time.UTC); !calendar.issue.Equal(want) {
+			t.Errorf("Expected issue %v, got %v", want, calendar.issue)
+		}
+		if calendar.day != 18 {
+			t.Errorf("Expected payment day 18, got %d", calendar.day)
+		}
 	})
 
-	t.Run("With items in cache", func(t *testing.T) {
-		cache := storage.NewLoanCache()
-		service, err := loanservice.NewLoanService(cache)
-		assert.NoError(t, err)
-
-		// Добавляем тестовые данные
-		_, err = service.Execute(context.Background(), &entities.LoanRequest{
-			ObjectCost:     1_000_000,
-			InitialPayment: 200_000,
-			Months:        12,
-			Program:       "standard",
+	t.Run("Explicit issue date and payment day", func(t *testing.T) {
+		calendar, err := ls.newPaymentCalendar(&entities.LoanRequest{
+			IssueDate:  timestamppb.New(time.Date(2023, time.May, 31, 0, 0, 0, 0, time.UTC)),
+			PaymentDay: 10,
 		})
-		assert.NoError(t, err)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if calendar.issue.Month() != time.May || calendar.day != 10 {
+			t.Errorf("Unexpected calendar %+v", calendar)
+		}
+	})
 
-		resp, err := service.Cache(context.Background(), &emptypb.Empty{})
-		assert.NoError(t, err)
-		assert.Len(t, resp.Results, 1)
+	t.Run("Invalid payment day", func(t *testing.T) {
+		_, err := ls.newPaymentCalendar(&entities.LoanRequest{PaymentDay: 32})
+		if err == nil {
+			t.Error("Expected error for payment day 32")
+		}
 	})
-}
\ No newline at end of file
+}
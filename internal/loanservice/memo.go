@@ -0,0 +1,43 @@
+package loanservice
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"github.com/Dorji/sberInterview/internal/money"
+)
+
+// requestKey строит ключ мемоизации по нормализованному и уже оцененному запросу: параметрам,
+// коду программы вместо bool-полей, ставке программы и датам после подстановки значений по умолчанию.
+// Комиссии и страховка меняют ПСК, а периоды ступенчатой ставки — график, поэтому тоже входят в ключ.
+// Ставка входит в ключ, чтобы после смены ставки программы или ключевой ставки старые расчеты не отдавались повторно.
+func requestKey(req *entities.LoanRequest, loan pricedLoan) string {
+	program, pricing, calendar := loan.program, loan.pricing, loan.calendar
+	raw := fmt.Sprintf("v1|%d|%d|%d|%s|%d|%d|%s|%d",
+		req.ObjectCost, req.InitialPayment, req.Months,
+		program.Code, program.AnnualRate, req.PaymentType,
+		calendar.issue.Format("2006-01-02"), calendar.day,
+	)
+	// Расходы дописываются, только если заданы, чтобы ключи расчетов без них не менялись
+	if c := req.Costs; c != nil {
+		raw += fmt.Sprintf("|costs|%d|%d|%d|%d",
+			money.FromProto(c.OneOffFee), money.FromProto(c.MonthlyFee),
+			money.FromProto(c.AnnualInsurance), c.InsuranceRateBps,
+		)
+	}
+	// Версия ставок записывается в расчет, поэтому расчет по другой версии с той же ставкой не переиспользуется
+	if pricing.rateVersion != "" {
+		raw += "|version|" + pricing.rateVersion
+	}
+	// Ступенчатая ставка из запроса или программы — тоже только если задана
+	if rates := loanRates(req, program); len(rates) > 1 {
+		raw += "|rates"
+		for _, p := range rates {
+			raw += fmt.Sprintf("|%d:%d", p.from, p.rate)
+		}
+	}
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
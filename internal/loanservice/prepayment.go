@@ -0,0 +1,297 @@
+package loanservice
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"github.com/Dorji/sberInterview/internal/money"
+)
+
+// Машиночитаемые причины ошибок в досрочных платежах
+const (
+	ReasonLoanNotSelected             = "LOAN_NOT_SELECTED"
+	ReasonPrepaymentAmountNotPositive = "PREPAYMENT_AMOUNT_NOT_POSITIVE"
+	ReasonPrepaymentDateMissing       = "PREPAYMENT_DATE_MISSING"
+	ReasonPrepaymentDateBeforeIssue   = "PREPAYMENT_DATE_BEFORE_ISSUE"
+	ReasonPrepaymentScheduleNegative  = "PREPAYMENT_SCHEDULE_NEGATIVE"
+	ReasonLoanInvalid                 = "LOAN_INVALID"
+	ReasonPrepaymentNotApplicable     = "PREPAYMENT_NOT_APPLICABLE"
+)
+
+// prepaymentEvent — досрочный платеж, привязанный к номеру планового платежа
+type prepaymentEvent struct {
+	amount money.Money
+	mode   entities.PrepaymentMode
+}
+
+// Prepay пересчитывает график сохраненного расчета или кредита по параметрам с досрочными платежами
+// и сравнивает его с исходным расчетом. Результат в кеш не сохраняется.
+func (ls *LoanServiceServer) Prepay(ctx context.Context, req *entities.PrepaymentRequest) (*entities.PrepaymentResult, error) {
+	ctx = ls.pin(ctx)
+	original, err := ls.prepaymentLoan(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	params := original.GetParams()
+	calendar := paymentCalendar{issue: params.GetIssueDate().AsTime(), day: int(params.PaymentDay)}
+	if calendar.day == 0 {
+		calendar.day = calendar.issue.Day()
+	}
+	plan, err := prepaymentPlan(req.Prepayments, calendar, params.Months)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregates := original.GetAggregates()
+	loanSum := money.FromRubles(aggregates.GetLoanSum())
+	if exact := aggregates.GetExact(); exact != nil {
+		loanSum = money.FromProto(exact.LoanSum)
+	}
+	rates := aggregateRates(aggregates)
+	if err := validatePrepaymentLoan(loanSum, rates, params.Months); err != nil {
+		return nil, err
+	}
+	rows, err := ls.buildPrepaymentSchedule(loanSum, rates, params.Months, original.PaymentType, calendar, plan)
+	if err != nil {
+		return nil, prepaymentViolation(err)
+	}
+
+	res := &entities.PrepaymentResult{
+		Params:      params,
+		Program:     original.Program,
+		PaymentType: original.PaymentType,
+		Original:    aggregates,
+		Aggregates:  scheduleAggregates(loanSum, rates, rows),
+		Rows:        scheduleProto(rows),
+		MonthsSaved: params.Months - int64(len(rows)),
+	}
+	if err := addFullCost(res.Aggregates, params.Costs, loanSum, rows, calendar); err != nil {
+		return nil, prepaymentViolation(err)
+	}
+	saved := money.FromProto(aggregates.GetExact().GetOverpayment()) - money.FromProto(res.Aggregates.Exact.Overpayment)
+	res.InterestSaved = saved.Proto()
+	return res, nil
+}
+
+// prepaymentLoan возвращает исходный расчет: из кеша по id или посчитанный по параметрам
+func (ls *LoanServiceServer) prepaymentLoan(ctx context.Context, req *entities.PrepaymentRequest) (*entities.LoanResult, error) {
+	switch loan := req.Loan.(type) {
+	case *entities.PrepaymentRequest_CalculationId:
+		if err := validateID(loan.CalculationId); err != nil {
+			return nil, err
+		}
+		res, err := ls.repo.Get(loan.CalculationId)
+		if err != nil {
+			return nil, cacheError(loan.CalculationId, err)
+		}
+		return res, nil
+	case *entities.PrepaymentRequest_Request:
+		res, _, err := ls.calculate(ctx, loan.Request)
+		return res, err
+	default:
+		var v violations
+		v.add("loan", ReasonLoanNotSelected, "choose calculation_id or request")
+		return nil, v.err()
+	}
+}
+
+// validatePrepaymentLoan проверяет исходный расчет: сохраненный в кеше расчет мог быть
+// сделан без сумм и ставки, по которым строится график
+func validatePrepaymentLoan(loanSum money.Money, rates rateSchedule, months int64) error {
+	var v violations
+	if loanSum <= 0 {
+		v.add("loan", ReasonLoanInvalid, "loan sum of the calculation should be positive")
+	}
+	if months <= 0 || months > maxMonths {
+		v.add("loan", ReasonLoanInvalid, "months of the calculation should be in 1..%d", maxMonths)
+	}
+	for _, p := range rates {
+		if p.rate <= 0 {
+			v.add("loan", ReasonLoanInvalid, "rate of the calculation should be positive")
+			break
+		}
+	}
+	return v.err()
+}
+
+// prepaymentViolation переводит ошибку пересчета графика в InvalidArgument:
+// с такими досрочными платежами график по этому кредиту не строится
+func prepaymentViolation(err error) error {
+	var v violations
+	v.add("prepayments", ReasonPrepaymentNotApplicable, "prepayments cannot be applied: %v", err)
+	return v.err()
+}
+
+// prepaymentPlan проверяет досрочные платежи и раскладывает их по номерам плановых платежей
+// до months включительно. Платеж вносится с ближайшим плановым платежом не раньше своей даты,
+// регулярный повторяется каждые every_months платежей. Платежи после конца графика не учитываются.
+func prepaymentPlan(prepayments []*entities.Prepayment, calendar paymentCalendar, months int64) (map[int64][]prepaymentEvent, error) {
+	var v violations
+	for i, p := range prepayments {
+		field := fmt.Sprintf("prepayments[%d]", i)
+		if money.FromProto(p.Amount) <= 0 {
+			v.add(field+".amount", ReasonPrepaymentAmountNotPositive, "prepayment amount should be positive")
+		}
+		if p.Date == nil {
+			v.add(field+".date", ReasonPrepaymentDateMissing, "prepayment date is required")
+		} else if dateOnly(p.Date.AsTime()).Before(calendar.issue) {
+			v.add(field+".date", ReasonPrepaymentDateBeforeIssue, "prepayment date should not be before the issue date")
+		}
+		if p.EveryMonths < 0 {
+			v.add(field+".every_months", ReasonPrepaymentScheduleNegative, "every_months should not be negative")
+		}
+		if p.Count < 0 {
+			v.add(field+".count", ReasonPrepaymentScheduleNegative, "count should not be negative")
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	plan := make(map[int64][]prepaymentEvent)
+	for _, p := range prepayments {
+		date := dateOnly(p.Date.AsTime())
+		month := int64(1)
+		for month <= months && calendar.date(month).Before(date) {
+			month++
+		}
+		event := prepaymentEvent{amount: money.FromProto(p.Amount), mode: p.Mode}
+		for n := int32(0); month <= months; n++ {
+			if p.Count > 0 && n == p.Count {
+				break
+			}
+			plan[month] = append(plan[month], event)
+			if p.EveryMonths == 0 {
+				break
+			}
+			month += int64(p.EveryMonths)
+		}
+	}
+	return plan, nil
+}
+
+// buildPrepaymentSchedule строит график с досрочными платежами. После платежа с REDUCE_TERM
+// прежний платеж (или часть долга в дифференцированном платеже) сохраняется и срок сокращается,
+// с REDUCE_PAYMENT платеж пересчитывается на оставшийся срок. С началом нового периода ставки
+// аннуитет пересчитывается на остаток долга и срока, поэтому без досрочных платежей
+// так же строится график со ступенчатой ставкой.
+func (ls *LoanServiceServer) buildPrepaymentSchedule(loanSum money.Money, rates rateSchedule, months int64, paymentType entities.PaymentType, calendar paymentCalendar, plan map[int64][]prepaymentEvent) ([]scheduleRow, error) {
+	if loanSum <= 0 {
+		return nil, fmt.Errorf("buildPrepaymentSchedule:Zero loan sum")
+	}
+	if months <= 0 || months > maxMonths {
+		return nil, fmt.Errorf("buildPrepaymentSchedule:months out of range")
+	}
+	for _, p := range rates {
+		if p.rate <= 0 {
+			return nil, fmt.Errorf("buildPrepaymentSchedule:rate less than 0.00")
+		}
+	}
+
+	annualRate := rates[0].rate
+	rate := annualRate.Monthly()
+	differentiated := paymentType == entities.PaymentType_DIFFERENTIATED
+	// payment — аннуитетный платеж, basePrincipal — часть долга в дифференцированном платеже
+	var payment, basePrincipal money.Money
+	var err error
+	if differentiated {
+		basePrincipal = loanSum / money.Money(months)
+	} else if payment, err = ls.calculateMonthlyPayment(loanSum, annualRate, months); err != nil {
+		return nil, err
+	}
+
+	balance, last := loanSum, months
+	rows := make([]scheduleRow, 0, months)
+	for month := int64(1); balance > 0; month++ {
+		if rates.changesAt(month) {
+			annualRate = rates.at(month)
+			rate = annualRate.Monthly()
+			if !differentiated {
+				if payment, err = ls.calculateMonthlyPayment(balance, annualRate, last-month+1); err != nil {
+					return nil, err
+				}
+			}
+		}
+		interest, err := balance.Mul(rate, money.HalfUp)
+		if err != nil {
+			return nil, fmt.Errorf("buildPrepaymentSchedule:%v", err)
+		}
+		principal := payment - interest
+		if differentiated {
+			principal = basePrincipal
+		}
+		if principal > balance || month == last {
+			principal = balance
+		}
+		balance -= principal
+		row := scheduleRow{
+			month:     month,
+			date:      calendar.date(month),
+			payment:   principal + interest,
+			interest:  interest,
+			principal: principal,
+		}
+
+		for _, e := range plan[month] {
+			if balance == 0 {
+				break
+			}
+			extra := min(e.amount, balance)
+			balance -= extra
+			row.prepayment += extra
+			if balance == 0 {
+				break
+			}
+
+			remaining := last - month
+			switch {
+			case e.mode == entities.PrepaymentMode_REDUCE_PAYMENT && differentiated:
+				basePrincipal = balance / money.Money(remaining)
+			case e.mode == entities.PrepaymentMode_REDUCE_PAYMENT:
+				if payment, err = ls.calculateMonthlyPayment(balance, annualRate, remaining); err != nil {
+					return nil, err
+				}
+			case differentiated && basePrincipal > 0:
+				last = month + int64((balance+basePrincipal-1)/basePrincipal)
+			case !differentiated:
+				if remaining, err = annuityTerm(balance, rate, payment); err != nil {
+					return nil, err
+				}
+				last = month + remaining
+			}
+		}
+		row.balance = balance
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// annuityTerm считает, за сколько платежей прежнего размера гасится остаток долга,
+// с тем же округлением процентов, что и в графике
+func annuityTerm(balance money.Money, rate *big.Rat, payment money.Money) (int64, error) {
+	for n := int64(1); n <= maxMonths; n++ {
+		interest, err := balance.Mul(rate, money.HalfUp)
+		if err != nil {
+			return 0, fmt.Errorf("annuityTerm:%v", err)
+		}
+		principal := payment - interest
+		if principal <= 0 {
+			return 0, fmt.Errorf("annuityTerm:payment does not cover interest")
+		}
+		if principal >= balance {
+			return n, nil
+		}
+		balance -= principal
+	}
+	return 0, fmt.Errorf("annuityTerm:months too large")
+}
+
+// dateOnly отбрасывает время, оставляя дату в UTC
+func dateOnly(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,75 @@
+package loanservice
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// programPricing — откуда взялась ставка расчета: версия таблицы ставок и ключевая ставка
+type programPricing struct {
+	rateVersion string
+	keyRate     *entities.KeyRateInfo
+}
+
+// pricedLoan — проверенный запрос с календарем и программой по ставке на дату выдачи.
+// Считается один раз за запрос, чтобы ключ мемоизации и расчет видели одну и ту же ставку.
+type pricedLoan struct {
+	calendar paymentCalendar
+	program  db.Program
+	pricing  programPricing
+}
+
+// priceLoan строит календарь платежей запроса и ставку программы на дату выдачи
+func (ls *LoanServiceServer) priceLoan(ctx context.Context, req *entities.LoanRequest, program db.Program) (pricedLoan, error) {
+	calendar, err := ls.newPaymentCalendar(req)
+	if err != nil {
+		return pricedLoan{}, err
+	}
+	program, pricing, err := ls.priceProgram(ctx, program, calendar.issue)
+	if err != nil {
+		return pricedLoan{}, err
+	}
+	return pricedLoan{calendar: calendar, program: program, pricing: pricing}, nil
+}
+
+// priceProgram берет ставку программы из версии таблицы ставок, действующей на дату выдачи,
+// а в ставку плавающей программы подставляет ключевую ставку на эту дату плюс надбавку.
+// Программу из реестра нужно передавать каждый раз заново: повторный вызов прибавит надбавку еще раз.
+func (ls *LoanServiceServer) priceProgram(ctx context.Context, program db.Program, issue time.Time) (db.Program, programPricing, error) {
+	rates := ls.snapshot(ctx)
+	var pricing programPricing
+	program, pricing.rateVersion = rates.programs.ProgramAt(program, issue)
+	if !program.Floating() {
+		return program, pricing, nil
+	}
+	if rates.keyRates == nil {
+		return program, pricing, status.Errorf(codes.FailedPrecondition, "program %q has a floating rate, but no key rate source is configured", program.Code)
+	}
+
+	keyRate, err := rates.keyRates.KeyRate(ctx, issue)
+	if errors.Is(err, db.ErrNoKeyRate) {
+		return program, pricing, status.Errorf(codes.FailedPrecondition, "key rate is unknown: %v", err)
+	}
+	if err != nil {
+		return program, pricing, status.Errorf(codes.Unavailable, "key rate is unavailable: %v", err)
+	}
+
+	program.AnnualRate = keyRate.Rate + program.Spread
+	if program.AnnualRate <= 0 {
+		return program, pricing, status.Errorf(codes.FailedPrecondition, "floating rate of program %q is not positive", program.Code)
+	}
+	pricing.keyRate = &entities.KeyRateInfo{
+		KeyRateBps: int64(keyRate.Rate),
+		Date:       timestamppb.New(keyRate.Date),
+		SpreadBps:  int64(program.Spread),
+		Stale:      keyRate.Stale,
+	}
+	return program, pricing, nil
+}
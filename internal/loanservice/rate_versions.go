@@ -0,0 +1,99 @@
+package loanservice
+
+import (
+	"context"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Машиночитаемая причина ошибки в запросе сравнения версий ставок
+const ReasonRateVersionMissing = "RATE_VERSION_MISSING"
+
+// RateVersions возвращает версии таблицы ставок с действующими ставками всех программ
+func (ls *LoanServiceServer) RateVersions(ctx context.Context, req *emptypb.Empty) (*entities.RateVersionList, error) {
+	programs := ls.snapshot(ctx).programs
+	versions := programs.Versions()
+	res := &entities.RateVersionList{Versions: make([]*entities.RateVersion, 0, len(versions))}
+	for _, v := range versions {
+		res.Versions = append(res.Versions, rateVersionProto(programs, v))
+	}
+	return res, nil
+}
+
+// DiffRateVersions сравнивает две версии таблицы ставок и возвращает программы с изменившейся ставкой
+func (ls *LoanServiceServer) DiffRateVersions(ctx context.Context, req *entities.RateVersionDiffRequest) (*entities.RateVersionDiff, error) {
+	var v violations
+	if req.From == "" {
+		v.add("from", ReasonRateVersionMissing, "from version is required")
+	}
+	if req.To == "" {
+		v.add("to", ReasonRateVersionMissing, "to version is required")
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	programs := ls.snapshot(ctx).programs
+	from, ok := programs.Version(req.From)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "rate version %q not found", req.From)
+	}
+	to, ok := programs.Version(req.To)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "rate version %q not found", req.To)
+	}
+
+	res := &entities.RateVersionDiff{
+		From: rateVersionProto(programs, from),
+		To:   rateVersionProto(programs, to),
+	}
+	for _, c := range programs.DiffVersions(from, to) {
+		res.Changes = append(res.Changes, &entities.ProgramRateChange{
+			Code: c.Code,
+			From: programRateProto(c.Code, c.From),
+			To:   programRateProto(c.Code, c.To),
+		})
+	}
+	return res, nil
+}
+
+// rateVersionProto переводит версию в сообщение API с записанными в ней ставками в порядке реестра,
+// включая выключенные программы — как в сравнении версий
+func rateVersionProto(programs *db.ProgramRegistry, v db.RateVersion) *entities.RateVersion {
+	res := &entities.RateVersion{
+		Id:            v.ID,
+		EffectiveFrom: timestamppb.New(v.EffectiveFrom),
+	}
+	if !v.EffectiveTo.IsZero() {
+		res.EffectiveTo = timestamppb.New(v.EffectiveTo)
+	}
+	rates := programs.VersionRates(v)
+	for _, p := range programs.All() {
+		if rate, ok := rates[p.Code]; ok {
+			res.Rates = append(res.Rates, programRateProto(p.Code, rate))
+		}
+	}
+	return res
+}
+
+// programRateProto переводит ставку программы в сообщение API
+func programRateProto(code string, r db.ProgramRate) *entities.ProgramRate {
+	res := &entities.ProgramRate{
+		Code:      code,
+		RateBps:   int64(r.AnnualRate),
+		RateType:  r.RateType,
+		SpreadBps: int64(r.Spread),
+	}
+	if res.RateType == "" {
+		res.RateType = db.RateFixed
+	}
+	for _, p := range r.RatePeriods {
+		res.RatePeriods = append(res.RatePeriods, &entities.RatePeriod{Months: p.Months, RateBps: int64(p.AnnualRate)})
+	}
+	return res
+}
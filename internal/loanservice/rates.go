@@ -0,0 +1,144 @@
+package loanservice
+
+import (
+	"fmt"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+	"github.com/Dorji/sberInterview/internal/money"
+)
+
+// Машиночитаемые причины ошибок в периодах ступенчатой ставки
+const (
+	ReasonRatePeriodMonthsNotPositive = "RATE_PERIOD_MONTHS_NOT_POSITIVE"
+	ReasonRatePeriodRateNotPositive   = "RATE_PERIOD_RATE_NOT_POSITIVE"
+)
+
+// ratePeriod — годовая ставка, действующая с платежа from
+type ratePeriod struct {
+	from int64
+	rate money.BasisPoints
+}
+
+// rateSchedule — ставки кредита по периодам в порядке начала, первый период начинается с первого платежа.
+// Соседние периоды всегда с разными ставками, поэтому ставка ступенчатая, только если периодов больше одного.
+type rateSchedule []ratePeriod
+
+// fixedRate возвращает одну ставку на весь срок
+func fixedRate(rate money.BasisPoints) rateSchedule {
+	return rateSchedule{{from: 1, rate: rate}}
+}
+
+// newRateSchedule раскладывает периоды ступенчатой ставки по номерам платежей в пределах срока months,
+// после последнего периода действует ставка base
+func newRateSchedule(base money.BasisPoints, periods []db.RatePeriod, months int64) rateSchedule {
+	res := make(rateSchedule, 0, len(periods)+1)
+	add := func(from int64, rate money.BasisPoints) {
+		if from > months || (len(res) > 0 && res[len(res)-1].rate == rate) {
+			return
+		}
+		res = append(res, ratePeriod{from: from, rate: rate})
+	}
+
+	from := int64(1)
+	for _, p := range periods {
+		add(from, p.AnnualRate)
+		if p.Months == 0 {
+			return res
+		}
+		from += p.Months
+	}
+	add(from, base)
+	return res
+}
+
+// loanRates возвращает ставки кредита: периоды из запроса заменяют периоды программы
+func loanRates(req *entities.LoanRequest, program db.Program) rateSchedule {
+	periods := program.RatePeriods
+	if len(req.RatePeriods) > 0 {
+		periods = make([]db.RatePeriod, 0, len(req.RatePeriods))
+		for _, p := range req.RatePeriods {
+			periods = append(periods, db.RatePeriod{Months: p.Months, AnnualRate: money.BasisPoints(p.RateBps)})
+		}
+	}
+	return newRateSchedule(program.AnnualRate, periods, req.Months)
+}
+
+// aggregateRates восстанавливает ставки сохраненного расчета из его агрегатов
+func aggregateRates(aggregates *entities.LoanAggregates) rateSchedule {
+	if len(aggregates.GetRatePeriods()) == 0 {
+		return fixedRate(money.BasisPoints(aggregates.GetRateBps()))
+	}
+	res := make(rateSchedule, 0, len(aggregates.RatePeriods))
+	for _, p := range aggregates.RatePeriods {
+		res = append(res, ratePeriod{from: p.FromMonth, rate: money.BasisPoints(p.RateBps)})
+	}
+	return res
+}
+
+// at возвращает ставку платежа month
+func (r rateSchedule) at(month int64) money.BasisPoints {
+	rate := r[0].rate
+	for _, p := range r[1:] {
+		if p.from > month {
+			break
+		}
+		rate = p.rate
+	}
+	return rate
+}
+
+// changesAt сообщает, начинается ли с платежа month новый период ставки
+func (r rateSchedule) changesAt(month int64) bool {
+	for _, p := range r[1:] {
+		if p.from == month {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRatePeriods проверяет периоды ступенчатой ставки из запроса
+func validateRatePeriods(v *violations, periods []*entities.RatePeriod) {
+	for i, p := range periods {
+		field := fmt.Sprintf("rate_periods[%d]", i)
+		if p.Months < 0 || (p.Months == 0 && i != len(periods)-1) {
+			v.add(field+".months", ReasonRatePeriodMonthsNotPositive, "rate period months should be positive, only the last period may be open")
+		}
+		if p.RateBps <= 0 {
+			v.add(field+".rate_bps", ReasonRatePeriodRateNotPositive, "rate period rate should be positive")
+		}
+	}
+}
+
+// ratePeriodsProto сводит график по периодам ставки. Для постоянной ставки возвращает nil.
+// Строки графика идут подряд с первого платежа, поэтому номер платежа — индекс строки плюс один.
+func ratePeriodsProto(rates rateSchedule, rows []scheduleRow) []*entities.RatePeriodSummary {
+	if len(rates) < 2 {
+		return nil
+	}
+	last := int64(len(rows))
+	res := make([]*entities.RatePeriodSummary, 0, len(rates))
+	for i, p := range rates {
+		// После досрочного погашения график может закончиться раньше следующих периодов
+		if p.from > last {
+			break
+		}
+		to := last
+		if i+1 < len(rates) {
+			to = min(rates[i+1].from-1, last)
+		}
+		var interest money.Money
+		for _, row := range rows[p.from-1 : to] {
+			interest += row.interest
+		}
+		res = append(res, &entities.RatePeriodSummary{
+			FromMonth: p.from,
+			ToMonth:   to,
+			RateBps:   int64(p.rate),
+			Payment:   rows[p.from-1].payment.Proto(),
+			Interest:  interest.Proto(),
+		})
+	}
+	return res
+}
@@ -0,0 +1,136 @@
+package loanservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"github.com/Dorji/sberInterview/internal/money"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// scheduleRow — строка графика платежей в копейках
+type scheduleRow struct {
+	month     int64
+	date      time.Time
+	payment   money.Money
+	interest  money.Money
+	principal money.Money
+	balance   money.Money
+	// досрочный платеж сверх планового, уже вычтен из balance
+	prepayment money.Money
+}
+
+// Schedule возвращает помесячный график платежей по выбранной схеме погашения.
+// Результат в кеш не сохраняется.
+func (ls *LoanServiceServer) Schedule(ctx context.Context, req *entities.LoanRequest) (*entities.ScheduleResult, error) {
+	ctx = ls.pin(ctx)
+	res, rows, err := ls.calculate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.ScheduleResult{
+		Params:     res.Params,
+		Program:    res.Program,
+		Aggregates: res.Aggregates,
+		Rows:       scheduleProto(rows),
+	}, nil
+}
+
+// buildAnnuitySchedule раскладывает аннуитетный платеж на проценты и основной долг.
+// Проценты округляются до копейки каждый месяц, а последний платеж корректируется
+// на накопленную разницу, чтобы график в сумме давал ровно долг плюс проценты.
+func (ls *LoanServiceServer) buildAnnuitySchedule(loanSum money.Money, annualRate money.BasisPoints, months int64, payment money.Money, calendar paymentCalendar) ([]scheduleRow, error) {
+	rate := annualRate.Monthly()
+	balance := loanSum
+
+	rows := make([]scheduleRow, 0, months)
+	for month := int64(1); month <= months; month++ {
+		interest, err := balance.Mul(rate, money.HalfUp)
+		if err != nil {
+			return nil, fmt.Errorf("buildAnnuitySchedule:%v", err)
+		}
+		principal := payment - interest
+		if principal > balance || month == months {
+			principal = balance
+		}
+		balance -= principal
+
+		rows = append(rows, scheduleRow{
+			month:     month,
+			date:      calendar.date(month),
+			payment:   principal + interest,
+			interest:  interest,
+			principal: principal,
+			balance:   balance,
+		})
+	}
+	return rows, nil
+}
+
+// buildDifferentiatedSchedule строит график дифференцированных платежей:
+// основной долг гасится равными частями, проценты начисляются на остаток.
+// Остаток от деления суммы кредита на срок уходит в последний платеж.
+func (ls *LoanServiceServer) buildDifferentiatedSchedule(loanSum money.Money, annualRate money.BasisPoints, months int64, calendar paymentCalendar) ([]scheduleRow, error) {
+	if loanSum <= 0 {
+		return nil, fmt.Errorf("buildDifferentiatedSchedule:Zero loan sum")
+	}
+	if months <= 0 {
+		return nil, fmt.Errorf("buildDifferentiatedSchedule:Zero months")
+	}
+	if months > maxMonths {
+		return nil, fmt.Errorf("buildDifferentiatedSchedule:months too large")
+	}
+	if annualRate <= 0 {
+		return nil, fmt.Errorf("buildDifferentiatedSchedule:rate less than 0.00")
+	}
+
+	rate := annualRate.Monthly()
+	basePrincipal := loanSum / money.Money(months)
+	balance := loanSum
+
+	rows := make([]scheduleRow, 0, months)
+	for month := int64(1); month <= months; month++ {
+		interest, err := balance.Mul(rate, money.HalfUp)
+		if err != nil {
+			return nil, fmt.Errorf("buildDifferentiatedSchedule:%v", err)
+		}
+		principal := basePrincipal
+		if month == months {
+			principal = balance
+		}
+		balance -= principal
+
+		rows = append(rows, scheduleRow{
+			month:     month,
+			date:      calendar.date(month),
+			payment:   principal + interest,
+			interest:  interest,
+			principal: principal,
+			balance:   balance,
+		})
+	}
+	return rows, nil
+}
+
+// scheduleProto переводит график в сообщения API
+func scheduleProto(rows []scheduleRow) []*entities.ScheduleRow {
+	res := make([]*entities.ScheduleRow, 0, len(rows))
+	for _, row := range rows {
+		r := &entities.ScheduleRow{
+			Month:       row.month,
+			PaymentDate: timestamppb.New(row.date),
+			Payment:     row.payment.Proto(),
+			Interest:    row.interest.Proto(),
+			Principal:   row.principal.Proto(),
+			Balance:     row.balance.Proto(),
+		}
+		if row.prepayment != 0 {
+			r.Prepayment = row.prepayment.Proto()
+		}
+		res = append(res, r)
+	}
+	return res
+}
@@ -0,0 +1,43 @@
+package loanservice
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+)
+
+// rateSnapshot — настройки расчета, которые заменяются при перезагрузке конфига без перезапуска
+type rateSnapshot struct {
+	programs *db.ProgramRegistry
+	keyRates db.KeyRateProvider
+}
+
+// snapshotKey — ключ настроек, закрепленных за запросом в контексте
+type snapshotKey struct{}
+
+// Reload атомарно подменяет реестр программ и источник ключевой ставки.
+// Запросы, начатые до подмены, досчитываются на прежних настройках.
+func (ls *LoanServiceServer) Reload(programs *db.ProgramRegistry, keyRates db.KeyRateProvider) error {
+	if programs == nil {
+		return fmt.Errorf("Reload:nil program registry")
+	}
+	ls.rates.Store(&rateSnapshot{programs: programs, keyRates: keyRates})
+	return nil
+}
+
+// pin закрепляет за запросом текущие настройки, чтобы все шаги расчета видели один и тот же реестр
+func (ls *LoanServiceServer) pin(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(snapshotKey{}).(*rateSnapshot); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, snapshotKey{}, ls.rates.Load())
+}
+
+// snapshot возвращает настройки, закрепленные за запросом, или текущие
+func (ls *LoanServiceServer) snapshot(ctx context.Context) *rateSnapshot {
+	if s, ok := ctx.Value(snapshotKey{}).(*rateSnapshot); ok {
+		return s
+	}
+	return ls.rates.Load()
+}
@@ -0,0 +1,200 @@
+package loanservice
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+	"github.com/Dorji/sberInterview/internal/money"
+)
+
+// Машиночитаемые причины, по которым у подбора нет решения
+const (
+	ReasonPaymentBelowInterest = "PAYMENT_BELOW_INTEREST"
+	ReasonNoSolution           = "NO_SOLUTION"
+)
+
+// Solve находит один параметр кредита по трем остальным: срок и взнос — наименьшие,
+// стоимость — наибольшую, при которых наибольший платеж графика укладывается в бюджет.
+// Найденный кредит проходит те же проверки, что и в Execute. Результат в кеш не сохраняется.
+func (ls *LoanServiceServer) Solve(ctx context.Context, req *entities.SolveRequest) (*entities.SolveResult, error) {
+	ctx = ls.pin(ctx)
+	loanReq := &entities.LoanRequest{
+		ObjectCost:     req.ObjectCost,
+		InitialPayment: req.InitialPayment,
+		Months:         req.Months,
+		Program:        req.Program,
+		PaymentType:    req.PaymentType,
+		IssueDate:      req.IssueDate,
+		PaymentDay:     req.PaymentDay,
+	}
+
+	if req.SolveFor != entities.SolveFor_SOLVE_MONTHLY_PAYMENT {
+		program, err := ls.validateSolve(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if program, _, err = ls.priceProgram(ctx, program, ls.issueDate(req.IssueDate)); err != nil {
+			return nil, err
+		}
+		budget := money.FromRubles(req.MonthlyPayment)
+		switch req.SolveFor {
+		case entities.SolveFor_SOLVE_MONTHS:
+			loanReq.Months, err = ls.solveMonths(budget, req.ObjectCost-req.InitialPayment, program, req.PaymentType)
+		case entities.SolveFor_SOLVE_INITIAL_PAYMENT:
+			loanReq.InitialPayment, err = ls.solveInitialPayment(budget, req, program)
+		case entities.SolveFor_SOLVE_OBJECT_COST:
+			loanReq.ObjectCost, err = ls.solveObjectCost(budget, req, program)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Платеж считается как в Execute, там же проверяются ограничения программы для найденного значения
+	res, _, err := ls.calculate(ctx, loanReq)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.SolveResult{
+		SolveFor:    req.SolveFor,
+		Params:      res.Params,
+		Program:     res.Program,
+		Aggregates:  res.Aggregates,
+		PaymentType: res.PaymentType,
+	}, nil
+}
+
+// validateSolve проверяет все поля, кроме искомого, и выбирает программу
+func (ls *LoanServiceServer) validateSolve(ctx context.Context, req *entities.SolveRequest) (db.Program, error) {
+	var v violations
+
+	if req.SolveFor != entities.SolveFor_SOLVE_OBJECT_COST {
+		if req.ObjectCost <= 0 {
+			v.add("object_cost", ReasonObjectCostNotPositive, "object cost should be positive")
+		} else if req.ObjectCost > maxAmount {
+			v.add("object_cost", ReasonObjectCostTooLarge, "object cost should be at most %d", int64(maxAmount))
+		}
+	}
+	if req.SolveFor != entities.SolveFor_SOLVE_INITIAL_PAYMENT {
+		if req.InitialPayment < 0 {
+			v.add("initial_payment", ReasonInitialPaymentNegative, "initial payment should not be negative")
+		} else if req.InitialPayment > maxAmount {
+			v.add("initial_payment", ReasonInitialPaymentTooLarge, "initial payment should be at most %d", int64(maxAmount))
+		} else if req.SolveFor != entities.SolveFor_SOLVE_OBJECT_COST && req.ObjectCost > 0 && req.InitialPayment >= req.ObjectCost {
+			v.add("initial_payment", ReasonInitialPaymentTooLarge, "initial payment should be less than object cost")
+		}
+	}
+	if req.SolveFor != entities.SolveFor_SOLVE_MONTHS {
+		if req.Months <= 0 {
+			v.add("months", ReasonMonthsNotPositive, "months should be positive")
+		} else if req.Months > maxMonths {
+			v.add("months", ReasonMonthsTooLarge, "months should be at most %d", maxMonths)
+		}
+	}
+	if req.MonthlyPayment <= 0 {
+		v.add("monthly_payment", ReasonMonthlyPaymentNotPositive, "monthly payment should be positive")
+	} else if req.MonthlyPayment > maxAmount {
+		v.add("monthly_payment", ReasonMonthlyPaymentTooLarge, "monthly payment should be at most %d", int64(maxAmount))
+	}
+	if req.PaymentDay < 0 || req.PaymentDay > 31 {
+		v.add("payment_day", ReasonPaymentDayOutOfRange, "payment day should be between 1 and 31")
+	}
+	program, _ := ls.resolveProgram(ctx, &v, req.Program)
+
+	return program, v.err()
+}
+
+// solveMonths ищет бинарным поиском наименьший срок в пределах программы, при котором платеж не больше бюджета.
+// Платеж убывает с ростом срока и стремится к процентам за месяц, поэтому бюджет не больше процентов
+// первого месяца решения не имеет. При ступенчатой ставке с бюджетом сравнивается наибольший платеж графика.
+func (ls *LoanServiceServer) solveMonths(budget money.Money, loanRubles int64, program db.Program, paymentType entities.PaymentType) (int64, error) {
+	loanSum := money.FromRubles(loanRubles)
+	interest, err := loanSum.Mul(program.InitialRate().Monthly(), money.HalfUp)
+	if err != nil {
+		return 0, err
+	}
+	var v violations
+	if budget <= interest {
+		v.add("monthly_payment", ReasonPaymentBelowInterest, "monthly payment should be more than the monthly interest %s", interest)
+		return 0, v.err()
+	}
+
+	lo, hi := max(program.MinMonths, 1), int64(maxMonths)
+	if program.MaxMonths > 0 {
+		hi = program.MaxMonths
+	}
+	// Первая ошибка расчета прерывает поиск и возвращается после него
+	var searchErr error
+	n := lo + int64(sort.Search(int(hi-lo+1), func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+		months := lo + int64(i)
+		payment, err := ls.maxPayment(loanSum, programRates(program, months), months, paymentType)
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return payment <= budget
+	}))
+	if searchErr != nil {
+		return 0, searchErr
+	}
+	if n > hi {
+		v.add("monthly_payment", ReasonNoSolution, "monthly payment is too small for the longest term of %d months", hi)
+		return 0, v.err()
+	}
+	return n, nil
+}
+
+// solveInitialPayment ищет наименьший взнос: стоимость за вычетом наибольшего кредита,
+// который укладывается в бюджет, но не меньше минимальной доли программы
+func (ls *LoanServiceServer) solveInitialPayment(budget money.Money, req *entities.SolveRequest, program db.Program) (int64, error) {
+	loanSum, err := ls.maxLoanSum(budget, programRates(program, req.Months), req.Months, req.PaymentType)
+	if err != nil {
+		return 0, err
+	}
+	if program.MaxLoanSum > 0 {
+		loanSum = min(loanSum, program.MaxLoanSum)
+	}
+	down := max(req.ObjectCost-loanSum, 0)
+
+	// Взнос по доле программы: стоимость минус наибольший кредит, для которого доли хватает
+	share := minInitialShare(program)
+	minDown, err := money.FromRubles(req.ObjectCost).Mul(share, money.Ceil)
+	if err != nil {
+		return 0, err
+	}
+	down = max(down, minDown.Rubles(money.Ceil))
+
+	if down >= req.ObjectCost {
+		var v violations
+		v.add("monthly_payment", ReasonNoSolution, "monthly payment is too small for this term")
+		return 0, v.err()
+	}
+	return down, nil
+}
+
+// solveObjectCost ищет наибольшую стоимость: взнос плюс наибольший кредит, который
+// укладывается в бюджет, ограничения программы по сумме и доле взноса
+func (ls *LoanServiceServer) solveObjectCost(budget money.Money, req *entities.SolveRequest, program db.Program) (int64, error) {
+	loanSum, err := ls.maxLoanSum(budget, programRates(program, req.Months), req.Months, req.PaymentType)
+	if err != nil {
+		return 0, err
+	}
+	if program.MaxLoanSum > 0 {
+		loanSum = min(loanSum, program.MaxLoanSum)
+	}
+	if byDown, ok := maxLoanByInitialPayment(req.InitialPayment, minInitialShare(program)); ok {
+		loanSum = min(loanSum, byDown)
+	}
+
+	if loanSum <= 0 {
+		var v violations
+		v.add("monthly_payment", ReasonNoSolution, "no loan fits the monthly payment and the initial payment")
+		return 0, v.err()
+	}
+	return req.InitialPayment + loanSum, nil
+}
@@ -0,0 +1,340 @@
+package storage
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"google.golang.org/protobuf/proto"
+)
+
+// FileStore хранит расчеты в одном файле: записи CacheRecord в том же формате, что и журнал кеша.
+// В памяти держится только индекс с полями для фильтров, сами расчеты читаются с диска.
+// Удаление дописывает запись-надгробие, место освобождается сжатием при открытии файла.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	end  int64 // конец последней целой записи
+	live int64 // байт в записях живых расчетов
+
+	entries []fileEntry // упорядочены по id
+	keys    map[string]int64
+	nextID  int64
+
+	stats CacheStats
+}
+
+type fileEntry struct {
+	item   listItem
+	key    string
+	offset int64
+	size   int64
+}
+
+// OpenFileStore открывает файл хранилища и строит индекс. Если файл поврежден, битый хвост
+// обрезается и вместе с рабочим хранилищем возвращается ошибка, оборачивающая ErrCorrupted.
+func OpenFileStore(path string) (*FileStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("OpenFileStore:%w", err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("OpenFileStore:%w", err)
+	}
+	s := &FileStore{path: path, f: f, keys: make(map[string]int64)}
+
+	loadErr := s.load()
+	if loadErr != nil && !errors.Is(loadErr, ErrCorrupted) {
+		f.Close()
+		return nil, fmt.Errorf("OpenFileStore:%w", loadErr)
+	}
+	// Сжимаем, когда надгробия и удаленные расчеты занимают больше половины файла
+	if s.end > 2*s.live {
+		if err := s.compact(); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("OpenFileStore:%w", err)
+		}
+	}
+	if loadErr != nil {
+		return s, fmt.Errorf("OpenFileStore:%w", loadErr)
+	}
+	return s, nil
+}
+
+// load читает все записи файла и строит индекс
+func (s *FileStore) load() error {
+	r := bufio.NewReader(io.NewSectionReader(s.f, 0, 1<<62))
+	for {
+		rec := &entities.CacheRecord{}
+		n, err := decodeFrame(r, rec)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			if terr := s.f.Truncate(s.end); terr != nil {
+				return terr
+			}
+			return fmt.Errorf("%w: record at offset %d: %v", ErrCorrupted, s.end, err)
+		}
+		s.apply(rec, s.end, n)
+		s.end += n
+	}
+}
+
+// apply обновляет индекс по записи, лежащей в файле по смещению offset
+func (s *FileStore) apply(rec *entities.CacheRecord, offset, size int64) {
+	switch op := rec.Op.(type) {
+	case *entities.CacheRecord_Add:
+		result := op.Add.GetResult()
+		if result == nil || result.Id < s.nextID {
+			return
+		}
+		s.entries = append(s.entries, fileEntry{item: newListItem(result), key: op.Add.Key, offset: offset, size: size})
+		if op.Add.Key != "" {
+			s.keys[op.Add.Key] = result.Id
+		}
+		s.live += size
+		s.nextID = result.Id + 1
+	case *entities.CacheRecord_Delete:
+		if i, ok := s.index(op.Delete); ok {
+			s.remove(i)
+		}
+		s.nextID = max(s.nextID, op.Delete+1)
+	case *entities.CacheRecord_Clear:
+		for len(s.entries) > 0 && s.entries[0].item.id < op.Clear {
+			s.remove(0)
+		}
+		s.nextID = max(s.nextID, op.Clear)
+	}
+}
+
+// write дописывает запись в конец файла и применяет ее к индексу
+func (s *FileStore) write(rec *entities.CacheRecord) error {
+	frame, err := encodeFrame(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := s.f.WriteAt(frame, s.end); err != nil {
+		return err
+	}
+	offset := s.end
+	s.end += int64(len(frame))
+	s.apply(rec, offset, int64(len(frame)))
+	return nil
+}
+
+// Save сохраняет расчет в файл
+func (s *FileStore) Save(key string, result *entities.LoanResult) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id, ok := s.keys[key]; key != "" && ok {
+		return id, nil
+	}
+	stored := proto.Clone(result).(*entities.LoanResult)
+	stored.Id = s.nextID
+	rec := &entities.CacheRecord{Op: &entities.CacheRecord_Add{Add: &entities.CacheEntry{Result: stored, Key: key}}}
+	if err := s.write(rec); err != nil {
+		s.stats.PersistErrors++
+		return 0, fmt.Errorf("FileStore.Save:%w", err)
+	}
+	return stored.Id, nil
+}
+
+// FindByKey ищет расчет по ключу мемоизации
+func (s *FileStore) FindByKey(key string) (*entities.LoanResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.keys[key]
+	if !ok {
+		s.stats.MemoMisses++
+		return nil, ErrNotFound
+	}
+	s.stats.MemoHits++
+	i, _ := s.index(id)
+	return s.read(s.entries[i])
+}
+
+// Get читает расчет по id. Файловое хранилище ничего не вытесняет, поэтому отсутствующий расчет — ErrNotFound.
+func (s *FileStore) Get(id int64) (*entities.LoanResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, ok := s.index(id)
+	if !ok {
+		s.stats.NotFoundLookups++
+		return nil, ErrNotFound
+	}
+	return s.read(s.entries[i])
+}
+
+// List строит страницу по индексу и читает с диска только ее расчеты
+func (s *FileStore) List(q Query) ([]*entities.LoanResult, *Cursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := make([]listItem, 0, len(s.entries))
+	for _, e := range s.entries {
+		items = append(items, e.item)
+	}
+	ids, next := q.page(items)
+	page := make([]*entities.LoanResult, 0, len(ids))
+	for _, id := range ids {
+		i, _ := s.index(id)
+		result, err := s.read(s.entries[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		page = append(page, result)
+	}
+	return page, next, nil
+}
+
+// Delete дописывает надгробие удаленного расчета
+func (s *FileStore) Delete(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.index(id); !ok {
+		s.stats.NotFoundLookups++
+		return ErrNotFound
+	}
+	if err := s.write(&entities.CacheRecord{Op: &entities.CacheRecord_Delete{Delete: id}}); err != nil {
+		s.stats.PersistErrors++
+		return fmt.Errorf("FileStore.Delete:%w", err)
+	}
+	return nil
+}
+
+// Clear удаляет все расчеты, id продолжают расти
+func (s *FileStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.write(&entities.CacheRecord{Op: &entities.CacheRecord_Clear{Clear: s.nextID}}); err != nil {
+		s.stats.PersistErrors++
+		return fmt.Errorf("FileStore.Clear:%w", err)
+	}
+	return nil
+}
+
+// Size возвращает количество расчетов в хранилище
+func (s *FileStore) Size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// Stats возвращает счетчики хранилища, Bytes — размер файла
+func (s *FileStore) Stats() CacheStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	stats.Entries = len(s.entries)
+	stats.Bytes = s.end
+	return stats
+}
+
+// Close сбрасывает файл на диск и закрывает его
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.f.Sync(); err != nil {
+		s.f.Close()
+		return err
+	}
+	return s.f.Close()
+}
+
+func (s *FileStore) read(e fileEntry) (*entities.LoanResult, error) {
+	rec := &entities.CacheRecord{}
+	if _, err := decodeFrame(io.NewSectionReader(s.f, e.offset, e.size), rec); err != nil {
+		return nil, fmt.Errorf("%w: record %d: %v", ErrCorrupted, e.item.id, err)
+	}
+	return rec.GetAdd().GetResult(), nil
+}
+
+func (s *FileStore) remove(i int) {
+	e := s.entries[i]
+	if e.key != "" {
+		delete(s.keys, e.key)
+	}
+	s.live -= e.size
+	s.entries = append(s.entries[:i], s.entries[i+1:]...)
+}
+
+func (s *FileStore) index(id int64) (int, bool) {
+	i := sort.Search(len(s.entries), func(i int) bool { return s.entries[i].item.id >= id })
+	return i, i < len(s.entries) && s.entries[i].item.id == id
+}
+
+// compact переписывает файл только с живыми расчетами. Надгробие последнего id
+// сохраняет счетчик, чтобы id удаленных расчетов не выдавались повторно.
+func (s *FileStore) compact() error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	for _, e := range s.entries {
+		buf := make([]byte, e.size)
+		if _, err := s.f.ReadAt(buf, e.offset); err != nil {
+			tmp.Close()
+			return err
+		}
+		if _, err := w.Write(buf); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if n := len(s.entries); s.nextID > 0 && (n == 0 || s.entries[n-1].item.id < s.nextID-1) {
+		frame, err := encodeFrame(&entities.CacheRecord{Op: &entities.CacheRecord_Delete{Delete: s.nextID - 1}})
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+		if _, err := w.Write(frame); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.path, os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	s.f.Close()
+	s.f, s.end, s.live, s.nextID = f, 0, 0, 0
+	s.entries, s.keys = nil, make(map[string]int64)
+	return s.load()
+}
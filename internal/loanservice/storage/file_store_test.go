@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	result := func(months int64) *entities.LoanResult {
+		return &entities.LoanResult{Params: &entities.LoanParams{Months: months}}
+	}
+	months := func(s *FileStore) []int64 {
+		page, _, err := s.List(Query{})
+		noError(t, err)
+		res := []int64{}
+		for _, r := range page {
+			res = append(res, r.Params.Months)
+		}
+		return res
+	}
+
+	t.Run("Survives reopen", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "calculations.db")
+		s, err := OpenFileStore(path)
+		noError(t, err)
+		for i := int64(1); i <= 3; i++ {
+			_, err := s.Save("", result(i*12))
+			noError(t, err)
+		}
+		noError(t, s.Delete(2))
+		noError(t, s.Close())
+
+		s, err = OpenFileStore(path)
+		noError(t, err)
+		defer s.Close()
+		assert.Equal(t, []int64{12, 24}, months(s))
+		id, err := s.Save("", result(48))
+		noError(t, err)
+		assert.Equal(t, int64(3), id)
+	})
+
+	t.Run("Compaction keeps id counter", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "calculations.db")
+		s, err := OpenFileStore(path)
+		noError(t, err)
+		for i := int64(1); i <= 4; i++ {
+			_, err := s.Save("", result(i*12))
+			noError(t, err)
+		}
+		noError(t, s.Delete(1))
+		noError(t, s.Delete(2))
+		noError(t, s.Delete(3))
+		before := s.Stats().Bytes
+		noError(t, s.Close())
+
+		s, err = OpenFileStore(path)
+		noError(t, err)
+		defer s.Close()
+		assert.Less(t, s.Stats().Bytes, before)
+		assert.Equal(t, []int64{12}, months(s))
+		id, err := s.Save("", result(60))
+		noError(t, err)
+		assert.Equal(t, int64(4), id)
+	})
+
+	t.Run("Corrupted tail is reported", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "calculations.db")
+		s, err := OpenFileStore(path)
+		noError(t, err)
+		_, err = s.Save("", result(12))
+		noError(t, err)
+		_, err = s.Save("", result(24))
+		noError(t, err)
+		noError(t, s.Close())
+
+		data, err := os.ReadFile(path)
+		noError(t, err)
+		data[len(data)-1] ^= 0xff
+		noError(t, os.WriteFile(path, data, 0o644))
+
+		s, err = OpenFileStore(path)
+		assert.ErrorIs(t, err, ErrCorrupted)
+		if assert.NotNil(t, s) {
+			defer s.Close()
+			assert.Equal(t, []int64{12}, months(s))
+		}
+	})
+}
@@ -1,58 +1,256 @@
 package storage
 
 import (
+	"container/list"
+	"errors"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/Dorji/sberInterview/api/protos/entities"
-	"google.golang.org/protobuf/types/known/timestamppb"
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+	"google.golang.org/protobuf/proto"
 )
 
+var (
+	// ErrNotFound — расчета с таким id не было или он удален явно
+	ErrNotFound = errors.New("calculation not found")
+	// ErrExpired — расчет был в кеше, но вытеснен по лимитам или TTL
+	ErrExpired = errors.New("calculation expired")
+)
+
+// EvictionPolicy — порядок вытеснения при переполнении кеша
+type EvictionPolicy string
+
+const (
+	EvictLRU  EvictionPolicy = "lru"  // первым вытесняется давно не запрошенный расчет
+	EvictFIFO EvictionPolicy = "fifo" // первым вытесняется самый старый расчет
+)
+
+// CacheStats — счетчики кеша для метрик
+type CacheStats struct {
+	Entries          int   `json:"entries"`
+	Bytes            int64 `json:"bytes"`
+	EvictedByEntries int64 `json:"evicted_by_entries"`
+	EvictedByBytes   int64 `json:"evicted_by_bytes"`
+	EvictedByTTL     int64 `json:"evicted_by_ttl"`
+	ExpiredLookups   int64 `json:"expired_lookups"`
+	NotFoundLookups  int64 `json:"not_found_lookups"`
+	MemoHits         int64 `json:"memo_hits"`
+	MemoMisses       int64 `json:"memo_misses"`
+	PersistErrors    int64 `json:"persist_errors"`
+}
+
+type cacheEntry struct {
+	result *entities.LoanResult
+	key    string // ключ мемоизации, пустой — расчет не дедуплицируется
+	size   int64
+	added  time.Time
+	elem   *list.Element // позиция в очереди вытеснения
+}
+
 type LoanCache struct {
-	mu    sync.RWMutex
-	items []*entities.LoanResult
+	mu    sync.Mutex
+	items []*cacheEntry // упорядочены по id
+	order *list.List    // очередь вытеснения, спереди — следующий кандидат
+	bytes int64
+	keys  map[string]*cacheEntry // индекс для мемоизации
+
+	nextID       int64
+	deleted      []int64 // удаленные явно по возрастанию, чтобы не путать их с вытесненными, см. maxTombstones
+	clearedBelow int64   // id меньше этого удалены через Clear
+
+	maxEntries int
+	maxBytes   int64
+	ttl        time.Duration
+	policy     EvictionPolicy
+	now        func() time.Time
+	persist    *Persistence
+
+	stats CacheStats
+}
+
+// CacheOption настраивает LoanCache. Без опций кеш не ограничен.
+type CacheOption func(*LoanCache)
+
+// WithMaxEntries ограничивает количество расчетов в кеше
+func WithMaxEntries(n int) CacheOption {
+	return func(c *LoanCache) {
+		c.maxEntries = n
+	}
+}
+
+// WithMaxBytes ограничивает примерный размер кеша по размеру сериализованных расчетов
+func WithMaxBytes(n int64) CacheOption {
+	return func(c *LoanCache) {
+		c.maxBytes = n
+	}
+}
+
+// WithTTL задает время жизни расчета в кеше
+func WithTTL(ttl time.Duration) CacheOption {
+	return func(c *LoanCache) {
+		c.ttl = ttl
+	}
+}
+
+// WithEvictionPolicy задает порядок вытеснения, по умолчанию LRU
+func WithEvictionPolicy(policy EvictionPolicy) CacheOption {
+	return func(c *LoanCache) {
+		c.policy = policy
+	}
+}
+
+// WithClock подменяет часы для TTL, нужен для тестов
+func WithClock(now func() time.Time) CacheOption {
+	return func(c *LoanCache) {
+		c.now = now
+	}
+}
+
+// WithPersistence пишет операции кеша в журнал на диске. Восстановление — через Restore.
+func WithPersistence(p *Persistence) CacheOption {
+	return func(c *LoanCache) {
+		c.persist = p
+	}
 }
 
-func NewLoanCache() *LoanCache {
-	return &LoanCache{
-		items: make([]*entities.LoanResult, 0),
+func NewLoanCache(opts ...CacheOption) *LoanCache {
+	c := &LoanCache{
+		items:  make([]*cacheEntry, 0),
+		order:  list.New(),
+		keys:   make(map[string]*cacheEntry),
+		policy: EvictLRU,
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
+}
+
+// Add сохраняет копию результата расчета в кеше и возвращает присвоенный ей id.
+// Id растут монотонно и не переиспользуются даже после удаления и Clear.
+// Если кеш переполнен, вытесняются расчеты согласно политике, но не только что добавленный.
+func (c *LoanCache) Add(entity *entities.LoanResult) int64 {
+	id, _ := c.Save("", entity)
+	return id
 }
 
-// Add добавляет новый результат расчета в кеш
-func (c *LoanCache) Add(entity *entities.LoanResult) {
+// Save добавляет расчет с ключом мемоизации. Если расчет с таким ключом уже есть
+// (например, его успел сохранить параллельный запрос), новый не добавляется и возвращается id существующего.
+// Ошибки записи в журнал не прерывают сохранение в памяти, поэтому ошибка всегда nil.
+func (c *LoanCache) Save(key string, entity *entities.LoanResult) (int64, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.items = append(c.items, entity)
+	now := c.now()
+	c.expire(now)
+	if e, ok := c.keys[key]; key != "" && ok {
+		return e.result.Id, nil
+	}
+
+	id := c.nextID
+	c.nextID++
+	stored := proto.Clone(entity).(*entities.LoanResult)
+	stored.Id = id
+
+	c.insert(stored, key, now)
+	c.log(&entities.CacheRecord{Op: &entities.CacheRecord_Add{Add: &entities.CacheEntry{Result: stored, Key: key}}})
+	c.evict()
+	return id, nil
+}
+
+// insert кладет расчет в конец кеша, вызывать под блокировкой
+func (c *LoanCache) insert(stored *entities.LoanResult, key string, added time.Time) {
+	e := &cacheEntry{result: stored, key: key, size: int64(proto.Size(stored)), added: added}
+	e.elem = c.order.PushBack(e)
+	if key != "" {
+		c.keys[key] = e
+	}
+	c.items = append(c.items, e)
+	c.bytes += e.size
+}
+
+// evict вытесняет расчеты сверх лимитов, последний добавленный остается всегда
+func (c *LoanCache) evict() {
+	for c.order.Len() > 1 {
+		switch {
+		case c.maxEntries > 0 && len(c.items) > c.maxEntries:
+			c.stats.EvictedByEntries++
+		case c.maxBytes > 0 && c.bytes > c.maxBytes:
+			c.stats.EvictedByBytes++
+		default:
+			return
+		}
+		victim := c.order.Front().Value.(*cacheEntry)
+		i, _ := c.index(victim.result.Id)
+		c.remove(i)
+	}
+}
+
+// Get возвращает копию расчета по id. Для LRU запрос отодвигает расчет в конец очереди вытеснения.
+func (c *LoanCache) Get(id int64) (*entities.LoanResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expire(c.now())
+	i, err := c.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	e := c.items[i]
+	if c.policy == EvictLRU {
+		c.order.MoveToBack(e.elem)
+	}
+	return proto.Clone(e.result).(*entities.LoanResult), nil
+}
+
+// FindByKey ищет расчет по ключу мемоизации и считает попадания и промахи
+func (c *LoanCache) FindByKey(key string) (*entities.LoanResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expire(c.now())
+	e, ok := c.keys[key]
+	if !ok {
+		c.stats.MemoMisses++
+		return nil, ErrNotFound
+	}
+	c.stats.MemoHits++
+	if c.policy == EvictLRU {
+		c.order.MoveToBack(e.elem)
+	}
+	return proto.Clone(e.result).(*entities.LoanResult), nil
+}
+
+// Delete удаляет расчет по id
+func (c *LoanCache) Delete(id int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expire(c.now())
+	i, err := c.lookup(id)
+	if err != nil {
+		return err
+	}
+	c.remove(i)
+	c.markDeleted(id)
+	c.log(&entities.CacheRecord{Op: &entities.CacheRecord_Delete{Delete: id}})
+	return nil
 }
 
 // GetAll возвращает все результаты в виде CacheResult
 func (c *LoanCache) GetAll() *entities.CacheResult {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
+	c.expire(c.now())
 	// Создаем глубокую копию для безопасности
 	results := make([]*entities.LoanResult, len(c.items))
 	for i, item := range c.items {
-		results[i] = &entities.LoanResult{
-			Params: &entities.LoanParams{
-				ObjectCost:     item.Params.ObjectCost,
-				InitialPayment: item.Params.InitialPayment,
-				Months:         item.Params.Months,
-			},
-			Program: &entities.LoanProgram{
-				Salary:   item.Program.Salary,
-				Military: item.Program.Military,
-				Base:     item.Program.Base,
-			},
-			Aggregates: &entities.LoanAggregates{
-				Rate:            item.Aggregates.Rate,
-				LoanSum:         item.Aggregates.LoanSum,
-				MonthlyPayment:  item.Aggregates.MonthlyPayment,
-				Overpayment:     item.Aggregates.Overpayment,
-				LastPaymentDate: timestamppb.New(item.Aggregates.LastPaymentDate.AsTime()),
-			},
-		}
+		results[i] = proto.Clone(item.result).(*entities.LoanResult)
 	}
 
 	return &entities.CacheResult{
@@ -61,30 +259,50 @@ func (c *LoanCache) GetAll() *entities.CacheResult {
 }
 
 // Clear очищает кеш
-func (c *LoanCache) Clear() {
+func (c *LoanCache) Clear() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.items = make([]*entities.LoanResult, 0)
+	c.items = make([]*cacheEntry, 0)
+	c.order.Init()
+	c.keys = make(map[string]*cacheEntry)
+	c.bytes = 0
+	c.deleted = nil
+	c.clearedBelow = c.nextID
+	c.log(&entities.CacheRecord{Op: &entities.CacheRecord_Clear{Clear: c.clearedBelow}})
+	return nil
 }
 
 // Size возвращает текущее количество элементов в кеше
 func (c *LoanCache) Size() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire(c.now())
 	return len(c.items)
 }
 
-// GetByProgram возвращает результаты только для указанной программы
+// Stats возвращает счетчики кеша
+func (c *LoanCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire(c.now())
+	stats := c.stats
+	stats.Entries = len(c.items)
+	stats.Bytes = c.bytes
+	return stats
+}
+
+// GetByProgram возвращает результаты только для указанной программы.
+// Программа сравнивается по коду, старые bool-поля отображаются на коды.
 func (c *LoanCache) GetByProgram(program *entities.LoanProgram) *entities.CacheResult {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
+	c.expire(c.now())
+	code := db.ProgramCode(program)
 	var filtered []*entities.LoanResult
 	for _, item := range c.items {
-		if item.Program.Salary == program.Salary &&
-			item.Program.Military == program.Military &&
-			item.Program.Base == program.Base {
-			filtered = append(filtered, item)
+		if code != "" && db.ProgramCode(item.result.Program) == code {
+			filtered = append(filtered, item.result)
 		}
 	}
 
@@ -92,3 +310,67 @@ func (c *LoanCache) GetByProgram(program *entities.LoanProgram) *entities.CacheR
 		Results: filtered,
 	}
 }
+
+// expire вытесняет расчеты с истекшим TTL. Id растут вместе со временем добавления,
+// поэтому истекшие расчеты всегда в начале items.
+func (c *LoanCache) expire(now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	for len(c.items) > 0 && !now.Before(c.items[0].added.Add(c.ttl)) {
+		c.remove(0)
+		c.stats.EvictedByTTL++
+	}
+}
+
+// lookup ищет расчет и отличает вытесненные id от никогда не существовавших и удаленных
+func (c *LoanCache) lookup(id int64) (int, error) {
+	i, ok := c.index(id)
+	if ok {
+		return i, nil
+	}
+	if id >= c.nextID || id < c.clearedBelow || c.isDeleted(id) {
+		c.stats.NotFoundLookups++
+		return 0, ErrNotFound
+	}
+	c.stats.ExpiredLookups++
+	return 0, ErrExpired
+}
+
+// maxTombstones ограничивает число отметок об удалении. Самые старые отметки забываются,
+// и такие id дальше отвечают как вытесненные, зато память кеша не растет от удалений.
+const maxTombstones = 10000
+
+// markDeleted запоминает явное удаление id, вызывать под блокировкой
+func (c *LoanCache) markDeleted(id int64) {
+	i := sort.Search(len(c.deleted), func(i int) bool { return c.deleted[i] >= id })
+	if i < len(c.deleted) && c.deleted[i] == id {
+		return
+	}
+	c.deleted = slices.Insert(c.deleted, i, id)
+	if extra := len(c.deleted) - maxTombstones; extra > 0 {
+		c.deleted = slices.Delete(c.deleted, 0, extra)
+	}
+}
+
+// isDeleted сообщает, удален ли id явно, вызывать под блокировкой
+func (c *LoanCache) isDeleted(id int64) bool {
+	_, ok := slices.BinarySearch(c.deleted, id)
+	return ok
+}
+
+func (c *LoanCache) remove(i int) {
+	e := c.items[i]
+	c.order.Remove(e.elem)
+	if e.key != "" {
+		delete(c.keys, e.key)
+	}
+	c.bytes -= e.size
+	c.items = append(c.items[:i], c.items[i+1:]...)
+}
+
+// index ищет позицию расчета бинарным поиском, вызывать под блокировкой
+func (c *LoanCache) index(id int64) (int, bool) {
+	i := sort.Search(len(c.items), func(i int) bool { return c.items[i].result.Id >= id })
+	return i, i < len(c.items) && c.items[i].result.Id == id
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/Dorji/sberInterview/api/protos/entities"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -172,4 +173,165 @@ func TestDeepCopy(t *testing.T) {
 		result.Aggregates.Rate == 0 {
 		t.Error("GetAll() returned a reference, expected deep copy")
 	}
-}
\ No newline at end of file
+}
+func TestGetByProgramCode(t *testing.T) {
+	cache := NewLoanCache()
+	cache.Add(&entities.LoanResult{Program: &entities.LoanProgram{Salary: true}})
+	cache.Add(&entities.LoanResult{Program: &entities.LoanProgram{Code: "salary"}})
+	cache.Add(&entities.LoanResult{Program: &entities.LoanProgram{Code: "family"}})
+
+	assert.Len(t, cache.GetByProgram(&entities.LoanProgram{Code: "salary"}).Results, 2)
+	assert.Len(t, cache.GetByProgram(&entities.LoanProgram{Salary: true}).Results, 2)
+	assert.Len(t, cache.GetByProgram(&entities.LoanProgram{Code: "family"}).Results, 1)
+}
+
+func TestLoanCacheIDs(t *testing.T) {
+	cache := NewLoanCache()
+	for i := 0; i < 3; i++ {
+		id := cache.Add(&entities.LoanResult{Params: &entities.LoanParams{Months: int64(i + 1)}})
+		assert.Equal(t, int64(i), id)
+	}
+
+	got, err := cache.Get(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), got.Id)
+	assert.Equal(t, int64(2), got.Params.Months)
+
+	// Get отдает копию
+	got.Params.Months = 100
+	again, _ := cache.Get(1)
+	assert.Equal(t, int64(2), again.Params.Months)
+
+	assert.NoError(t, cache.Delete(1))
+	assert.ErrorIs(t, cache.Delete(1), ErrNotFound)
+	_, err = cache.Get(1)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = cache.Get(42)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// После удаления и очистки id не переиспользуются
+	cache.Clear()
+	assert.Equal(t, int64(3), cache.Add(&entities.LoanResult{}))
+
+	ids := []int64{}
+	for _, r := range cache.GetAll().Results {
+		ids = append(ids, r.Id)
+	}
+	assert.Equal(t, []int64{3}, ids)
+
+	// Отметок об удалении не больше maxTombstones, самые старые удаления отвечают как вытесненные
+	cache = NewLoanCache()
+	for i := 0; i <= maxTombstones; i++ {
+		id := cache.Add(&entities.LoanResult{})
+		assert.NoError(t, cache.Delete(id))
+	}
+	assert.Len(t, cache.deleted, maxTombstones)
+	_, err = cache.Get(0)
+	assert.ErrorIs(t, err, ErrExpired)
+	_, err = cache.Get(maxTombstones)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestBoundedLoanCache(t *testing.T) {
+	add := func(cache *LoanCache, n int) {
+		for i := 0; i < n; i++ {
+			cache.Add(&entities.LoanResult{Params: &entities.LoanParams{Months: 12}})
+		}
+	}
+	ids := func(cache *LoanCache) []int64 {
+		res := []int64{}
+		for _, r := range cache.GetAll().Results {
+			res = append(res, r.Id)
+		}
+		return res
+	}
+
+	t.Run("FIFO evicts the oldest", func(t *testing.T) {
+		cache := NewLoanCache(WithMaxEntries(3), WithEvictionPolicy(EvictFIFO))
+		add(cache, 3)
+		_, err := cache.Get(0)
+		assert.NoError(t, err)
+		add(cache, 1)
+
+		assert.Equal(t, []int64{1, 2, 3}, ids(cache))
+		_, err = cache.Get(0)
+		assert.ErrorIs(t, err, ErrExpired)
+		assert.Equal(t, int64(1), cache.Stats().EvictedByEntries)
+	})
+
+	t.Run("LRU keeps recently requested", func(t *testing.T) {
+		cache := NewLoanCache(WithMaxEntries(3))
+		add(cache, 3)
+		_, err := cache.Get(0)
+		assert.NoError(t, err)
+		add(cache, 1)
+
+		assert.Equal(t, []int64{0, 2, 3}, ids(cache))
+		_, err = cache.Get(1)
+		assert.ErrorIs(t, err, ErrExpired)
+		assert.ErrorIs(t, cache.Delete(1), ErrExpired)
+	})
+
+	t.Run("Byte limit", func(t *testing.T) {
+		size := int64(proto.Size(&entities.LoanResult{Id: 1, Params: &entities.LoanParams{Months: 12}}))
+		cache := NewLoanCache(WithMaxBytes(2 * size))
+		add(cache, 4)
+
+		stats := cache.Stats()
+		assert.Equal(t, 2, stats.Entries)
+		assert.LessOrEqual(t, stats.Bytes, 2*size)
+		assert.Equal(t, int64(2), stats.EvictedByBytes)
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		now := time.Date(2024, time.February, 18, 12, 0, 0, 0, time.UTC)
+		cache := NewLoanCache(WithTTL(time.Hour), WithClock(func() time.Time { return now }))
+		add(cache, 1)
+		now = now.Add(30 * time.Minute)
+		add(cache, 1)
+		now = now.Add(30 * time.Minute)
+
+		assert.Equal(t, []int64{1}, ids(cache))
+		_, err := cache.Get(0)
+		assert.ErrorIs(t, err, ErrExpired)
+
+		stats := cache.Stats()
+		assert.Equal(t, int64(1), stats.EvictedByTTL)
+		assert.Equal(t, int64(1), stats.ExpiredLookups)
+	})
+
+	t.Run("Cleared ids are not expired", func(t *testing.T) {
+		cache := NewLoanCache(WithMaxEntries(1))
+		add(cache, 2)
+		cache.Clear()
+		_, err := cache.Get(0)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestLoanCacheMemoization(t *testing.T) {
+	cache := NewLoanCache(WithMaxEntries(2))
+
+	_, err := cache.FindByKey("a")
+	assert.ErrorIs(t, err, ErrNotFound)
+	first, err := cache.Save("a", &entities.LoanResult{Params: &entities.LoanParams{Months: 12}})
+	assert.NoError(t, err)
+	again, _ := cache.Save("a", &entities.LoanResult{})
+	assert.Equal(t, first, again, "same key must not be stored twice")
+	assert.Equal(t, 1, cache.Size())
+
+	got, err := cache.FindByKey("a")
+	assert.NoError(t, err)
+	assert.Equal(t, first, got.Id)
+	assert.Equal(t, int64(12), got.Params.Months)
+
+	// Вытесненный расчет пропадает и из индекса
+	cache.Save("b", &entities.LoanResult{})
+	cache.Save("c", &entities.LoanResult{})
+	_, err = cache.FindByKey("a")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	stats := cache.Stats()
+	assert.Equal(t, int64(1), stats.MemoHits)
+	assert.Equal(t, int64(2), stats.MemoMisses)
+}
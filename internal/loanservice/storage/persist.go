@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	walFile      = "cache.wal"
+	snapshotFile = "cache.snapshot"
+
+	// maxFrameSize защищает от выделения гигантского буфера по битой длине
+	maxFrameSize = 64 << 20
+)
+
+// ErrCorrupted — файл на диске поврежден, восстановлено только то, что прошло проверку
+var ErrCorrupted = errors.New("cache persistence corrupted")
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Persistence хранит кеш на диске: журнал операций и периодический снимок.
+// Каждая запись — длина, CRC32C и protobuf-сообщение.
+type Persistence struct {
+	mu  sync.Mutex
+	dir string
+	wal *os.File
+}
+
+// OpenPersistence открывает (или создает) каталог с журналом и снимком
+func OpenPersistence(dir string) (*Persistence, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("OpenPersistence:%w", err)
+	}
+	wal, err := os.OpenFile(filepath.Join(dir, walFile), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("OpenPersistence:%w", err)
+	}
+	return &Persistence{dir: dir, wal: wal}, nil
+}
+
+// Close сбрасывает журнал на диск и закрывает его
+func (p *Persistence) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.wal.Sync(); err != nil {
+		p.wal.Close()
+		return err
+	}
+	return p.wal.Close()
+}
+
+// append дописывает операцию в журнал
+func (p *Persistence) append(rec *entities.CacheRecord) error {
+	frame, err := encodeFrame(rec)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.wal.Write(frame)
+	return err
+}
+
+// writeSnapshot атомарно заменяет снимок и очищает журнал
+func (p *Persistence) writeSnapshot(s *entities.CacheSnapshot) error {
+	frame, err := encodeFrame(s)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	path := filepath.Join(p.dir, snapshotFile)
+	tmp, err := os.CreateTemp(p.dir, snapshotFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(frame); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Если упадем до очистки журнала, повторное применение его записей ничего не испортит
+	if err := p.wal.Truncate(0); err != nil {
+		return err
+	}
+	return p.wal.Sync()
+}
+
+// load читает снимок и журнал. При повреждении возвращает все, что удалось прочитать,
+// вместе с ошибкой ErrCorrupted; битый хвост журнала обрезается, чтобы дописывать после целых записей.
+func (p *Persistence) load() (*entities.CacheSnapshot, []*entities.CacheRecord, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs []error
+	snapshot := &entities.CacheSnapshot{}
+	data, err := os.ReadFile(filepath.Join(p.dir, snapshotFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, nil, err
+	default:
+		if _, err := decodeFrame(bytes.NewReader(data), snapshot); err != nil {
+			snapshot = &entities.CacheSnapshot{}
+			errs = append(errs, fmt.Errorf("%w: snapshot: %v", ErrCorrupted, err))
+		}
+	}
+
+	if _, err := p.wal.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+	var (
+		records []*entities.CacheRecord
+		good    int64
+	)
+	r := bufio.NewReader(p.wal)
+	for {
+		rec := &entities.CacheRecord{}
+		n, err := decodeFrame(r, rec)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: log record at offset %d: %v", ErrCorrupted, good, err))
+			if err := p.wal.Truncate(good); err != nil {
+				return nil, nil, err
+			}
+			break
+		}
+		records = append(records, rec)
+		good += n
+	}
+	return snapshot, records, errors.Join(errs...)
+}
+
+func encodeFrame(m proto.Message) ([]byte, error) {
+	payload, err := proto.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	frame := make([]byte, 8, 8+len(payload))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.Checksum(payload, crcTable))
+	return append(frame, payload...), nil
+}
+
+// decodeFrame читает одну запись и возвращает ее размер на диске. io.EOF — только на границе записей.
+func decodeFrame(r io.Reader, m proto.Message) (int64, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, fmt.Errorf("truncated header")
+		}
+		return 0, err
+	}
+	size := binary.BigEndian.Uint32(header[0:4])
+	if size > maxFrameSize {
+		return 0, fmt.Errorf("record size %d is too large", size)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, fmt.Errorf("truncated record")
+	}
+	if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(header[4:8]) {
+		return 0, fmt.Errorf("checksum mismatch")
+	}
+	if err := proto.Unmarshal(payload, m); err != nil {
+		return 0, err
+	}
+	return int64(len(header)) + int64(size), nil
+}
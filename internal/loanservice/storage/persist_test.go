@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestLoanCachePersistence(t *testing.T) {
+	now := time.Date(2024, time.February, 18, 12, 0, 0, 0, time.UTC)
+	result := func(months int64) *entities.LoanResult {
+		return &entities.LoanResult{
+			Params:    &entities.LoanParams{Months: months},
+			CreatedAt: timestamppb.New(now),
+		}
+	}
+	open := func(t *testing.T, dir string, opts ...CacheOption) (*LoanCache, *Persistence, error) {
+		p, err := OpenPersistence(dir)
+		noError(t, err)
+		t.Cleanup(func() { p.Close() })
+		cache := NewLoanCache(append(opts, WithPersistence(p), WithClock(func() time.Time { return now }))...)
+		return cache, p, cache.Restore()
+	}
+	months := func(cache *LoanCache) []int64 {
+		res := []int64{}
+		for _, r := range cache.GetAll().Results {
+			res = append(res, r.Params.Months)
+		}
+		return res
+	}
+
+	t.Run("Log is replayed on restart", func(t *testing.T) {
+		dir := t.TempDir()
+		cache, p, err := open(t, dir)
+		noError(t, err)
+		cache.Save("a", result(12))
+		cache.Add(result(24))
+		cache.Add(result(36))
+		noError(t, cache.Delete(1))
+		noError(t, p.Close())
+
+		restored, _, err := open(t, dir)
+		noError(t, err)
+		assert.Equal(t, []int64{12, 36}, months(restored))
+		_, err = restored.Get(1)
+		assert.ErrorIs(t, err, ErrNotFound)
+		_, err = restored.FindByKey("a")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), restored.Add(result(48)), "ids continue after restart")
+	})
+
+	t.Run("Snapshot compacts the log", func(t *testing.T) {
+		dir := t.TempDir()
+		cache, p, err := open(t, dir)
+		noError(t, err)
+		cache.Add(result(12))
+		cache.Add(result(24))
+		noError(t, cache.Snapshot())
+
+		info, err := os.Stat(filepath.Join(dir, walFile))
+		noError(t, err)
+		assert.Zero(t, info.Size())
+
+		cache.Add(result(36))
+		cache.Clear()
+		cache.Add(result(48))
+		noError(t, p.Close())
+
+		restored, _, err := open(t, dir)
+		noError(t, err)
+		assert.Equal(t, []int64{48}, months(restored))
+		_, err = restored.Get(0)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Limits apply to restored cache", func(t *testing.T) {
+		dir := t.TempDir()
+		cache, p, err := open(t, dir)
+		noError(t, err)
+		for i := int64(1); i <= 3; i++ {
+			cache.Add(result(i * 12))
+		}
+		noError(t, p.Close())
+
+		restored, _, err := open(t, dir, WithMaxEntries(2))
+		noError(t, err)
+		assert.Equal(t, []int64{24, 36}, months(restored))
+		_, err = restored.Get(0)
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("Torn log tail is reported and cut", func(t *testing.T) {
+		dir := t.TempDir()
+		cache, p, err := open(t, dir)
+		noError(t, err)
+		cache.Add(result(12))
+		cache.Add(result(24))
+		noError(t, p.Close())
+
+		wal := filepath.Join(dir, walFile)
+		data, err := os.ReadFile(wal)
+		noError(t, err)
+		noError(t, os.WriteFile(wal, data[:len(data)-3], 0o644))
+
+		restored, p, err := open(t, dir)
+		assert.ErrorIs(t, err, ErrCorrupted)
+		assert.Equal(t, []int64{12}, months(restored))
+
+		// После обрезки журнал снова читается без ошибок
+		restored.Add(result(36))
+		noError(t, p.Close())
+		again, _, err := open(t, dir)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{12, 36}, months(again))
+	})
+
+	t.Run("Checksum mismatch in snapshot", func(t *testing.T) {
+		dir := t.TempDir()
+		cache, p, err := open(t, dir)
+		noError(t, err)
+		cache.Add(result(12))
+		noError(t, cache.Snapshot())
+		cache.Add(result(24))
+		noError(t, p.Close())
+
+		path := filepath.Join(dir, snapshotFile)
+		data, err := os.ReadFile(path)
+		noError(t, err)
+		data[len(data)-1] ^= 0xff
+		noError(t, os.WriteFile(path, data, 0o644))
+
+		restored, _, err := open(t, dir)
+		assert.ErrorIs(t, err, ErrCorrupted)
+		assert.Equal(t, []int64{24}, months(restored))
+	})
+}
+
+func noError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
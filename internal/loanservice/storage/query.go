@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+	"github.com/Dorji/sberInterview/internal/money"
+	"google.golang.org/protobuf/proto"
+)
+
+// SortField — поле сортировки списка расчетов
+type SortField int
+
+const (
+	SortByCreatedAt SortField = iota
+	SortByMonthlyPayment
+)
+
+// Cursor — позиция в отсортированном списке: ключ сортировки и id последнего отданного расчета
+type Cursor struct {
+	Key int64
+	ID  int64
+}
+
+// Query описывает фильтры, сортировку и страницу. Нулевые значения фильтров не ограничивают выборку.
+type Query struct {
+	ProgramCode string
+	MinLoanSum  int64
+	MaxLoanSum  int64
+	MinMonths   int64
+	MaxMonths   int64
+	CreatedFrom time.Time // включительно
+	CreatedTo   time.Time // не включительно
+	Sort        SortField
+	Desc        bool
+	Limit       int
+	After       *Cursor
+}
+
+// listItem — поля расчета, по которым фильтруется и сортируется список.
+// Хранилища, которые не держат расчеты в памяти, строят список только по ним.
+type listItem struct {
+	id          int64
+	programCode string
+	loanSum     int64
+	months      int64
+	createdAt   time.Time
+	payment     money.Money // ежемесячный платеж в копейках
+}
+
+func newListItem(r *entities.LoanResult) listItem {
+	payment := money.FromRubles(r.GetAggregates().GetMonthlyPayment())
+	if exact := r.GetAggregates().GetExact(); exact != nil {
+		payment = money.FromProto(exact.MonthlyPayment)
+	}
+	return listItem{
+		id:          r.Id,
+		programCode: db.ProgramCode(r.Program),
+		loanSum:     r.GetAggregates().GetLoanSum(),
+		months:      r.GetParams().GetMonths(),
+		createdAt:   r.GetCreatedAt().AsTime(),
+		payment:     payment,
+	}
+}
+
+// List возвращает копии расчетов одной страницы и курсор следующей (nil, если страниц больше нет).
+// Пагинация по курсору, а не по смещению, поэтому добавление и удаление расчетов не сдвигают страницы.
+func (c *LoanCache) List(q Query) ([]*entities.LoanResult, *Cursor, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expire(c.now())
+	items := make([]listItem, 0, len(c.items))
+	for _, e := range c.items {
+		items = append(items, newListItem(e.result))
+	}
+
+	ids, next := q.page(items)
+	page := make([]*entities.LoanResult, 0, len(ids))
+	for _, id := range ids {
+		i, _ := c.index(id)
+		page = append(page, proto.Clone(c.items[i].result).(*entities.LoanResult))
+	}
+	return page, next, nil
+}
+
+// page фильтрует и сортирует расчеты и возвращает id одной страницы
+func (q Query) page(items []listItem) ([]int64, *Cursor) {
+	type entry struct {
+		key int64
+		id  int64
+	}
+	var matched []entry
+	for _, item := range items {
+		if q.match(item) {
+			matched = append(matched, entry{key: q.sortKey(item), id: item.id})
+		}
+	}
+
+	less := func(a, b entry) bool {
+		if a.key != b.key {
+			return a.key < b.key
+		}
+		return a.id < b.id
+	}
+	if q.Desc {
+		asc := less
+		less = func(a, b entry) bool { return asc(b, a) }
+	}
+	sort.Slice(matched, func(i, j int) bool { return less(matched[i], matched[j]) })
+
+	start := 0
+	if q.After != nil {
+		after := entry{key: q.After.Key, id: q.After.ID}
+		start = sort.Search(len(matched), func(i int) bool { return less(after, matched[i]) })
+	}
+	end := len(matched)
+	if q.Limit > 0 && start+q.Limit < end {
+		end = start + q.Limit
+	}
+
+	ids := make([]int64, 0, end-start)
+	for _, e := range matched[start:end] {
+		ids = append(ids, e.id)
+	}
+	if end == len(matched) {
+		return ids, nil
+	}
+	last := matched[end-1]
+	return ids, &Cursor{Key: last.key, ID: last.id}
+}
+
+func (q Query) match(item listItem) bool {
+	if q.ProgramCode != "" && item.programCode != q.ProgramCode {
+		return false
+	}
+	if q.MinLoanSum > 0 && item.loanSum < q.MinLoanSum {
+		return false
+	}
+	if q.MaxLoanSum > 0 && item.loanSum > q.MaxLoanSum {
+		return false
+	}
+	if q.MinMonths > 0 && item.months < q.MinMonths {
+		return false
+	}
+	if q.MaxMonths > 0 && item.months > q.MaxMonths {
+		return false
+	}
+	if !q.CreatedFrom.IsZero() && item.createdAt.Before(q.CreatedFrom) {
+		return false
+	}
+	if !q.CreatedTo.IsZero() && !item.createdAt.Before(q.CreatedTo) {
+		return false
+	}
+	return true
+}
+
+// sortKey возвращает ключ сортировки; платеж берется в копейках, если есть точные суммы
+func (q Query) sortKey(item listItem) int64 {
+	switch q.Sort {
+	case SortByMonthlyPayment:
+		return item.payment.Kopecks()
+	default:
+		return item.createdAt.UnixNano()
+	}
+}
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestLoanCacheList(t *testing.T) {
+	start := time.Date(2024, time.February, 18, 12, 0, 0, 0, time.UTC)
+	cache := NewLoanCache()
+	add := func(program *entities.LoanProgram, loanSum, months, payment int64, created time.Time) {
+		cache.Add(&entities.LoanResult{
+			Params:     &entities.LoanParams{Months: months},
+			Program:    program,
+			Aggregates: &entities.LoanAggregates{LoanSum: loanSum, MonthlyPayment: payment},
+			CreatedAt:  timestamppb.New(created),
+		})
+	}
+	salary := &entities.LoanProgram{Salary: true}
+	base := &entities.LoanProgram{Base: true}
+	add(salary, 1_000_000, 12, 90_000, start)                   // id 0
+	add(base, 4_000_000, 240, 38_000, start.Add(time.Hour))     // id 1
+	add(salary, 2_000_000, 120, 25_000, start.Add(2*time.Hour)) // id 2
+	add(salary, 3_000_000, 240, 25_000, start.Add(3*time.Hour)) // id 3
+
+	ids := func(results []*entities.LoanResult) []int64 {
+		res := []int64{}
+		for _, r := range results {
+			res = append(res, r.Id)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []int64
+	}{
+		{"No filters", Query{}, []int64{0, 1, 2, 3}},
+		{"Program", Query{ProgramCode: "salary"}, []int64{0, 2, 3}},
+		{"Loan sum range", Query{MinLoanSum: 2_000_000, MaxLoanSum: 3_000_000}, []int64{2, 3}},
+		{"Months range", Query{MinMonths: 100}, []int64{1, 2, 3}},
+		{"Created window", Query{CreatedFrom: start.Add(time.Hour), CreatedTo: start.Add(3 * time.Hour)}, []int64{1, 2}},
+		{"Created desc", Query{Desc: true}, []int64{3, 2, 1, 0}},
+		{"Monthly payment ties by id", Query{Sort: SortByMonthlyPayment}, []int64{2, 3, 1, 0}},
+		{"Monthly payment desc", Query{Sort: SortByMonthlyPayment, Desc: true}, []int64{0, 1, 3, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, next, err := cache.List(tt.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, ids(got))
+			assert.Nil(t, next)
+		})
+	}
+
+	t.Run("Pages follow the cursor", func(t *testing.T) {
+		q := Query{Sort: SortByMonthlyPayment, Limit: 3}
+		page, next, _ := cache.List(q)
+		assert.Equal(t, []int64{2, 3, 1}, ids(page))
+		if !assert.NotNil(t, next) {
+			return
+		}
+
+		// Новый расчет с меньшим платежом не сдвигает вторую страницу
+		add(base, 500_000, 12, 10_000, start.Add(4*time.Hour))
+		q.After = next
+		page, next, _ = cache.List(q)
+		assert.Equal(t, []int64{0}, ids(page))
+		assert.Nil(t, next)
+	})
+}
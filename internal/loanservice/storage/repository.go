@@ -0,0 +1,25 @@
+package storage
+
+import "github.com/Dorji/sberInterview/api/protos/entities"
+
+// CalculationRepository — хранилище расчетов, с которым работает сервис.
+// Реализации: LoanCache в памяти и FileStore на диске.
+type CalculationRepository interface {
+	// Save сохраняет копию расчета и возвращает присвоенный id. С непустым key
+	// расчет с тем же ключом не дублируется, возвращается id уже сохраненного.
+	Save(key string, result *entities.LoanResult) (int64, error)
+	// FindByKey ищет расчет по ключу мемоизации, ErrNotFound — если его нет
+	FindByKey(key string) (*entities.LoanResult, error)
+	// Get возвращает расчет по id, ErrNotFound или ErrExpired — если его нет
+	Get(id int64) (*entities.LoanResult, error)
+	// List возвращает страницу расчетов и курсор следующей
+	List(q Query) ([]*entities.LoanResult, *Cursor, error)
+	Delete(id int64) error
+	Clear() error
+	Size() int
+}
+
+var (
+	_ CalculationRepository = (*LoanCache)(nil)
+	_ CalculationRepository = (*FileStore)(nil)
+)
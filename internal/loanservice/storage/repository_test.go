@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Общие проверки для всех реализаций CalculationRepository
+func TestCalculationRepository(t *testing.T) {
+	backends := []struct {
+		name string
+		open func(t *testing.T) CalculationRepository
+	}{
+		{"memory", func(t *testing.T) CalculationRepository { return NewLoanCache() }},
+		{"file", func(t *testing.T) CalculationRepository {
+			s, err := OpenFileStore(filepath.Join(t.TempDir(), "calculations.db"))
+			noError(t, err)
+			t.Cleanup(func() { s.Close() })
+			return s
+		}},
+	}
+
+	created := time.Date(2024, time.February, 18, 12, 0, 0, 0, time.UTC)
+	result := func(months, payment int64) *entities.LoanResult {
+		return &entities.LoanResult{
+			Params:     &entities.LoanParams{Months: months},
+			Program:    &entities.LoanProgram{Salary: true},
+			Aggregates: &entities.LoanAggregates{MonthlyPayment: payment},
+			CreatedAt:  timestamppb.New(created),
+		}
+	}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			repo := backend.open(t)
+
+			id, err := repo.Save("a", result(12, 90_000))
+			noError(t, err)
+			assert.Equal(t, int64(0), id)
+			again, err := repo.Save("a", result(12, 90_000))
+			noError(t, err)
+			assert.Equal(t, id, again)
+			_, err = repo.Save("", result(240, 33_000))
+			noError(t, err)
+			_, err = repo.Save("", result(120, 45_000))
+			noError(t, err)
+			assert.Equal(t, 3, repo.Size())
+
+			got, err := repo.Get(1)
+			noError(t, err)
+			assert.Equal(t, int64(240), got.Params.Months)
+			got, err = repo.FindByKey("a")
+			noError(t, err)
+			assert.Equal(t, int64(0), got.Id)
+			_, err = repo.FindByKey("b")
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			page, next, err := repo.List(Query{Sort: SortByMonthlyPayment, Limit: 2})
+			noError(t, err)
+			if assert.Len(t, page, 2) {
+				assert.Equal(t, int64(1), page[0].Id)
+				assert.Equal(t, int64(2), page[1].Id)
+			}
+			page, next, err = repo.List(Query{Sort: SortByMonthlyPayment, Limit: 2, After: next})
+			noError(t, err)
+			assert.Len(t, page, 1)
+			assert.Nil(t, next)
+
+			noError(t, repo.Delete(1))
+			assert.ErrorIs(t, repo.Delete(1), ErrNotFound)
+			_, err = repo.Get(1)
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			noError(t, repo.Clear())
+			assert.Equal(t, 0, repo.Size())
+			_, err = repo.FindByKey("a")
+			assert.ErrorIs(t, err, ErrNotFound)
+			id, err = repo.Save("a", result(12, 90_000))
+			noError(t, err)
+			assert.Equal(t, int64(3), id, "ids are not reused after Clear")
+		})
+	}
+}
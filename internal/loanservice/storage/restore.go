@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+)
+
+// log пишет операцию в журнал, вызывать под блокировкой кеша, чтобы порядок записей совпадал с порядком операций.
+// Ошибка записи не должна ронять расчет, поэтому она только считается и логируется.
+func (c *LoanCache) log(rec *entities.CacheRecord) {
+	if c.persist == nil {
+		return
+	}
+	if err := c.persist.append(rec); err != nil {
+		c.stats.PersistErrors++
+		log.Printf("cache persistence: %v", err)
+	}
+}
+
+// Restore загружает снимок и журнал в кеш. При повреждении файлов восстанавливает
+// все целые записи и возвращает ошибку, оборачивающую ErrCorrupted.
+// Вызывать до начала обслуживания запросов.
+func (c *LoanCache) Restore() error {
+	if c.persist == nil {
+		return nil
+	}
+	snapshot, records, loadErr := c.persist.load()
+	if snapshot == nil {
+		return fmt.Errorf("Restore:%w", loadErr)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := snapshot.Entries
+	sort.Slice(entries, func(i, j int) bool { return entries[i].GetResult().GetId() < entries[j].GetResult().GetId() })
+	for _, e := range entries {
+		c.restoreAdd(e)
+	}
+	c.nextID = max(c.nextID, snapshot.NextId)
+	for _, id := range snapshot.Deleted {
+		c.markDeleted(id)
+	}
+	c.restoreClear(snapshot.ClearedBelow)
+
+	// Журнал может повторять записи из снимка, если упали между снимком и очисткой журнала,
+	// поэтому применение записей идемпотентно
+	for _, rec := range records {
+		switch op := rec.Op.(type) {
+		case *entities.CacheRecord_Add:
+			c.restoreAdd(op.Add)
+		case *entities.CacheRecord_Delete:
+			if i, ok := c.index(op.Delete); ok {
+				c.remove(i)
+			}
+			c.markDeleted(op.Delete)
+		case *entities.CacheRecord_Clear:
+			c.restoreClear(op.Clear)
+		}
+	}
+
+	c.expire(c.now())
+	if loadErr != nil {
+		return fmt.Errorf("Restore:%w", loadErr)
+	}
+	return nil
+}
+
+// restoreAdd возвращает расчет в кеш со временем его создания, чтобы TTL не начинался заново
+func (c *LoanCache) restoreAdd(e *entities.CacheEntry) {
+	if e.GetResult() == nil || e.Result.Id < c.nextID {
+		return
+	}
+	added := c.now()
+	if e.Result.CreatedAt != nil {
+		added = e.Result.CreatedAt.AsTime()
+	}
+	c.insert(e.Result, e.Key, added)
+	c.nextID = e.Result.Id + 1
+	c.evict()
+}
+
+func (c *LoanCache) restoreClear(below int64) {
+	if below <= c.clearedBelow {
+		return
+	}
+	for len(c.items) > 0 && c.items[0].result.Id < below {
+		c.remove(0)
+	}
+	c.clearedBelow = below
+	c.nextID = max(c.nextID, below)
+	i := sort.Search(len(c.deleted), func(i int) bool { return c.deleted[i] >= below })
+	c.deleted = slices.Delete(c.deleted, 0, i)
+}
+
+// Snapshot сохраняет текущее содержимое кеша снимком и начинает журнал заново
+func (c *LoanCache) Snapshot() error {
+	if c.persist == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expire(c.now())
+	snapshot := &entities.CacheSnapshot{
+		NextId:       c.nextID,
+		Entries:      make([]*entities.CacheEntry, 0, len(c.items)),
+		Deleted:      slices.Clone(c.deleted),
+		ClearedBelow: c.clearedBelow,
+	}
+	for _, e := range c.items {
+		snapshot.Entries = append(snapshot.Entries, &entities.CacheEntry{Result: e.result, Key: e.key})
+	}
+	if err := c.persist.writeSnapshot(snapshot); err != nil {
+		c.stats.PersistErrors++
+		return fmt.Errorf("Snapshot:%w", err)
+	}
+	return nil
+}
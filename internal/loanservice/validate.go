@@ -0,0 +1,131 @@
+package loanservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+	db "github.com/Dorji/sberInterview/internal/db/storage"
+	"github.com/Dorji/sberInterview/internal/money"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Машиночитаемые причины ошибок валидации полей запроса
+const (
+	ReasonObjectCostNotPositive  = "OBJECT_COST_NOT_POSITIVE"
+	ReasonObjectCostTooLarge     = "OBJECT_COST_TOO_LARGE"
+	ReasonInitialPaymentNegative = "INITIAL_PAYMENT_NEGATIVE"
+	ReasonInitialPaymentTooLarge = "INITIAL_PAYMENT_TOO_LARGE"
+	ReasonMonthsNotPositive      = "MONTHS_NOT_POSITIVE"
+	ReasonMonthsTooLarge         = "MONTHS_TOO_LARGE"
+	ReasonPaymentDayOutOfRange   = "PAYMENT_DAY_OUT_OF_RANGE"
+	ReasonIDNegative             = "ID_NEGATIVE"
+)
+
+// maxAmount — наибольшая сумма в рублях в запросе, триллион. Предел копеек в int64 в тысячи раз больше,
+// поэтому суммы платежей и процентов по графику из таких сумм не переполняются.
+const maxAmount = 1_000_000_000_000
+
+// violations копит нарушения по полям запроса
+type violations []*errdetails.BadRequest_FieldViolation
+
+func (v *violations) add(field, reason, format string, args ...interface{}) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Reason:      reason,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+// err собирает InvalidArgument с errdetails.BadRequest. Сообщением статуса служит
+// первое нарушение, чтобы одиночные ошибки остались как в спецификации: {"error": "choose program"}
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	st := status.New(codes.InvalidArgument, v[0].Description)
+	detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// validateRequest проверяет запрос целиком и возвращает выбранную программу.
+// Ограничения программы проверяются, только если сами поля корректны.
+func (ls *LoanServiceServer) validateRequest(ctx context.Context, req *entities.LoanRequest) (db.Program, error) {
+	v, paramsOK := validateParams(req)
+
+	program, ok := ls.resolveProgram(ctx, &v, req.Program)
+	if ok && paramsOK {
+		v = append(v, program.LimitViolations(req.ObjectCost, req.InitialPayment, req.Months)...)
+	}
+
+	return program, v.err()
+}
+
+// resolveProgram выбирает программу из реестра, ошибку выбора добавляет нарушением поля program
+func (ls *LoanServiceServer) resolveProgram(ctx context.Context, v *violations, req *entities.LoanProgram) (db.Program, bool) {
+	program, err := ls.snapshot(ctx).programs.Resolve(req)
+	if err != nil {
+		st := status.Convert(err)
+		reason := ""
+		for _, detail := range st.Details() {
+			if info, ok := detail.(*errdetails.ErrorInfo); ok {
+				reason = info.Reason
+				break
+			}
+		}
+		v.add("program", reason, "%s", st.Message())
+		return program, false
+	}
+	return program, true
+}
+
+// validateParams проверяет параметры кредита без программы. Второе значение
+// сообщает, корректны ли стоимость, взнос и срок, по которым проверяются ограничения программ.
+func validateParams(req *entities.LoanRequest) (violations, bool) {
+	var v violations
+
+	costOK := req.ObjectCost > 0 && req.ObjectCost <= maxAmount
+	if req.ObjectCost <= 0 {
+		v.add("object_cost", ReasonObjectCostNotPositive, "object cost should be positive")
+	} else if req.ObjectCost > maxAmount {
+		v.add("object_cost", ReasonObjectCostTooLarge, "object cost should be at most %d", int64(maxAmount))
+	}
+	initialOK := req.InitialPayment >= 0
+	if !initialOK {
+		v.add("initial_payment", ReasonInitialPaymentNegative, "initial payment should not be negative")
+	} else if costOK && req.InitialPayment >= req.ObjectCost {
+		initialOK = false
+		v.add("initial_payment", ReasonInitialPaymentTooLarge, "initial payment should be less than object cost")
+	}
+	monthsOK := req.Months > 0 && req.Months <= maxMonths
+	if req.Months <= 0 {
+		v.add("months", ReasonMonthsNotPositive, "months should be positive")
+	} else if req.Months > maxMonths {
+		v.add("months", ReasonMonthsTooLarge, "months should be at most %d", maxMonths)
+	}
+	if req.PaymentDay < 0 || req.PaymentDay > 31 {
+		v.add("payment_day", ReasonPaymentDayOutOfRange, "payment day should be between 1 and 31")
+	}
+	var loanSum money.Money
+	if costOK && initialOK {
+		loanSum = money.FromRubles(req.ObjectCost - req.InitialPayment)
+	}
+	validateCosts(&v, req.Costs, loanSum)
+	validateRatePeriods(&v, req.RatePeriods)
+
+	return v, costOK && initialOK && monthsOK
+}
+
+// validateID проверяет id расчета в кеше
+func validateID(id int64) error {
+	var v violations
+	if id < 0 {
+		v.add("id", ReasonIDNegative, "id should not be negative")
+	}
+	return v.err()
+}
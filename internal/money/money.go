@@ -0,0 +1,137 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/Dorji/sberInterview/api/protos/entities"
+)
+
+// Money — денежная сумма в копейках
+type Money int64
+
+const (
+	Kopeck Money = 1
+	Ruble  Money = 100
+)
+
+// RoundingMode — способ округления дробных копеек
+type RoundingMode int
+
+const (
+	HalfEven RoundingMode = iota // банковское округление: 0.5 к ближайшему четному
+	HalfUp                       // математическое округление: 0.5 от нуля
+	Ceil                         // всегда вверх
+	Floor                        // всегда вниз
+)
+
+var ErrOverflow = errors.New("money: amount overflows int64")
+
+var (
+	maxKopecks = big.NewInt(math.MaxInt64)
+	minKopecks = big.NewInt(math.MinInt64)
+)
+
+// FromRubles переводит целые рубли в копейки
+func FromRubles(rubles int64) Money {
+	return Money(rubles) * Ruble
+}
+
+// FromRublesChecked переводит целые рубли в копейки, если сумма в копейках помещается в int64
+func FromRublesChecked(rubles int64) (Money, error) {
+	if rubles > math.MaxInt64/int64(Ruble) || rubles < math.MinInt64/int64(Ruble) {
+		return 0, ErrOverflow
+	}
+	return Money(rubles) * Ruble, nil
+}
+
+// FromRat округляет точную сумму в копейках до целых копеек
+func FromRat(kopecks *big.Rat, mode RoundingMode) (Money, error) {
+	rounded := roundRat(kopecks, mode)
+	if rounded.Cmp(maxKopecks) > 0 || rounded.Cmp(minKopecks) < 0 {
+		return 0, ErrOverflow
+	}
+	return Money(rounded.Int64()), nil
+}
+
+// Kopecks возвращает сумму в копейках
+func (m Money) Kopecks() int64 {
+	return int64(m)
+}
+
+// Rat возвращает сумму в копейках как точную дробь
+func (m Money) Rat() *big.Rat {
+	return new(big.Rat).SetInt64(int64(m))
+}
+
+// Mul умножает сумму на дробь (например, на месячную ставку) с округлением до копейки
+func (m Money) Mul(factor *big.Rat, mode RoundingMode) (Money, error) {
+	return FromRat(new(big.Rat).Mul(m.Rat(), factor), mode)
+}
+
+// Rubles округляет сумму до целых рублей
+func (m Money) Rubles(mode RoundingMode) int64 {
+	return roundRat(big.NewRat(int64(m), int64(Ruble)), mode).Int64()
+}
+
+// String форматирует сумму как "1234.56"
+func (m Money) String() string {
+	sign := ""
+	abs := new(big.Int).Abs(big.NewInt(int64(m)))
+	if m < 0 {
+		sign = "-"
+	}
+	rubles, kopecks := new(big.Int).QuoRem(abs, big.NewInt(int64(Ruble)), new(big.Int))
+	return fmt.Sprintf("%s%s.%02d", sign, rubles, kopecks.Int64())
+}
+
+// Proto переводит сумму в сообщение API
+func (m Money) Proto() *entities.Money {
+	return &entities.Money{
+		Rubles:  int64(m / Ruble),
+		Kopecks: int32(m % Ruble),
+	}
+}
+
+// FromProto переводит сообщение API в сумму, nil считается нулем
+func FromProto(p *entities.Money) Money {
+	if p == nil {
+		return 0
+	}
+	return FromRubles(p.Rubles) + Money(p.Kopecks)
+}
+
+// roundRat округляет дробь до целого по выбранному правилу
+func roundRat(x *big.Rat, mode RoundingMode) *big.Int {
+	num, denom := x.Num(), x.Denom()
+	// Quo округляет к нулю, rem имеет знак делимого
+	quo, rem := new(big.Int).QuoRem(num, denom, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	away := big.NewInt(int64(num.Sign()))
+	switch mode {
+	case Ceil:
+		if num.Sign() > 0 {
+			quo.Add(quo, away)
+		}
+	case Floor:
+		if num.Sign() < 0 {
+			quo.Add(quo, away)
+		}
+	case HalfUp, HalfEven:
+		// сравниваем 2*|rem| с знаменателем
+		twice := new(big.Int).Abs(rem)
+		twice.Lsh(twice, 1)
+		switch cmp := twice.Cmp(denom); {
+		case cmp > 0:
+			quo.Add(quo, away)
+		case cmp == 0 && (mode == HalfUp || quo.Bit(0) == 1):
+			quo.Add(quo, away)
+		}
+	}
+	return quo
+}
@@ -0,0 +1,129 @@
+package money
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestFromRat(t *testing.T) {
+	tests := []struct {
+		name string
+		num  int64
+		den  int64
+		mode RoundingMode
+		want Money
+	}{
+		{"Exact", 12345, 1, HalfEven, 12345},
+		{"HalfEven down to even", 25, 10, HalfEven, 2},
+		{"HalfEven up to even", 35, 10, HalfEven, 4},
+		{"HalfEven above half", 26, 10, HalfEven, 3},
+		{"HalfUp on half", 25, 10, HalfUp, 3},
+		{"HalfUp below half", 24, 10, HalfUp, 2},
+		{"HalfUp negative half", -25, 10, HalfUp, -3},
+		{"HalfEven negative half", -25, 10, HalfEven, -2},
+		{"Ceil small fraction", 201, 100, Ceil, 3},
+		{"Ceil exact", 300, 100, Ceil, 3},
+		{"Ceil negative", -25, 10, Ceil, -2},
+		{"Floor large fraction", 299, 100, Floor, 2},
+		{"Floor negative", -21, 10, Floor, -3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromRat(big.NewRat(tt.num, tt.den), tt.mode)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FromRat(%d/%d) = %d, want %d", tt.num, tt.den, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromRatOverflow(t *testing.T) {
+	huge := new(big.Rat).SetInt64(math.MaxInt64)
+	huge.Add(huge, big.NewRat(1, 2))
+
+	if _, err := FromRat(huge, HalfUp); err != ErrOverflow {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+	// MaxInt64 нечетное, поэтому банковское округление тоже уходит за предел
+	if _, err := FromRat(huge, HalfEven); err != ErrOverflow {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+	below := new(big.Rat).SetInt64(math.MaxInt64)
+	below.Add(below, big.NewRat(1, 4))
+	if got, err := FromRat(below, HalfUp); err != nil || got != math.MaxInt64 {
+		t.Errorf("Expected MaxInt64 without error, got %d, %v", got, err)
+	}
+	if _, err := Money(math.MaxInt64).Mul(big.NewRat(2, 1), HalfUp); err != ErrOverflow {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+
+	if got, err := FromRublesChecked(math.MaxInt64 / 100); err != nil || got != math.MaxInt64/100*100 {
+		t.Errorf("Expected the largest ruble amount without error, got %d, %v", got, err)
+	}
+	for _, rubles := range []int64{math.MaxInt64/100 + 1, 1 << 60, math.MinInt64 / 10} {
+		if _, err := FromRublesChecked(rubles); err != ErrOverflow {
+			t.Errorf("FromRublesChecked(%d): expected ErrOverflow, got %v", rubles, err)
+		}
+	}
+}
+
+func TestMoney(t *testing.T) {
+	m := FromRubles(33457) + 60
+
+	if m.Kopecks() != 3_345_760 {
+		t.Errorf("Kopecks() = %d", m.Kopecks())
+	}
+	if m.String() != "33457.60" {
+		t.Errorf("String() = %s", m.String())
+	}
+	if Money(-5).String() != "-0.05" {
+		t.Errorf("String() = %s", Money(-5).String())
+	}
+	if got := m.Rubles(Ceil); got != 33458 {
+		t.Errorf("Rubles(Ceil) = %d", got)
+	}
+	if got := m.Rubles(HalfUp); got != 33458 {
+		t.Errorf("Rubles(HalfUp) = %d", got)
+	}
+	if got := (FromRubles(10) + 50).Rubles(HalfEven); got != 10 {
+		t.Errorf("Rubles(HalfEven) = %d", got)
+	}
+
+	// 1% от 12 345.67 = 123.4567 -> 123.46
+	interest, err := (FromRubles(12345) + 67).Mul(big.NewRat(1, 100), HalfUp)
+	if err != nil || interest != 12346 {
+		t.Errorf("Mul() = %v, %v", interest, err)
+	}
+
+	if got := FromProto(m.Proto()); got != m {
+		t.Errorf("FromProto(Proto()) = %v, want %v", got, m)
+	}
+	if FromProto(nil) != 0 {
+		t.Error("FromProto(nil) should be zero")
+	}
+}
+
+func TestBasisPoints(t *testing.T) {
+	rate := BasisPoints(850)
+
+	if rate.String() != "8.50" {
+		t.Errorf("String() = %s", rate.String())
+	}
+	if BasisPoints(595).String() != "5.95" {
+		t.Errorf("String() = %s", BasisPoints(595).String())
+	}
+	if rate.Percent() != 8 {
+		t.Errorf("Percent() = %d", rate.Percent())
+	}
+	if rate.Rat().Cmp(big.NewRat(17, 200)) != 0 {
+		t.Errorf("Rat() = %v", rate.Rat())
+	}
+	if rate.Monthly().Cmp(big.NewRat(17, 2400)) != 0 {
+		t.Errorf("Monthly() = %v", rate.Monthly())
+	}
+}
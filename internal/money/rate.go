@@ -0,0 +1,36 @@
+package money
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// BasisPoints — процентная ставка в базисных пунктах: 1 б.п. = 0.01%, 850 = 8.5%
+type BasisPoints int64
+
+// BasisPointsPerUnit — сколько базисных пунктов в ставке 100%
+const BasisPointsPerUnit BasisPoints = 10000
+
+// Rat возвращает ставку как точную долю: 850 -> 17/200
+func (bp BasisPoints) Rat() *big.Rat {
+	return big.NewRat(int64(bp), int64(BasisPointsPerUnit))
+}
+
+// Monthly возвращает месячную ставку как точную долю от годовой
+func (bp BasisPoints) Monthly() *big.Rat {
+	return big.NewRat(int64(bp), int64(BasisPointsPerUnit)*12)
+}
+
+// Percent возвращает ставку в целых процентах с отбрасыванием дробной части (формат API v1)
+func (bp BasisPoints) Percent() int64 {
+	return int64(bp / 100)
+}
+
+// String форматирует ставку в процентах: "8.50"
+func (bp BasisPoints) String() string {
+	sign := ""
+	if bp < 0 {
+		sign, bp = "-", -bp
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, bp/100, bp%100)
+}